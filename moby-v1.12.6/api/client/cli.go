@@ -87,6 +87,16 @@ func (cli *DockerCli) ConfigFile() *configfile.ConfigFile {
 	return cli.configFile
 }
 
+// IsTerminalIn returns true if the clients stdin is a TTY
+func (cli *DockerCli) IsTerminalIn() bool {
+	return cli.isTerminalIn
+}
+
+// InFd returns the fd for the stdin stream
+func (cli *DockerCli) InFd() uintptr {
+	return cli.inFd
+}
+
 // IsTerminalOut returns true if the clients stdin is a TTY
 func (cli *DockerCli) IsTerminalOut() bool {
 	return cli.isTerminalOut
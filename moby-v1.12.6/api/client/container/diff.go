@@ -2,17 +2,21 @@ package container
 
 import (
 	"fmt"
+	"path"
+	"sort"
 
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/cli"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/engine-api/types"
 	"github.com/spf13/cobra"
 )
 
 type diffOptions struct {
-	container string
+	container     string
+	writablePaths bool
 }
 
 // NewDiffCommand creates a new cobra.Command for `docker diff`
@@ -29,6 +33,9 @@ func NewDiffCommand(dockerCli *client.DockerCli) *cobra.Command {
 		},
 	}
 
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.writablePaths, "writable-paths", false, "Print the paths to keep writable to run the container with a read-only root filesystem")
+
 	return cmd
 }
 
@@ -43,6 +50,10 @@ func runDiff(dockerCli *client.DockerCli, opts *diffOptions) error {
 		return err
 	}
 
+	if opts.writablePaths {
+		return printWritablePaths(dockerCli, changes)
+	}
+
 	for _, change := range changes {
 		var kind string
 		switch change.Kind {
@@ -58,3 +69,79 @@ func runDiff(dockerCli *client.DockerCli, opts *diffOptions) error {
 
 	return nil
 }
+
+// printWritablePaths prints the paths which have to be writable for the
+// changes of the container to be made with a read-only root filesystem, and
+// warns about the paths whose image content a tmpfs would hide.
+func printWritablePaths(dockerCli *client.DockerCli, changes []types.ContainerChange) error {
+	kinds := make(map[string]int, len(changes))
+	for _, change := range changes {
+		kinds[change.Path] = change.Kind
+	}
+
+	for _, p := range writablePaths(changes) {
+		if p == "/" {
+			fmt.Fprintln(dockerCli.Err(), "Warning: the container changes files directly under /, which cannot be kept writable")
+			continue
+		}
+		if kind, ok := kinds[p]; !ok || kind != archive.ChangeAdd {
+			fmt.Fprintf(dockerCli.Err(), "Warning: %s exists in the image, its content is hidden when it is kept writable\n", p)
+		}
+		fmt.Fprintln(dockerCli.Out(), p)
+	}
+	return nil
+}
+
+// writablePaths reduces the changes of a container to the sorted list of
+// directories to mount as writable. A file or directory added under
+// directories which were added too makes the top-most of them writable;
+// any other change makes its parent directory writable.
+func writablePaths(changes []types.ContainerChange) []string {
+	kinds := make(map[string]int, len(changes))
+	parents := make(map[string]bool)
+	for _, change := range changes {
+		kinds[change.Path] = change.Kind
+		for dir := path.Dir(change.Path); dir != "/" && dir != "."; dir = path.Dir(dir) {
+			parents[dir] = true
+		}
+	}
+
+	candidates := make(map[string]bool)
+	for _, change := range changes {
+		if parents[change.Path] {
+			continue
+		}
+		p := change.Path
+		for {
+			dir := path.Dir(p)
+			if kind, ok := kinds[dir]; !ok || kind != archive.ChangeAdd || dir == "/" {
+				break
+			}
+			p = dir
+		}
+		if p == change.Path {
+			p = path.Dir(p)
+		}
+		candidates[p] = true
+	}
+
+	var paths []string
+	for p := range candidates {
+		if !underAny(p, candidates) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// underAny returns true if one of the paths other than / is a parent
+// directory of p.
+func underAny(p string, paths map[string]bool) bool {
+	for dir := path.Dir(p); dir != "/" && dir != "."; dir = path.Dir(dir) {
+		if paths[dir] {
+			return true
+		}
+	}
+	return false
+}
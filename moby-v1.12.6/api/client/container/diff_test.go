@@ -0,0 +1,33 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/engine-api/types"
+)
+
+func TestWritablePaths(t *testing.T) {
+	changes := []types.ContainerChange{
+		{Kind: archive.ChangeModify, Path: "/run"},
+		{Kind: archive.ChangeAdd, Path: "/run/app.pid"},
+		{Kind: archive.ChangeModify, Path: "/var"},
+		{Kind: archive.ChangeModify, Path: "/var/cache"},
+		{Kind: archive.ChangeAdd, Path: "/var/cache/app"},
+		{Kind: archive.ChangeAdd, Path: "/var/cache/app/data"},
+		{Kind: archive.ChangeAdd, Path: "/var/cache/app/data/index"},
+		{Kind: archive.ChangeModify, Path: "/tmp"},
+		{Kind: archive.ChangeAdd, Path: "/tmp/sessions"},
+		{Kind: archive.ChangeAdd, Path: "/tmp/sessions/1"},
+		{Kind: archive.ChangeAdd, Path: "/tmp/lock"},
+		{Kind: archive.ChangeModify, Path: "/etc"},
+		{Kind: archive.ChangeDelete, Path: "/etc/motd"},
+	}
+
+	got := writablePaths(changes)
+	want := []string{"/etc", "/run", "/tmp", "/var/cache/app"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("writablePaths() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package container
+
+import (
+	"io/ioutil"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+type seccompProfileOptions struct {
+	container string
+	output    string
+}
+
+// NewSeccompProfileCommand creates a new cobra.Command for `docker seccomp-profile`
+func NewSeccompProfileCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts seccompProfileOptions
+
+	cmd := &cobra.Command{
+		Use:   "seccomp-profile [OPTIONS] CONTAINER",
+		Short: "Print the seccomp profile recorded for a container",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			return runSeccompProfile(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+
+	return cmd
+}
+
+func runSeccompProfile(dockerCli *client.DockerCli, opts seccompProfileOptions) error {
+	profile, err := dockerCli.Client().ContainerSeccompProfile(context.Background(), opts.container)
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "" {
+		_, err := dockerCli.Out().Write(profile)
+		return err
+	}
+	return ioutil.WriteFile(opts.output, profile, 0644)
+}
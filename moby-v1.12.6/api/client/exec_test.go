@@ -44,6 +44,16 @@ func TestParseExec(t *testing.T) {
 			Tty:          true,
 			Cmd:          []string{"command"},
 		},
+		&arguments{
+			[]string{"-e", "FOO=bar", "-w", "/tmp", "--timeout", "10", "container", "command"},
+		}: {
+			Env:          []string{"FOO=bar"},
+			WorkingDir:   "/tmp",
+			Timeout:      10,
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          []string{"command"},
+		},
 		&arguments{
 			[]string{"-d", "container", "command"},
 		}: {
@@ -110,6 +120,20 @@ func compareExecConfig(config1 *types.ExecConfig, config2 *types.ExecConfig) boo
 	if config1.User != config2.User {
 		return false
 	}
+	if config1.WorkingDir != config2.WorkingDir {
+		return false
+	}
+	if config1.Timeout != config2.Timeout {
+		return false
+	}
+	if len(config1.Env) != len(config2.Env) {
+		return false
+	}
+	for index, value := range config1.Env {
+		if value != config2.Env[index] {
+			return false
+		}
+	}
 	if len(config1.Cmd) != len(config2.Cmd) {
 		return false
 	}
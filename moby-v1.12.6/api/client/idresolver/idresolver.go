@@ -45,6 +45,21 @@ func (r *IDResolver) get(ctx context.Context, t interface{}, id string) (string,
 			return id, nil
 		}
 		return service.Spec.Annotations.Name, nil
+	case swarm.Task:
+		// tasks are named after their service, slot and ID like their
+		// containers.
+		task, _, err := r.client.TaskInspectWithRaw(ctx, id)
+		if err != nil {
+			return id, nil
+		}
+		serviceName, err := r.Resolve(ctx, swarm.Service{}, task.ServiceID)
+		if err != nil {
+			return id, nil
+		}
+		if task.Slot > 0 {
+			return fmt.Sprintf("%s.%d.%s", serviceName, task.Slot, task.ID), nil
+		}
+		return fmt.Sprintf("%s.%s", serviceName, task.ID), nil
 	default:
 		return "", fmt.Errorf("unsupported type")
 	}
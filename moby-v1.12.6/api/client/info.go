@@ -64,6 +64,12 @@ func (cli *DockerCli) CmdInfo(args ...string) error {
 	fmt.Fprintf(cli.out, " %s", strings.Join(info.Plugins.Network, " "))
 	fmt.Fprintf(cli.out, "\n")
 
+	if len(info.Plugins.Authentication) != 0 {
+		fmt.Fprintf(cli.out, " Authentication:")
+		fmt.Fprintf(cli.out, " %s", strings.Join(info.Plugins.Authentication, " "))
+		fmt.Fprintf(cli.out, "\n")
+	}
+
 	if len(info.Plugins.Authorization) != 0 {
 		fmt.Fprintf(cli.out, " Authorization:")
 		fmt.Fprintf(cli.out, " %s", strings.Join(info.Plugins.Authorization, " "))
@@ -71,7 +77,7 @@ func (cli *DockerCli) CmdInfo(args ...string) error {
 	}
 
 	fmt.Fprintf(cli.out, "Swarm: %v\n", info.Swarm.LocalNodeState)
-	if info.Swarm.LocalNodeState != swarm.LocalNodeStateInactive {
+	if info.Swarm.LocalNodeState != swarm.LocalNodeStateInactive && info.Swarm.LocalNodeState != swarm.LocalNodeStateLocked {
 		fmt.Fprintf(cli.out, " NodeID: %s\n", info.Swarm.NodeID)
 		if info.Swarm.Error != "" {
 			fmt.Fprintf(cli.out, " Error: %v\n", info.Swarm.Error)
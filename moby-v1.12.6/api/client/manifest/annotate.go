@@ -0,0 +1,141 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+type annotateOptions struct {
+	list       string
+	image      string
+	os         string
+	arch       string
+	variant    string
+	osFeatures []string
+	features   []string
+}
+
+// validOSArches lists the os/arch combinations which may be set on a
+// manifest, following the GOOS/GOARCH values understood by Go.
+var validOSArches = map[string]bool{
+	"darwin/386":      true,
+	"darwin/amd64":    true,
+	"darwin/arm":      true,
+	"darwin/arm64":    true,
+	"freebsd/386":     true,
+	"freebsd/amd64":   true,
+	"freebsd/arm":     true,
+	"linux/386":       true,
+	"linux/amd64":     true,
+	"linux/arm":       true,
+	"linux/arm64":     true,
+	"linux/ppc64":     true,
+	"linux/ppc64le":   true,
+	"linux/mips64":    true,
+	"linux/mips64le":  true,
+	"linux/s390x":     true,
+	"netbsd/386":      true,
+	"netbsd/amd64":    true,
+	"netbsd/arm":      true,
+	"openbsd/386":     true,
+	"openbsd/amd64":   true,
+	"openbsd/arm":     true,
+	"solaris/amd64":   true,
+	"windows/386":     true,
+	"windows/amd64":   true,
+	"dragonfly/amd64": true,
+}
+
+func isValidOSArch(os, arch string) bool {
+	return validOSArches[os+"/"+arch]
+}
+
+func newAnnotateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts annotateOptions
+
+	cmd := &cobra.Command{
+		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
+		Short: "Add platform information to a manifest in a local manifest list",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			opts.image = args[1]
+			return runAnnotate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.os, "os", "", "Set operating system")
+	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
+	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system features")
+	flags.StringSliceVar(&opts.features, "features", []string{}, "Set CPU features")
+
+	return cmd
+}
+
+func runAnnotate(dockerCli *client.DockerCli, opts annotateOptions) error {
+	listRef, err := normalizeReference(opts.list)
+	if err != nil {
+		return err
+	}
+	named, err := normalizeReference(opts.image)
+	if err != nil {
+		return err
+	}
+
+	store := defaultStore()
+	img, err := store.Get(listRef.String(), named.String())
+	if err != nil {
+		return err
+	}
+
+	if err := annotate(&img, opts); err != nil {
+		return err
+	}
+	return store.Save(listRef.String(), img)
+}
+
+// annotate applies the platform options to img.
+func annotate(img *imageManifest, opts annotateOptions) error {
+	platform := &img.Descriptor.Platform
+	if opts.os != "" {
+		platform.OS = opts.os
+	}
+	if opts.arch != "" {
+		platform.Architecture = opts.arch
+	}
+	if opts.variant != "" {
+		platform.Variant = opts.variant
+	}
+	if len(opts.osFeatures) > 0 {
+		platform.OSFeatures = appendUnique(platform.OSFeatures, opts.osFeatures...)
+	}
+	if len(opts.features) > 0 {
+		platform.Features = appendUnique(platform.Features, opts.features...)
+	}
+
+	if !isValidOSArch(platform.OS, platform.Architecture) {
+		return fmt.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", platform.OS, platform.Architecture)
+	}
+	return nil
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
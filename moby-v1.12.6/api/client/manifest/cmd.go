@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cliconfig"
+)
+
+// NewManifestCommand returns a cobra command for `manifest` subcommands
+func NewManifestCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Manage Docker image manifests and manifest lists",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newAnnotateCommand(dockerCli),
+		newCreateCommand(dockerCli),
+		newInspectCommand(dockerCli),
+		newPushCommand(dockerCli),
+	)
+	return cmd
+}
+
+func defaultStore() *manifestStore {
+	return newManifestStore(filepath.Join(cliconfig.ConfigDir(), "manifests"))
+}
@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	amend    bool
+	insecure bool
+	list     string
+	images   []string
+}
+
+func newCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts createOptions
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] MANIFEST_LIST MANIFEST [MANIFEST...]",
+		Short: "Create a local manifest list for annotating and pushing to a registry",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			opts.images = args[1:]
+			return runCreate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+func runCreate(dockerCli *client.DockerCli, opts createOptions) error {
+	listRef, err := normalizeReference(opts.list)
+	if err != nil {
+		return err
+	}
+
+	store := defaultStore()
+	if store.Exists(listRef.String()) && !opts.amend {
+		return fmt.Errorf("refusing to amend an existing manifest list with no --amend flag")
+	}
+
+	ctx := context.Background()
+	var imgs []imageManifest
+	for _, name := range opts.images {
+		named, err := normalizeReference(name)
+		if err != nil {
+			return err
+		}
+		if named.Hostname() != listRef.Hostname() {
+			return fmt.Errorf("manifest %s must be in the same registry as manifest list %s", named.String(), listRef.String())
+		}
+		img, err := fetchImageManifest(ctx, dockerCli, named, opts.insecure)
+		if err != nil {
+			return err
+		}
+		imgs = append(imgs, img)
+	}
+	if err := store.SaveList(listRef.String(), imgs); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", listRef.String())
+	return nil
+}
+
+// normalizeReference parses name, adding the default tag if name carries
+// neither a tag nor a digest.
+func normalizeReference(name string) (reference.Named, error) {
+	named, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithDefaultTag(named), nil
+}
@@ -0,0 +1,116 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	list     string
+	image    string
+	insecure bool
+}
+
+func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] [MANIFEST_LIST] MANIFEST",
+		Short: "Display an image manifest, or manifest list",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch len(args) {
+			case 1:
+				opts.image = args[0]
+			case 2:
+				opts.list = args[0]
+				opts.image = args[1]
+			}
+			return runInspect(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+func runInspect(dockerCli *client.DockerCli, opts inspectOptions) error {
+	named, err := normalizeReference(opts.image)
+	if err != nil {
+		return err
+	}
+	store := defaultStore()
+
+	// A single manifest of a local manifest list
+	if opts.list != "" {
+		listRef, err := normalizeReference(opts.list)
+		if err != nil {
+			return err
+		}
+		img, err := store.Get(listRef.String(), named.String())
+		if err != nil {
+			return err
+		}
+		return printJSON(dockerCli, img.Descriptor)
+	}
+
+	// A local manifest list
+	if store.Exists(named.String()) {
+		imgs, err := store.GetList(named.String())
+		if err != nil {
+			return err
+		}
+		list, err := buildManifestList(imgs)
+		if err != nil {
+			return err
+		}
+		_, payload, err := list.Payload()
+		if err != nil {
+			return err
+		}
+		return printRaw(dockerCli, payload)
+	}
+
+	// A manifest or manifest list in the registry
+	ctx := context.Background()
+	repo, err := getRepository(ctx, dockerCli, named, opts.insecure, "pull")
+	if err != nil {
+		return err
+	}
+	m, err := getManifest(ctx, repo, named)
+	if err != nil {
+		return err
+	}
+	_, payload, err := m.Payload()
+	if err != nil {
+		return err
+	}
+	return printRaw(dockerCli, payload)
+}
+
+func printJSON(dockerCli *client.DockerCli, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), string(data))
+	return nil
+}
+
+func printRaw(dockerCli *client.DockerCli, payload []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, payload, "", "\t"); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), buf.String())
+	return nil
+}
@@ -0,0 +1,106 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/distribution"
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type pushOptions struct {
+	list     string
+	insecure bool
+	purge    bool
+}
+
+func newPushCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts pushOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [OPTIONS] MANIFEST_LIST",
+		Short: "Push a manifest list to a repository",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			return runPush(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.purge, "purge", "p", false, "Remove the local manifest list after push")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow push to an insecure registry")
+
+	return cmd
+}
+
+func runPush(dockerCli *client.DockerCli, opts pushOptions) error {
+	listRef, err := normalizeReference(opts.list)
+	if err != nil {
+		return err
+	}
+	tagged, ok := listRef.(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("manifest list %s must be tagged", listRef.String())
+	}
+
+	store := defaultStore()
+	imgs, err := store.GetList(listRef.String())
+	if err != nil {
+		return err
+	}
+	if len(imgs) == 0 {
+		return fmt.Errorf("manifest list %s is empty", listRef.String())
+	}
+	for _, img := range imgs {
+		platform := img.Descriptor.Platform
+		if !isValidOSArch(platform.OS, platform.Architecture) {
+			return fmt.Errorf("manifest %s has unsupported os/arch combination %s/%s, use `docker manifest annotate` to fix it", img.Ref, platform.OS, platform.Architecture)
+		}
+	}
+
+	ctx := context.Background()
+	repo, err := getRepository(ctx, dockerCli, listRef, opts.insecure, "pull", "push")
+	if err != nil {
+		return err
+	}
+
+	// Image manifests from other repositories must exist in the repository
+	// of the manifest list before the list can reference them.
+	for _, img := range imgs {
+		named, err := reference.ParseNamed(img.Ref)
+		if err != nil {
+			return err
+		}
+		if named.Name() == listRef.Name() {
+			continue
+		}
+		fmt.Fprintf(dockerCli.Out(), "Copying %s to %s\n", img.Ref, listRef.Name())
+		if err := copyImageManifest(ctx, dockerCli, repo, img, opts.insecure); err != nil {
+			return fmt.Errorf("failed to copy %s: %v", img.Ref, err)
+		}
+	}
+
+	list, err := buildManifestList(imgs)
+	if err != nil {
+		return err
+	}
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	dgst, err := manSvc.Put(ctx, list, distribution.WithTag(tagged.Tag()))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "%s: digest: %s\n", tagged.Tag(), dgst)
+
+	if opts.purge {
+		return store.Remove(listRef.String())
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	distreference "github.com/docker/distribution/reference"
+	registryclient "github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/api/client"
+	dockerdistribution "github.com/docker/docker/distribution"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+)
+
+// getRepository returns a client for the repository of named, trying each
+// of the registry's v2 endpoints in turn.
+func getRepository(ctx context.Context, dockerCli *client.DockerCli, named reference.Named, insecure bool, actions ...string) (distribution.Repository, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(named)
+	if err != nil {
+		return nil, err
+	}
+
+	options := registry.ServiceOptions{}
+	if insecure {
+		options.InsecureRegistries = append(options.InsecureRegistries, repoInfo.Hostname())
+	}
+	registryService := registry.NewService(options)
+
+	endpoints, err := registryService.LookupPushEndpoints(repoInfo.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig := dockerCli.ResolveAuthConfig(ctx, repoInfo.Index)
+
+	var lastErr error
+	for _, endpoint := range endpoints {
+		if endpoint.Version == registry.APIVersion1 {
+			continue
+		}
+		repo, _, err := dockerdistribution.NewV2Repository(ctx, repoInfo, endpoint, nil, &authConfig, actions...)
+		if err != nil {
+			logrus.Debugf("Error connecting to %s: %v", endpoint.URL, err)
+			lastErr = err
+			continue
+		}
+		return repo, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no v2 endpoint found for %s", repoInfo.Hostname())
+	}
+	return nil, lastErr
+}
+
+// getManifest fetches the manifest named refers to, either by digest or by
+// tag.
+func getManifest(ctx context.Context, repo distribution.Repository, named reference.Named) (distribution.Manifest, error) {
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if digested, ok := named.(reference.Canonical); ok {
+		return manSvc.Get(ctx, digested.Digest())
+	}
+	tag := reference.DefaultTag
+	if tagged, ok := named.(reference.NamedTagged); ok {
+		tag = tagged.Tag()
+	}
+	return manSvc.Get(ctx, "", distribution.WithTag(tag))
+}
+
+// fetchImageManifest resolves named to a schema2 image manifest and fills
+// in the platform from the image configuration.
+func fetchImageManifest(ctx context.Context, dockerCli *client.DockerCli, named reference.Named, insecure bool) (imageManifest, error) {
+	repo, err := getRepository(ctx, dockerCli, named, insecure, "pull")
+	if err != nil {
+		return imageManifest{}, err
+	}
+	m, err := getManifest(ctx, repo, named)
+	if err != nil {
+		return imageManifest{}, err
+	}
+
+	var mfst *schema2.DeserializedManifest
+	switch v := m.(type) {
+	case *schema2.DeserializedManifest:
+		mfst = v
+	case *manifestlist.DeserializedManifestList:
+		return imageManifest{}, fmt.Errorf("%s is a manifest list, only image manifests can be added to a manifest list", named.String())
+	default:
+		return imageManifest{}, fmt.Errorf("%s uses an unsupported manifest format, push it again with a schema2 capable registry", named.String())
+	}
+
+	mediaType, payload, err := mfst.Payload()
+	if err != nil {
+		return imageManifest{}, err
+	}
+
+	configJSON, err := repo.Blobs(ctx).Get(ctx, mfst.Config.Digest)
+	if err != nil {
+		return imageManifest{}, err
+	}
+	if digest.FromBytes(configJSON) != mfst.Config.Digest {
+		return imageManifest{}, fmt.Errorf("image config verification failed for digest %s", mfst.Config.Digest)
+	}
+	var config image.V1Image
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		return imageManifest{}, err
+	}
+
+	img := imageManifest{
+		Ref: named.String(),
+		Descriptor: manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{
+				MediaType: mediaType,
+				Digest:    digest.FromBytes(payload),
+				Size:      int64(len(payload)),
+			},
+			Platform: manifestlist.PlatformSpec{
+				Architecture: config.Architecture,
+				OS:           config.OS,
+			},
+		},
+		Raw:        payload,
+		References: mfst.References(),
+	}
+	return img, nil
+}
+
+// copyImageManifest makes the image manifest img available in repo, which
+// is the repository of the manifest list. Blobs are mounted from the source
+// repository when the registry allows it, and copied otherwise.
+func copyImageManifest(ctx context.Context, dockerCli *client.DockerCli, repo distribution.Repository, img imageManifest, insecure bool) error {
+	named, err := reference.ParseNamed(img.Ref)
+	if err != nil {
+		return err
+	}
+	srcName, err := distreference.ParseNamed(named.RemoteName())
+	if err != nil {
+		return err
+	}
+
+	var srcRepo distribution.Repository
+	bs := repo.Blobs(ctx)
+	for _, desc := range img.References {
+		if _, err := bs.Stat(ctx, desc.Digest); err == nil {
+			continue
+		}
+
+		canonical, err := distreference.WithDigest(srcName, desc.Digest)
+		if err != nil {
+			return err
+		}
+		w, err := bs.Create(ctx, registryclient.WithMountFrom(canonical))
+		if err != nil {
+			if _, mounted := err.(distribution.ErrBlobMounted); mounted {
+				continue
+			}
+			return err
+		}
+
+		// The registry did not mount the blob, copy it instead.
+		if srcRepo == nil {
+			srcRepo, err = getRepository(ctx, dockerCli, named, insecure, "pull")
+			if err != nil {
+				w.Cancel(ctx)
+				return err
+			}
+		}
+		if err := copyBlob(ctx, srcRepo, w, desc); err != nil {
+			w.Cancel(ctx)
+			return err
+		}
+	}
+
+	var mfst schema2.DeserializedManifest
+	if err := mfst.UnmarshalJSON(img.Raw); err != nil {
+		return err
+	}
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = manSvc.Put(ctx, &mfst)
+	return err
+}
+
+func copyBlob(ctx context.Context, srcRepo distribution.Repository, w distribution.BlobWriter, desc distribution.Descriptor) error {
+	rc, err := srcRepo.Blobs(ctx).Open(ctx, desc.Digest)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if _, err := io.Copy(w, rc); err != nil {
+		return err
+	}
+	_, err = w.Commit(ctx, desc)
+	return err
+}
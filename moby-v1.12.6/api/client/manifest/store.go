@@ -0,0 +1,189 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+// imageManifest is a platform-specific image manifest which has been added
+// to a local manifest list.
+type imageManifest struct {
+	// Ref is the reference the manifest was resolved from.
+	Ref string `json:"ref"`
+	// Descriptor describes the manifest and the platform it applies to.
+	Descriptor manifestlist.ManifestDescriptor `json:"descriptor"`
+	// Raw is the manifest as returned by the registry. It is needed to
+	// push the manifest into the repository of the manifest list when the
+	// image lives in another repository.
+	Raw []byte `json:"raw"`
+	// References lists the blobs (config and layers) of the manifest.
+	References []distribution.Descriptor `json:"references"`
+}
+
+// manifestStore keeps manifest lists which are being assembled on the
+// client before they are pushed. Each list is a directory holding one JSON
+// file per image manifest.
+type manifestStore struct {
+	root string
+}
+
+func newManifestStore(root string) *manifestStore {
+	return &manifestStore{root: root}
+}
+
+// makeFilesafeName turns a reference into a name which can be used as a
+// file or directory name.
+func makeFilesafeName(ref string) string {
+	return strings.NewReplacer("/", "_", ":", "-", "@", "-").Replace(ref)
+}
+
+func (s *manifestStore) listDir(listRef string) string {
+	return filepath.Join(s.root, makeFilesafeName(listRef))
+}
+
+// Exists returns true if a local manifest list exists for listRef.
+func (s *manifestStore) Exists(listRef string) bool {
+	_, err := os.Stat(s.listDir(listRef))
+	return err == nil
+}
+
+// Save adds or replaces an image manifest in the list listRef.
+func (s *manifestStore) Save(listRef string, img imageManifest) error {
+	dir := s.listDir(listRef)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(img)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(dir, makeFilesafeName(img.Ref)), data, 0600)
+}
+
+// SaveList adds or replaces the image manifests in the list listRef, all at
+// once. The list is assembled in a temporary directory which is renamed into
+// place, so that a failure leaves no list, or the list as it was, behind.
+func (s *manifestStore) SaveList(listRef string, imgs []imageManifest) (err error) {
+	if err := os.MkdirAll(s.root, 0700); err != nil {
+		return err
+	}
+	dir := s.listDir(listRef)
+	tmp, err := ioutil.TempDir(s.root, ".tmp-"+filepath.Base(dir))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	files, err := ioutil.ReadDir(dir)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmp, f.Name()), data, 0600); err != nil {
+			return err
+		}
+	}
+	for _, img := range imgs {
+		data, err := json.Marshal(img)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmp, makeFilesafeName(img.Ref)), data, 0600); err != nil {
+			return err
+		}
+	}
+
+	// A directory cannot be renamed over an existing one: move the current
+	// list aside first, and put it back if the new one cannot take its place.
+	old := tmp + ".old"
+	if exists {
+		if err := os.Rename(dir, old); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				os.Rename(old, dir)
+			} else {
+				os.RemoveAll(old)
+			}
+		}()
+	}
+	return os.Rename(tmp, dir)
+}
+
+// Get returns the image manifest imgRef from the list listRef.
+func (s *manifestStore) Get(listRef, imgRef string) (imageManifest, error) {
+	var img imageManifest
+	data, err := ioutil.ReadFile(filepath.Join(s.listDir(listRef), makeFilesafeName(imgRef)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return img, fmt.Errorf("manifest %s is not part of manifest list %s", imgRef, listRef)
+		}
+		return img, err
+	}
+	if err := json.Unmarshal(data, &img); err != nil {
+		return img, err
+	}
+	return img, nil
+}
+
+// GetList returns all image manifests of the list listRef, sorted by
+// reference.
+func (s *manifestStore) GetList(listRef string) ([]imageManifest, error) {
+	files, err := ioutil.ReadDir(s.listDir(listRef))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no such manifest list: %s", listRef)
+		}
+		return nil, err
+	}
+	var imgs []imageManifest
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(s.listDir(listRef), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var img imageManifest
+		if err := json.Unmarshal(data, &img); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s in manifest list %s: %v", f.Name(), listRef, err)
+		}
+		imgs = append(imgs, img)
+	}
+	sort.Sort(byRef(imgs))
+	return imgs, nil
+}
+
+// Remove deletes the local manifest list listRef.
+func (s *manifestStore) Remove(listRef string) error {
+	return os.RemoveAll(s.listDir(listRef))
+}
+
+type byRef []imageManifest
+
+func (r byRef) Len() int           { return len(r) }
+func (r byRef) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byRef) Less(i, j int) bool { return r[i].Ref < r[j].Ref }
+
+// buildManifestList assembles the manifest list from the given image
+// manifests.
+func buildManifestList(imgs []imageManifest) (*manifestlist.DeserializedManifestList, error) {
+	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(imgs))
+	for _, img := range imgs {
+		descriptors = append(descriptors, img.Descriptor)
+	}
+	return manifestlist.FromDescriptors(descriptors)
+}
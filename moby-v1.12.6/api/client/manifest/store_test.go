@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func newTestImageManifest(ref, dgst, arch string) imageManifest {
+	return imageManifest{
+		Ref: ref,
+		Descriptor: manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{
+				MediaType: schema2.MediaTypeManifest,
+				Digest:    digest.Digest("sha256:" + dgst),
+				Size:      1024,
+			},
+			Platform: manifestlist.PlatformSpec{
+				Architecture: arch,
+				OS:           "linux",
+			},
+		},
+	}
+}
+
+func TestManifestStoreSaveAndGetList(t *testing.T) {
+	root, err := ioutil.TempDir("", "manifest-store-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	store := newManifestStore(root)
+	listRef := "example.com/foo/bar:latest"
+	assert.Equal(t, store.Exists(listRef), false)
+
+	assert.NilError(t, store.Save(listRef, newTestImageManifest("example.com/foo/bar:ppc64le", "bb", "ppc64le")))
+	assert.NilError(t, store.Save(listRef, newTestImageManifest("example.com/foo/bar:amd64", "aa", "amd64")))
+	assert.Equal(t, store.Exists(listRef), true)
+
+	imgs, err := store.GetList(listRef)
+	assert.NilError(t, err)
+	assert.Equal(t, len(imgs), 2)
+	assert.Equal(t, imgs[0].Ref, "example.com/foo/bar:amd64")
+	assert.Equal(t, imgs[1].Ref, "example.com/foo/bar:ppc64le")
+
+	img, err := store.Get(listRef, "example.com/foo/bar:ppc64le")
+	assert.NilError(t, err)
+	assert.Equal(t, img.Descriptor.Platform.Architecture, "ppc64le")
+
+	_, err = store.Get(listRef, "example.com/foo/bar:s390x")
+	assert.Error(t, err, "is not part of manifest list")
+
+	list, err := buildManifestList(imgs)
+	assert.NilError(t, err)
+	assert.Equal(t, list.MediaType, manifestlist.MediaTypeManifestList)
+	assert.Equal(t, len(list.Manifests), 2)
+	assert.Equal(t, string(list.Manifests[0].Digest), "sha256:aa")
+
+	assert.NilError(t, store.Remove(listRef))
+	assert.Equal(t, store.Exists(listRef), false)
+	_, err = store.GetList(listRef)
+	assert.Error(t, err, "no such manifest list")
+}
+
+func TestAnnotate(t *testing.T) {
+	img := newTestImageManifest("example.com/foo/bar:arm", "cc", "amd64")
+
+	err := annotate(&img, annotateOptions{arch: "arm", variant: "v7", osFeatures: []string{"a", "b"}})
+	assert.NilError(t, err)
+	assert.Equal(t, img.Descriptor.Platform.Architecture, "arm")
+	assert.Equal(t, img.Descriptor.Platform.Variant, "v7")
+
+	err = annotate(&img, annotateOptions{osFeatures: []string{"b", "c"}})
+	assert.NilError(t, err)
+	assert.EqualStringSlice(t, img.Descriptor.Platform.OSFeatures, []string{"a", "b", "c"})
+
+	err = annotate(&img, annotateOptions{os: "plan9"})
+	assert.Error(t, err, "unsupported os/arch combination: plan9/arm")
+}
+
+func TestManifestStoreSaveList(t *testing.T) {
+	root, err := ioutil.TempDir("", "manifest-store-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	store := newManifestStore(root)
+	listRef := "example.com/foo/bar:latest"
+	assert.NilError(t, store.SaveList(listRef, []imageManifest{
+		newTestImageManifest("example.com/foo/bar:amd64", "aa", "amd64"),
+	}))
+	assert.NilError(t, store.SaveList(listRef, []imageManifest{
+		newTestImageManifest("example.com/foo/bar:amd64", "ab", "amd64"),
+		newTestImageManifest("example.com/foo/bar:ppc64le", "bb", "ppc64le"),
+	}))
+
+	imgs, err := store.GetList(listRef)
+	assert.NilError(t, err)
+	assert.Equal(t, len(imgs), 2)
+	assert.Equal(t, string(imgs[0].Descriptor.Digest), "sha256:ab")
+
+	files, err := ioutil.ReadDir(root)
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 1)
+}
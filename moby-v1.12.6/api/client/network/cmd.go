@@ -22,10 +22,12 @@ func NewNetworkCommand(dockerCli *client.DockerCli) *cobra.Command {
 	cmd.AddCommand(
 		newConnectCommand(dockerCli),
 		newCreateCommand(dockerCli),
+		newDiagnoseCommand(dockerCli),
 		newDisconnectCommand(dockerCli),
 		newInspectCommand(dockerCli),
 		newListCommand(dockerCli),
 		newRemoveCommand(dockerCli),
+		newUpdateCommand(dockerCli),
 	)
 	return cmd
 }
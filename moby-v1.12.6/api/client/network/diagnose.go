@@ -0,0 +1,161 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type diagnoseOptions struct {
+	target string
+	probe  string
+	port   int
+}
+
+func newDiagnoseCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts diagnoseOptions
+
+	cmd := &cobra.Command{
+		Use:   "diagnose [OPTIONS] NETWORK|CONTAINER",
+		Short: "Diagnose the connectivity of a network or a container",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.target = args[0]
+			return runDiagnose(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.probe, "probe", "", "Probe the connectivity from the container to a container or an address")
+	flags.IntVar(&opts.port, "port", 0, "Probe a TCP port instead of sending an ICMP echo request")
+
+	return cmd
+}
+
+func runDiagnose(dockerCli *client.DockerCli, opts diagnoseOptions) error {
+	if opts.port != 0 && opts.probe == "" {
+		return fmt.Errorf("--port can only be used with --probe")
+	}
+
+	options := types.NetworkDiagnoseOptions{
+		Probe:     opts.probe,
+		ProbePort: opts.port,
+	}
+	diagnostics, err := dockerCli.Client().NetworkDiagnose(context.Background(), opts.target, options)
+	if err != nil {
+		return err
+	}
+
+	out := dockerCli.Out()
+	for _, nd := range diagnostics.Networks {
+		printNetworkDiagnostic(out, nd)
+	}
+	for _, cd := range diagnostics.Containers {
+		printContainerDiagnostic(out, cd)
+	}
+	if len(diagnostics.Firewall) > 0 {
+		fmt.Fprintln(out, "Firewall Rules:")
+		printList(out, diagnostics.Firewall)
+	}
+	if diagnostics.Probe != nil {
+		printProbe(out, *diagnostics.Probe)
+	}
+	return nil
+}
+
+func printNetworkDiagnostic(out io.Writer, nd types.NetworkDiagnostic) {
+	fmt.Fprintf(out, "Network:\t\t%s\n", nd.Name)
+	fmt.Fprintf(out, " ID:\t\t\t%s\n", nd.ID)
+	fmt.Fprintf(out, " Driver:\t\t%s\n", nd.Driver)
+	ioutils.FprintfIfNotEmpty(out, " Interface:\t\t%s\n", nd.Interface)
+	ioutils.FprintfIfNotEmpty(out, " Subnets:\t\t%s\n", strings.Join(nd.Subnets, ", "))
+	ioutils.FprintfIfNotEmpty(out, " Gateways:\t\t%s\n", strings.Join(nd.Gateways, ", "))
+}
+
+func printContainerDiagnostic(out io.Writer, cd types.ContainerNetworkDiagnostic) {
+	fmt.Fprintf(out, "Container:\t\t%s\n", cd.Name)
+	fmt.Fprintf(out, " ID:\t\t\t%s\n", cd.ID)
+	fmt.Fprintf(out, " Sandbox:\t\t%s\n", cd.SandboxKey)
+
+	for _, ed := range cd.Endpoints {
+		fmt.Fprintf(out, " Endpoint on %s:\n", ed.Network)
+		fmt.Fprintf(out, "  ID:\t\t\t%s\n", ed.EndpointID)
+		ioutils.FprintfIfNotEmpty(out, "  MAC Address:\t\t%s\n", ed.MacAddress)
+		ioutils.FprintfIfNotEmpty(out, "  IPv4 Address:\t\t%s\n", ed.IPv4Address)
+		ioutils.FprintfIfNotEmpty(out, "  IPv6 Address:\t\t%s\n", ed.IPv6Address)
+		ioutils.FprintfIfNotEmpty(out, "  Gateway:\t\t%s\n", ed.Gateway)
+		ioutils.FprintfIfNotEmpty(out, "  IPv6 Gateway:\t\t%s\n", ed.IPv6Gateway)
+		ioutils.FprintfIfNotEmpty(out, "  Aliases:\t\t%s\n", strings.Join(ed.Aliases, ", "))
+	}
+
+	if len(cd.Interfaces) > 0 {
+		fmt.Fprintln(out, " Interfaces:")
+		for _, id := range cd.Interfaces {
+			state := "down"
+			if id.Up {
+				state = "up"
+			}
+			fmt.Fprintf(out, "  %s (%s, mtu %d", id.Name, state, id.MTU)
+			if id.MacAddress != "" {
+				fmt.Fprintf(out, ", %s", id.MacAddress)
+			}
+			fmt.Fprintln(out, ")")
+			for _, addr := range id.Addresses {
+				fmt.Fprintf(out, "   %s\n", addr)
+			}
+		}
+	}
+	if len(cd.Routes) > 0 {
+		fmt.Fprintln(out, " Routes:")
+		printList(out, cd.Routes)
+	}
+
+	fmt.Fprintln(out, " DNS:")
+	ioutils.FprintfIfNotEmpty(out, "  Nameservers:\t\t%s\n", strings.Join(cd.Nameservers, ", "))
+	ioutils.FprintfIfNotEmpty(out, "  Search:\t\t%s\n", strings.Join(cd.Search, ", "))
+	ioutils.FprintfIfNotEmpty(out, "  Options:\t\t%s\n", strings.Join(cd.DNSOptions, ", "))
+	if cd.EmbeddedDNS {
+		fmt.Fprintln(out, "  Embedded Server:\tYes")
+		ioutils.FprintfIfNotEmpty(out, "  External Servers:\t%s\n", strings.Join(cd.ExternalDNS, ", "))
+		for _, record := range cd.DNSRecords {
+			fmt.Fprintf(out, "  %s -> %s\n", record.Name, strings.Join(record.Addresses, ", "))
+		}
+	} else {
+		fmt.Fprintln(out, "  Embedded Server:\tNo")
+	}
+
+	if len(cd.Hosts) > 0 {
+		fmt.Fprintln(out, " Hosts:")
+		printList(out, cd.Hosts)
+	}
+	if len(cd.Ports) > 0 {
+		fmt.Fprintln(out, " Ports:")
+		printList(out, cd.Ports)
+	}
+}
+
+func printProbe(out io.Writer, probe types.NetworkProbe) {
+	fmt.Fprintf(out, "Probe:\t\t\t%s -> %s (%s)\n", probe.Source, probe.Target, probe.Address)
+	fmt.Fprintf(out, " Protocol:\t\t%s\n", probe.Protocol)
+	reachable := "No"
+	if probe.Reachable {
+		reachable = "Yes"
+	}
+	fmt.Fprintf(out, " Reachable:\t\t%s\n", reachable)
+	ioutils.FprintfIfNotEmpty(out, " Result:\t\t%s\n", probe.Result)
+	ioutils.FprintfIfNotEmpty(out, " Round-trip Time:\t%s\n", probe.RTT)
+}
+
+func printList(out io.Writer, lines []string) {
+	for _, line := range lines {
+		fmt.Fprintf(out, " - %s\n", line)
+	}
+}
@@ -0,0 +1,111 @@
+package network
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type updateOptions struct {
+	network     string
+	labelAdd    []string
+	labelRemove []string
+	driverOpts  opts.MapOpts
+	auxRemove   []string
+
+	attachable        bool
+	attachableChanged bool
+
+	ipamSubnet  []string
+	ipamIPRange []string
+	ipamGateway []string
+	ipamAux     opts.MapOpts
+}
+
+func newUpdateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := updateOptions{
+		driverOpts: *opts.NewMapOpts(nil, nil),
+		ipamAux:    *opts.NewMapOpts(nil, nil),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "update [OPTIONS] NETWORK",
+		Short: "Update the labels, driver options or address pools of a network",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.network = args[0]
+			opts.attachableChanged = cmd.Flags().Changed("attachable")
+			return runUpdate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.labelAdd, "label-add", []string{}, "Add or update a label of the network")
+	flags.StringSliceVar(&opts.labelRemove, "label-rm", []string{}, "Remove a label of the network")
+	flags.VarP(&opts.driverOpts, "opt", "o", "Change driver specific options")
+	flags.BoolVar(&opts.attachable, "attachable", true, "Allow containers to be manually connected to the network")
+
+	flags.StringSliceVar(&opts.ipamSubnet, "subnet", []string{}, "Subnet in CIDR format of the address pool to add")
+	flags.StringSliceVar(&opts.ipamIPRange, "ip-range", []string{}, "Allocate container ip from a sub-range")
+	flags.StringSliceVar(&opts.ipamGateway, "gateway", []string{}, "IPv4 or IPv6 Gateway for the master subnet")
+	flags.Var(&opts.ipamAux, "aux-address", "Auxiliary IPv4 or IPv6 addresses used by Network driver")
+	flags.StringSliceVar(&opts.auxRemove, "aux-address-rm", []string{}, "Remove an auxiliary address")
+
+	return cmd
+}
+
+func runUpdate(dockerCli *client.DockerCli, opts updateOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	// Without a subnet, auxiliary addresses are reserved in the existing
+	// address pools.
+	auxAddresses := opts.ipamAux.GetAll()
+	update := types.NetworkUpdate{
+		Options:            opts.driverOpts.GetAll(),
+		RemoveAuxAddresses: opts.auxRemove,
+	}
+	if len(opts.ipamSubnet) == 0 {
+		update.AuxAddresses = auxAddresses
+		auxAddresses = nil
+	}
+	if opts.attachableChanged {
+		update.Attachable = &opts.attachable
+	}
+
+	ipamCfg, err := consolidateIpam(opts.ipamSubnet, opts.ipamIPRange, opts.ipamGateway, auxAddresses)
+	if err != nil {
+		return err
+	}
+	update.IPAMConfig = ipamCfg
+
+	if len(opts.labelAdd) > 0 || len(opts.labelRemove) > 0 {
+		nw, err := client.NetworkInspect(ctx, opts.network, types.NetworkInspectOptions{})
+		if err != nil {
+			return err
+		}
+		update.Labels = make(map[string]string, len(nw.Labels))
+		for k, v := range nw.Labels {
+			update.Labels[k] = v
+		}
+		for k, v := range runconfigopts.ConvertKVStringsToMap(opts.labelAdd) {
+			update.Labels[k] = v
+		}
+		for _, k := range opts.labelRemove {
+			delete(update.Labels, k)
+		}
+	}
+
+	if err := client.NetworkUpdate(ctx, opts.network, update); err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "%s\n", opts.network)
+	return nil
+}
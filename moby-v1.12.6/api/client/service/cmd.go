@@ -24,6 +24,7 @@ func NewServiceCommand(dockerCli *client.DockerCli) *cobra.Command {
 		newInspectCommand(dockerCli),
 		newPSCommand(dockerCli),
 		newListCommand(dockerCli),
+		newLogsCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		newScaleCommand(dockerCli),
 		newUpdateCommand(dockerCli),
@@ -107,7 +107,7 @@ func printService(out io.Writer, service swarm.Service) {
 		fmt.Fprintln(out, "Update status:")
 		fmt.Fprintf(out, " State:\t\t%s\n", service.UpdateStatus.State)
 		fmt.Fprintf(out, " Started:\t%s ago\n", strings.ToLower(units.HumanDuration(time.Since(service.UpdateStatus.StartedAt))))
-		if service.UpdateStatus.State == swarm.UpdateStateCompleted {
+		if service.UpdateStatus.State == swarm.UpdateStateCompleted || service.UpdateStatus.State == swarm.UpdateStateRollbackCompleted {
 			fmt.Fprintf(out, " Completed:\t%s ago\n", strings.ToLower(units.HumanDuration(time.Since(service.UpdateStatus.CompletedAt))))
 		}
 		fmt.Fprintf(out, " Message:\t%s\n", service.UpdateStatus.Message)
@@ -124,6 +124,10 @@ func printService(out io.Writer, service swarm.Service) {
 			fmt.Fprintf(out, " Delay:\t\t%s\n", service.Spec.UpdateConfig.Delay)
 		}
 		fmt.Fprintf(out, " On failure:\t%s\n", service.Spec.UpdateConfig.FailureAction)
+		if service.Spec.UpdateConfig.Monitor.Nanoseconds() > 0 {
+			fmt.Fprintf(out, " Monitoring Period:\t%s\n", service.Spec.UpdateConfig.Monitor)
+		}
+		fmt.Fprintf(out, " Max failure ratio:\t%g\n", service.Spec.UpdateConfig.MaxFailureRatio)
 	}
 
 	fmt.Fprintf(out, "ContainerSpec:\n")
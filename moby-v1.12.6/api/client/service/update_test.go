@@ -3,6 +3,7 @@ package service
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/testutil/assert"
 	"github.com/docker/engine-api/types/swarm"
@@ -20,6 +21,21 @@ func TestUpdateServiceArgs(t *testing.T) {
 	assert.EqualStringSlice(t, cspec.Args, []string{"the", "new args"})
 }
 
+func TestUpdateServiceUpdateConfig(t *testing.T) {
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set("update-failure-action", "rollback")
+	flags.Set("update-monitor", "20s")
+	flags.Set("update-max-failure-ratio", "0.5")
+
+	spec := &swarm.ServiceSpec{UpdateConfig: &swarm.UpdateConfig{Parallelism: 2}}
+
+	updateService(flags, spec)
+	assert.Equal(t, spec.UpdateConfig.Parallelism, uint64(2))
+	assert.Equal(t, spec.UpdateConfig.FailureAction, swarm.UpdateFailureActionRollback)
+	assert.Equal(t, spec.UpdateConfig.Monitor, 20*time.Second)
+	assert.Equal(t, spec.UpdateConfig.MaxFailureRatio, float32(0.5))
+}
+
 func TestUpdateLabels(t *testing.T) {
 	flags := newUpdateCommand(nil).Flags()
 	flags.Set("label-add", "toadd=newlabel")
@@ -0,0 +1,68 @@
+package swarm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/pkg/term"
+	"github.com/docker/engine-api/types/swarm"
+)
+
+func newUnlockCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Unlock swarm",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := dockerCli.Client()
+			ctx := context.Background()
+
+			key, err := readKey(dockerCli, "Please enter unlock key: ")
+			if err != nil {
+				return err
+			}
+			req := swarm.UnlockRequest{
+				UnlockKey: key,
+			}
+
+			return client.SwarmUnlock(ctx, req)
+		},
+	}
+
+	return cmd
+}
+
+// readKey reads the unlock key from stdin, without echoing it when stdin is
+// a terminal.
+func readKey(dockerCli *client.DockerCli, prompt string) (string, error) {
+	if dockerCli.IsTerminalIn() {
+		fmt.Fprint(dockerCli.Out(), prompt)
+		oldState, err := term.SaveState(dockerCli.InFd())
+		if err != nil {
+			return "", err
+		}
+		term.DisableEcho(dockerCli.InFd(), oldState)
+		defer func() {
+			fmt.Fprintln(dockerCli.Out())
+			term.RestoreTerminal(dockerCli.InFd(), oldState)
+		}()
+	}
+
+	key, err := bufio.NewReader(dockerCli.In()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", errors.New("unlock key is required")
+	}
+	return key, nil
+}
@@ -39,6 +39,8 @@ func runUpdate(dockerCli *client.DockerCli, flags *pflag.FlagSet, opts swarmOpti
 		return err
 	}
 
+	prevAutoLock := swarm.Spec.EncryptionConfig.AutoLockManagers
+
 	err = mergeSwarm(&swarm, flags)
 	if err != nil {
 		return err
@@ -51,6 +53,14 @@ func runUpdate(dockerCli *client.DockerCli, flags *pflag.FlagSet, opts swarmOpti
 
 	fmt.Fprintln(dockerCli.Out(), "Swarm updated.")
 
+	if swarm.Spec.EncryptionConfig.AutoLockManagers && !prevAutoLock {
+		unlockKeyResp, err := client.SwarmGetUnlockKey(ctx)
+		if err != nil {
+			return fmt.Errorf("could not fetch unlock key: %v", err)
+		}
+		printUnlockCommand(dockerCli, unlockKeyResp.UnlockKey)
+	}
+
 	return nil
 }
 
@@ -78,5 +88,9 @@ func mergeSwarm(swarm *swarm.Swarm, flags *pflag.FlagSet) error {
 		spec.CAConfig.ExternalCAs = value.Value()
 	}
 
+	if flags.Changed(flagAutolock) {
+		spec.EncryptionConfig.AutoLockManagers, _ = flags.GetBool(flagAutolock)
+	}
+
 	return nil
 }
@@ -28,6 +28,10 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	flMemoryReservation := cmd.String([]string{"-memory-reservation"}, "", "Memory soft limit")
 	flMemorySwap := cmd.String([]string{"-memory-swap"}, "", "Swap limit equal to memory plus swap: '-1' to enable unlimited swap")
 	flKernelMemory := cmd.String([]string{"-kernel-memory"}, "", "Kernel memory limit")
+	flNetIngressRate := cmd.String([]string{"-network-ingress-rate"}, "", "Limit the rate (bytes per second) of the traffic received by the container: '-1' to remove the limit")
+	flNetIngressBurst := cmd.String([]string{"-network-ingress-burst"}, "", "Burst size of the network ingress rate limit")
+	flNetEgressRate := cmd.String([]string{"-network-egress-rate"}, "", "Limit the rate (bytes per second) of the traffic sent by the container: '-1' to remove the limit")
+	flNetEgressBurst := cmd.String([]string{"-network-egress-burst"}, "", "Burst size of the network egress rate limit")
 	flRestartPolicy := cmd.String([]string{"-restart"}, "", "Restart policy to apply when a container exits")
 
 	cmd.Require(flag.Min, 1)
@@ -73,6 +77,15 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 		}
 	}
 
+	netIngressRate, netIngressBurst, err := opts.ParseNetworkLimit(*flNetIngressRate, *flNetIngressBurst)
+	if err != nil {
+		return err
+	}
+	netEgressRate, netEgressBurst, err := opts.ParseNetworkLimit(*flNetEgressRate, *flNetEgressBurst)
+	if err != nil {
+		return err
+	}
+
 	var restartPolicy container.RestartPolicy
 	if *flRestartPolicy != "" {
 		restartPolicy, err = opts.ParseRestartPolicy(*flRestartPolicy)
@@ -82,16 +95,20 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	}
 
 	resources := container.Resources{
-		BlkioWeight:       *flBlkioWeight,
-		CpusetCpus:        *flCpusetCpus,
-		CpusetMems:        *flCpusetMems,
-		CPUShares:         *flCPUShares,
-		Memory:            flMemory,
-		MemoryReservation: memoryReservation,
-		MemorySwap:        memorySwap,
-		KernelMemory:      kernelMemory,
-		CPUPeriod:         *flCPUPeriod,
-		CPUQuota:          *flCPUQuota,
+		BlkioWeight:         *flBlkioWeight,
+		CpusetCpus:          *flCpusetCpus,
+		CpusetMems:          *flCpusetMems,
+		CPUShares:           *flCPUShares,
+		Memory:              flMemory,
+		MemoryReservation:   memoryReservation,
+		MemorySwap:          memorySwap,
+		KernelMemory:        kernelMemory,
+		NetworkIngressRate:  netIngressRate,
+		NetworkIngressBurst: netIngressBurst,
+		NetworkEgressRate:   netEgressRate,
+		NetworkEgressBurst:  netEgressBurst,
+		CPUPeriod:           *flCPUPeriod,
+		CPUQuota:            *flCPUQuota,
 	}
 
 	updateConfig := container.UpdateConfig{
@@ -0,0 +1,75 @@
+package volume
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type cloneOptions struct {
+	source     string
+	name       string
+	driverOpts opts.MapOpts
+	labels     []string
+}
+
+func newCloneCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := cloneOptions{
+		driverOpts: *opts.NewMapOpts(nil, nil),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "clone [OPTIONS] VOLUME NAME",
+		Short: "Create a volume as a copy of another volume",
+		Long:  cloneDescription,
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			opts.name = args[1]
+			return runClone(dockerCli, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.VarP(&opts.driverOpts, "opt", "o", "Set driver specific options")
+	flags.StringSliceVar(&opts.labels, "label", []string{}, "Set metadata for the new volume")
+
+	return cmd
+}
+
+func runClone(dockerCli *client.DockerCli, opts cloneOptions) error {
+	client := dockerCli.Client()
+
+	cloneReq := types.VolumeCloneRequest{
+		Name:       opts.name,
+		DriverOpts: opts.driverOpts.GetAll(),
+		Labels:     runconfigopts.ConvertKVStringsToMap(opts.labels),
+	}
+
+	vol, err := client.VolumeClone(context.Background(), opts.source, cloneReq)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", vol.Name)
+	return nil
+}
+
+var cloneDescription = `
+Creates a new volume holding a copy of the content of an existing volume. The
+new volume uses the driver of the existing volume:
+
+    $ docker volume clone db-data db-data-backup
+    db-data-backup
+
+Volume drivers supporting snapshots copy the volume on their own. Otherwise,
+Docker creates a new volume and copies the files of the existing volume into
+it.
+
+`
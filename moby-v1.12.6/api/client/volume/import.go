@@ -0,0 +1,99 @@
+package volume
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	apiclient "github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type importOptions struct {
+	volume     string
+	source     string
+	driver     string
+	driverOpts opts.MapOpts
+	labels     []string
+}
+
+func newImportCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := importOptions{
+		driverOpts: *opts.NewMapOpts(nil, nil),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import [OPTIONS] VOLUME file|-",
+		Short: "Import the content of a tar archive into a volume",
+		Long:  importDescription,
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			opts.source = args[1]
+			return runImport(dockerCli, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.driver, "driver", "d", "local", "Specify the volume driver name, if the volume is created")
+	flags.VarP(&opts.driverOpts, "opt", "o", "Set driver specific options, if the volume is created")
+	flags.StringSliceVar(&opts.labels, "label", []string{}, "Set metadata for the volume, if it is created")
+
+	return cmd
+}
+
+func runImport(dockerCli *client.DockerCli, opts importOptions) error {
+	var in io.Reader
+	if opts.source == "-" {
+		if dockerCli.IsTerminalIn() {
+			return errors.New("Cowardly refusing to read an archive from a terminal. Give a file or redirect.")
+		}
+		in = dockerCli.In()
+	} else {
+		file, err := os.Open(opts.source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	if _, err := client.VolumeInspect(ctx, opts.volume, types.VolumeInspectOptions{}); err != nil {
+		if !apiclient.IsErrVolumeNotFound(err) {
+			return err
+		}
+		volReq := types.VolumeCreateRequest{
+			Name:       opts.volume,
+			Driver:     opts.driver,
+			DriverOpts: opts.driverOpts.GetAll(),
+			Labels:     runconfigopts.ConvertKVStringsToMap(opts.labels),
+		}
+		if _, err := client.VolumeCreate(ctx, volReq); err != nil {
+			return err
+		}
+	}
+
+	return client.VolumeImport(ctx, opts.volume, in)
+}
+
+var importDescription = `
+Extracts a tar archive into a volume, for example to restore the content of a
+volume exported with **docker volume export**:
+
+    $ docker volume import db-data db-data.tar
+
+The archive may be compressed with gzip, bzip2 or xz. Files of the volume which
+are also in the archive are replaced, other files are kept. The volume is
+created if it does not exist, with the driver, options and labels given with
+the **--driver**, **--opt** and **--label** flags.
+
+`
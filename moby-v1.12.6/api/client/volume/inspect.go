@@ -6,12 +6,14 @@ import (
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/api/client/inspect"
 	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
 	"github.com/spf13/cobra"
 )
 
 type inspectOptions struct {
 	format string
 	names  []string
+	size   bool
 }
 
 func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
@@ -29,6 +31,7 @@ func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&opts.format, "format", "f", "", "Format the output using the given go template")
+	cmd.Flags().BoolVarP(&opts.size, "size", "s", false, "Display the size of the volumes")
 
 	return cmd
 }
@@ -39,7 +42,7 @@ func runInspect(dockerCli *client.DockerCli, opts inspectOptions) error {
 	ctx := context.Background()
 
 	getVolFunc := func(name string) (interface{}, []byte, error) {
-		i, err := client.VolumeInspect(ctx, name)
+		i, err := client.VolumeInspect(ctx, name, types.VolumeInspectOptions{Size: opts.size})
 		return i, nil, err
 	}
 
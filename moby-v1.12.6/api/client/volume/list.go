@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/docker/docker/cli"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +26,7 @@ func (r byVolumeName) Less(i, j int) bool {
 
 type listOptions struct {
 	quiet  bool
+	size   bool
 	filter []string
 }
 
@@ -43,6 +46,7 @@ func newListCommand(dockerCli *client.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display volume names")
+	flags.BoolVarP(&opts.size, "size", "s", false, "Display the size of the volumes")
 	flags.StringSliceVarP(&opts.filter, "filter", "f", []string{}, "Provide filter values (i.e. 'dangling=true')")
 
 	return cmd
@@ -60,7 +64,11 @@ func runList(dockerCli *client.DockerCli, opts listOptions) error {
 		}
 	}
 
-	volumes, err := client.VolumeList(context.Background(), volFilterArgs)
+	options := types.VolumeListOptions{
+		Size:   opts.size,
+		Filter: volFilterArgs,
+	}
+	volumes, err := client.VolumeList(context.Background(), options)
 	if err != nil {
 		return err
 	}
@@ -71,6 +79,9 @@ func runList(dockerCli *client.DockerCli, opts listOptions) error {
 			fmt.Fprintln(dockerCli.Err(), warn)
 		}
 		fmt.Fprintf(w, "DRIVER \tVOLUME NAME")
+		if opts.size {
+			fmt.Fprintf(w, "\tSIZE\tLAST USED")
+		}
 		fmt.Fprintf(w, "\n")
 	}
 
@@ -80,12 +91,41 @@ func runList(dockerCli *client.DockerCli, opts listOptions) error {
 			fmt.Fprintln(w, vol.Name)
 			continue
 		}
-		fmt.Fprintf(w, "%s\t%s\n", vol.Driver, vol.Name)
+		fmt.Fprintf(w, "%s\t%s", vol.Driver, vol.Name)
+		if opts.size {
+			fmt.Fprintf(w, "\t%s\t%s", volumeSize(vol), volumeLastUsed(vol))
+		}
+		fmt.Fprintf(w, "\n")
 	}
 	w.Flush()
 	return nil
 }
 
+// volumeSize returns the size of the volume for humans, or N/A if the driver
+// of the volume cannot report it.
+func volumeSize(vol *types.Volume) string {
+	if vol.UsageData == nil || vol.UsageData.Size < 0 {
+		return "N/A"
+	}
+	return units.HumanSize(float64(vol.UsageData.Size))
+}
+
+// volumeLastUsed returns how long ago the volume was last used, "in use" if
+// containers reference it, or N/A if it is not known.
+func volumeLastUsed(vol *types.Volume) string {
+	if vol.UsageData == nil {
+		return "N/A"
+	}
+	if len(vol.UsageData.Containers) > 0 {
+		return "in use"
+	}
+	lastUsed, err := time.Parse(time.RFC3339Nano, vol.UsageData.LastUsedAt)
+	if err != nil {
+		return "N/A"
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(lastUsed)) + " ago"
+}
+
 var listDescription = `
 
 Lists all the volumes Docker knows about. You can filter using the **-f** or
@@ -0,0 +1,348 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/pkg/authentication"
+	"github.com/docker/docker/pkg/ioutils"
+	"golang.org/x/net/context"
+)
+
+// Audit levels, from the least to the most verbose.
+const (
+	// AuditLevelNone records nothing.
+	AuditLevelNone = "none"
+	// AuditLevelWrite records requests which may modify the daemon state,
+	// that is all requests except GET and HEAD requests.
+	AuditLevelWrite = "write"
+	// AuditLevelMetadata records all requests.
+	AuditLevelMetadata = "metadata"
+	// AuditLevelRequest records all requests, with a redacted summary of
+	// the body of requests which may modify the daemon state.
+	AuditLevelRequest = "request"
+)
+
+// AuditGroups lists the groups of endpoints which audit levels can be set for.
+var AuditGroups = []string{"containers", "exec", "images", "volumes", "networks", "swarm", "plugins", "system"}
+
+const (
+	// maxAuditBodySize is the size of the largest request body summarized
+	// in audit records.
+	maxAuditBodySize = 65536 // 64KB
+	// maxAuditResponseSize is the size of the largest response body
+	// inspected for the IDs of created objects.
+	maxAuditResponseSize = 4096 // 4KB
+	// maxAuditStringSize is the size of the longest string kept in body
+	// summaries.
+	maxAuditStringSize = 256
+)
+
+var (
+	auditVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+	auditExecPath      = regexp.MustCompile(`^/containers/[^/]+/exec$`)
+)
+
+// AuditRecord is the record of an API request written to the audit log.
+type AuditRecord struct {
+	Time        time.Time
+	User        string   `json:",omitempty"`
+	Groups      []string `json:",omitempty"`
+	AuthNMethod string   `json:",omitempty"`
+	RemoteAddr  string
+	Method      string
+	Path        string
+	Query       string `json:",omitempty"`
+	Status      int
+	Duration    string
+	// Objects are the names or IDs of the objects the request applies to,
+	// and of the objects it created.
+	Objects []string `json:",omitempty"`
+	// Body is a summary of the request body, with secrets redacted.
+	Body  interface{} `json:",omitempty"`
+	Error string      `json:",omitempty"`
+}
+
+// AuditMiddleware writes a record of each API request to an audit log.
+// It must run after the authentication middleware to record the user of
+// each request.
+type AuditMiddleware struct {
+	mu           sync.Mutex
+	w            io.Writer
+	defaultLevel string
+	levels       map[string]string
+}
+
+// NewAuditMiddleware creates a new AuditMiddleware writing records to w,
+// one JSON object per write. levels holds the audit level of all endpoints
+// ("level") or of a group of endpoints ("group=level"), and defaults to
+// AuditLevelMetadata.
+func NewAuditMiddleware(w io.Writer, levels []string) (*AuditMiddleware, error) {
+	m := &AuditMiddleware{
+		w:            w,
+		defaultLevel: AuditLevelMetadata,
+		levels:       make(map[string]string),
+	}
+	for _, l := range levels {
+		group, level := "", l
+		if i := strings.Index(l, "="); i >= 0 {
+			group, level = l[:i], l[i+1:]
+		}
+		if err := validateAuditLevel(level); err != nil {
+			return nil, err
+		}
+		if group == "" {
+			m.defaultLevel = level
+			continue
+		}
+		if !isAuditGroup(group) {
+			return nil, fmt.Errorf("invalid audit endpoint group %q, must be one of %s", group, strings.Join(AuditGroups, ", "))
+		}
+		m.levels[group] = level
+	}
+	return m, nil
+}
+
+func validateAuditLevel(level string) error {
+	switch level {
+	case AuditLevelNone, AuditLevelWrite, AuditLevelMetadata, AuditLevelRequest:
+		return nil
+	}
+	return fmt.Errorf("invalid audit level %q, must be one of %s, %s, %s or %s", level, AuditLevelNone, AuditLevelWrite, AuditLevelMetadata, AuditLevelRequest)
+}
+
+func isAuditGroup(group string) bool {
+	for _, g := range AuditGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// auditGroup returns the group of the endpoint at path, without the API
+// version prefix.
+func auditGroup(path string) string {
+	if auditExecPath.MatchString(path) {
+		return "exec"
+	}
+	segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	switch segment {
+	case "containers", "exec", "volumes", "networks", "plugins":
+		return segment
+	case "images", "build", "commit":
+		return "images"
+	case "swarm", "nodes", "services", "tasks":
+		return "swarm"
+	}
+	return "system"
+}
+
+func (m *AuditMiddleware) level(group string) string {
+	if level, ok := m.levels[group]; ok {
+		return level
+	}
+	return m.defaultLevel
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m *AuditMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		path := auditVersionPrefix.ReplaceAllString(r.URL.Path, "")
+		level := m.level(auditGroup(path))
+		write := r.Method != "GET" && r.Method != "HEAD"
+		if level == AuditLevelNone || (level == AuditLevelWrite && !write) {
+			return handler(ctx, w, r, vars)
+		}
+
+		record := &AuditRecord{
+			Time:       time.Now().UTC(),
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			Path:       path,
+			Query:      r.URL.RawQuery,
+		}
+		if user := authentication.UserFromContext(ctx); user != nil {
+			record.User = user.Name
+			record.Groups = user.Groups
+			record.AuthNMethod = user.AuthNMethod
+		}
+		for _, k := range []string{"name", "id"} {
+			if v := vars[k]; v != "" {
+				record.Objects = append(record.Objects, v)
+			}
+		}
+		if level == AuditLevelRequest && write {
+			record.Body = summarizeRequestBody(r)
+		}
+
+		aw := &auditResponseWriter{ResponseWriter: w, captureBody: write}
+		err := handler(ctx, aw, r, vars)
+
+		record.Duration = time.Since(record.Time).String()
+		switch {
+		case err != nil:
+			record.Status = httputils.GetHTTPErrorStatusCode(err)
+			record.Error = err.Error()
+		case aw.status != 0:
+			record.Status = aw.status
+		case aw.hijacked:
+			record.Status = http.StatusSwitchingProtocols
+		default:
+			record.Status = http.StatusOK
+		}
+		if id := aw.createdID(); id != "" {
+			record.Objects = append(record.Objects, id)
+		}
+
+		m.write(record)
+		return err
+	}
+}
+
+func (m *AuditMiddleware) write(record *AuditRecord) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		logrus.Errorf("Error encoding audit record for %s %s: %v", record.Method, record.Path, err)
+		return
+	}
+	b = append(b, '\n')
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.w.Write(b); err != nil {
+		logrus.Errorf("Error writing audit record for %s %s: %v", record.Method, record.Path, err)
+	}
+}
+
+// summarizeRequestBody returns the JSON body of r with secrets redacted
+// and long strings truncated, without consuming it.
+func summarizeRequestBody(r *http.Request) interface{} {
+	if r.ContentLength <= 0 || r.ContentLength > maxAuditBodySize {
+		return nil
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return nil
+	}
+
+	body := r.Body
+	bufReader := bufio.NewReaderSize(body, maxAuditBodySize)
+	r.Body = ioutils.NewReadCloserWrapper(bufReader, func() error { return body.Close() })
+
+	b, err := bufReader.Peek(maxAuditBodySize)
+	if err != io.EOF {
+		return nil
+	}
+
+	var summary interface{}
+	if err := json.Unmarshal(b, &summary); err != nil {
+		return nil
+	}
+	maskSecretKeys(summary)
+	return redactAuditValue("", summary)
+}
+
+// redactAuditValue truncates long strings and hides the values of
+// environment variables, which commonly hold credentials.
+func redactAuditValue(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			v[k] = redactAuditValue(k, value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			if s, ok := value.(string); ok && strings.EqualFold(key, "Env") {
+				if j := strings.Index(s, "="); j >= 0 {
+					v[i] = s[:j] + "=*****"
+				}
+				continue
+			}
+			v[i] = redactAuditValue(key, value)
+		}
+	case string:
+		if len(v) > maxAuditStringSize {
+			return v[:maxAuditStringSize] + "..."
+		}
+	}
+	return v
+}
+
+// auditResponseWriter records the status of a response and keeps the
+// beginning of its body, to find the IDs of created objects.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	hijacked    bool
+	captureBody bool
+	body        bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.captureBody && w.body.Len() < maxAuditResponseSize {
+		n := maxAuditResponseSize - w.body.Len()
+		if n > len(b) {
+			n = len(b)
+		}
+		w.body.Write(b[:n])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("Internal response writer doesn't support the Hijacker interface")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+func (w *auditResponseWriter) CloseNotify() <-chan bool {
+	if closeNotifier, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return closeNotifier.CloseNotify()
+	}
+	logrus.Error("Internal response writer doesn't support the CloseNotifier interface")
+	return nil
+}
+
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// createdID returns the ID of the object created by the request, if any.
+func (w *auditResponseWriter) createdID() string {
+	if w.status != http.StatusCreated || w.body.Len() == 0 {
+		return ""
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := json.Unmarshal(w.body.Bytes(), &created); err != nil {
+		return ""
+	}
+	return created.ID
+}
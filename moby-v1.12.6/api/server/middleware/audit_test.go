@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/authentication"
+	"golang.org/x/net/context"
+)
+
+func TestAuditLevels(t *testing.T) {
+	if _, err := NewAuditMiddleware(nil, []string{"verbose"}); err == nil {
+		t.Fatal("expected an error for an invalid level")
+	}
+	if _, err := NewAuditMiddleware(nil, []string{"dummy=write"}); err == nil {
+		t.Fatal("expected an error for an invalid group")
+	}
+
+	m, err := NewAuditMiddleware(nil, []string{"write", "exec=request", "system=none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"/containers/abc/exec": AuditLevelRequest,
+		"/exec/abc/start":      AuditLevelRequest,
+		"/containers/create":   AuditLevelWrite,
+		"/build":               AuditLevelWrite,
+		"/services/create":     AuditLevelWrite,
+		"/info":                AuditLevelNone,
+		"/_ping":               AuditLevelNone,
+	}
+	for path, expected := range cases {
+		if level := m.level(auditGroup(path)); level != expected {
+			t.Fatalf("expected level %s for %s, got %s", expected, path, level)
+		}
+	}
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	m, err := NewAuditMiddleware(&buf, []string{"request", "system=write"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		switch r.URL.Path {
+		case "/v1.24/containers/abc/kill":
+			return errors.New("No such container: abc")
+		case "/v1.24/info":
+			return nil
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(b), "s3cr3t") {
+			t.Fatalf("expected the handler to read the original body, got %s", b)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"123456","Warnings":null}`))
+		return nil
+	})
+
+	body := `{"Image":"busybox","Env":["PASSWORD=s3cr3t","DEBUG"],"AuthConfig":{"password":"s3cr3t"}}`
+	req, _ := http.NewRequest("POST", "/v1.24/containers/create?name=web", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "10.0.0.1:1234"
+	ctx := authentication.WithUser(context.Background(), &authentication.User{Name: "alice", AuthNMethod: "token"})
+	if err := handler(ctx, httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ = http.NewRequest("POST", "/v1.24/containers/abc/kill", nil)
+	if err := handler(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": "abc"}); err == nil {
+		t.Fatal("expected the handler error to be returned")
+	}
+
+	req, _ = http.NewRequest("GET", "/v1.24/info", nil)
+	if err := handler(context.Background(), httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "s3cr3t") {
+		t.Fatalf("expected secrets to be redacted, got %s", buf.String())
+	}
+
+	var records []AuditRecord
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var record AuditRecord
+		if err := dec.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	create := records[0]
+	if create.User != "alice" || create.AuthNMethod != "token" || create.RemoteAddr != "10.0.0.1:1234" ||
+		create.Method != "POST" || create.Path != "/containers/create" || create.Query != "name=web" ||
+		create.Status != http.StatusCreated {
+		t.Fatalf("unexpected record %+v", create)
+	}
+	if !reflect.DeepEqual(create.Objects, []string{"123456"}) {
+		t.Fatalf("expected the created container in the objects, got %v", create.Objects)
+	}
+	expectedBody := map[string]interface{}{
+		"Image":      "busybox",
+		"Env":        []interface{}{"PASSWORD=*****", "DEBUG"},
+		"AuthConfig": map[string]interface{}{"password": "*****"},
+	}
+	if !reflect.DeepEqual(create.Body, expectedBody) {
+		t.Fatalf("expected body %v, got %v", expectedBody, create.Body)
+	}
+
+	kill := records[1]
+	if kill.Status != http.StatusNotFound || kill.Error == "" || !reflect.DeepEqual(kill.Objects, []string{"abc"}) {
+		t.Fatalf("unexpected record %+v", kill)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMaskSecretKeys(t *testing.T) {
+	form := map[string]interface{}{
+		"UnlockKey": "SWMKEY-1-7c37Cc8654o6p38HnroywCi19pllOnGtbdZEgtKxZu8",
+		"JoinToken": "SWMTKN-1-3pu6hszjas19xyp7ghgosyx9k8atbfcr8p2is99znpy26u2lkl",
+		"Spec": map[string]interface{}{
+			"Name":     "default",
+			"Password": "s3cr3t",
+		},
+		"Secrets": []interface{}{
+			map[string]interface{}{"secret": "s3cr3t"},
+		},
+	}
+	maskSecretKeys(form)
+
+	expected := map[string]interface{}{
+		"UnlockKey": "*****",
+		"JoinToken": "*****",
+		"Spec": map[string]interface{}{
+			"Name":     "default",
+			"Password": "*****",
+		},
+		"Secrets": []interface{}{
+			map[string]interface{}{"secret": "*****"},
+		},
+	}
+	if !reflect.DeepEqual(form, expected) {
+		t.Fatalf("expected %v, got %v", expected, form)
+	}
+}
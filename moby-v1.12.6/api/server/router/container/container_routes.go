@@ -121,7 +121,7 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 }
 
 func (s *containerRouter) getContainersExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
-	return s.backend.ContainerExport(vars["name"], w)
+	return s.backend.ContainerExport(ctx, vars["name"], w)
 }
 
 func (s *containerRouter) postContainersStart(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -151,7 +151,7 @@ func (s *containerRouter) postContainersStart(ctx context.Context, w http.Respon
 		hostConfig = c
 	}
 
-	if err := s.backend.ContainerStart(vars["name"], hostConfig); err != nil {
+	if err := s.backend.ContainerStart(ctx, vars["name"], hostConfig); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -165,7 +165,7 @@ func (s *containerRouter) postContainersStop(ctx context.Context, w http.Respons
 
 	seconds, _ := strconv.Atoi(r.Form.Get("t"))
 
-	if err := s.backend.ContainerStop(vars["name"], seconds); err != nil {
+	if err := s.backend.ContainerStop(ctx, vars["name"], seconds); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -193,7 +193,7 @@ func (s *containerRouter) postContainersKill(ctx context.Context, w http.Respons
 		}
 	}
 
-	if err := s.backend.ContainerKill(name, uint64(sig)); err != nil {
+	if err := s.backend.ContainerKill(ctx, name, uint64(sig)); err != nil {
 		var isStopped bool
 		if e, ok := err.(errContainerIsRunning); ok {
 			isStopped = !e.ContainerIsRunning()
@@ -219,7 +219,7 @@ func (s *containerRouter) postContainersRestart(ctx context.Context, w http.Resp
 
 	timeout, _ := strconv.Atoi(r.Form.Get("t"))
 
-	if err := s.backend.ContainerRestart(vars["name"], timeout); err != nil {
+	if err := s.backend.ContainerRestart(ctx, vars["name"], timeout); err != nil {
 		return err
 	}
 
@@ -233,7 +233,7 @@ func (s *containerRouter) postContainersPause(ctx context.Context, w http.Respon
 		return err
 	}
 
-	if err := s.backend.ContainerPause(vars["name"]); err != nil {
+	if err := s.backend.ContainerPause(ctx, vars["name"]); err != nil {
 		return err
 	}
 
@@ -247,7 +247,7 @@ func (s *containerRouter) postContainersUnpause(ctx context.Context, w http.Resp
 		return err
 	}
 
-	if err := s.backend.ContainerUnpause(vars["name"]); err != nil {
+	if err := s.backend.ContainerUnpause(ctx, vars["name"]); err != nil {
 		return err
 	}
 
@@ -276,12 +276,29 @@ func (s *containerRouter) getContainersChanges(ctx context.Context, w http.Respo
 	return httputils.WriteJSON(w, http.StatusOK, changes)
 }
 
+func (s *containerRouter) getContainersSeccomp(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if versions.LessThan(httputils.VersionFromContext(ctx), "1.24") {
+		w.WriteHeader(http.StatusNotFound)
+		return nil
+	}
+
+	profile, err := s.backend.ContainerSeccompProfile(vars["name"])
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(profile)
+	return err
+}
+
 func (s *containerRouter) getContainersTop(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
 
-	procList, err := s.backend.ContainerTop(vars["name"], r.Form.Get("ps_args"))
+	procList, err := s.backend.ContainerTop(ctx, vars["name"], r.Form.Get("ps_args"))
 	if err != nil {
 		return err
 	}
@@ -296,7 +313,7 @@ func (s *containerRouter) postContainerRename(ctx context.Context, w http.Respon
 
 	name := vars["name"]
 	newName := r.Form.Get("name")
-	if err := s.backend.ContainerRename(name, newName); err != nil {
+	if err := s.backend.ContainerRename(ctx, name, newName); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -324,7 +341,7 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 	}
 
 	name := vars["name"]
-	warnings, err := s.backend.ContainerUpdate(name, hostConfig)
+	warnings, err := s.backend.ContainerUpdate(ctx, name, hostConfig)
 	if err != nil {
 		return err
 	}
@@ -351,7 +368,7 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 	version := httputils.VersionFromContext(ctx)
 	adjustCPUShares := versions.LessThan(version, "1.19")
 
-	ccr, err := s.backend.ContainerCreate(types.ContainerCreateConfig{
+	ccr, err := s.backend.ContainerCreate(ctx, types.ContainerCreateConfig{
 		Name:             name,
 		Config:           config,
 		HostConfig:       hostConfig,
@@ -377,7 +394,7 @@ func (s *containerRouter) deleteContainers(ctx context.Context, w http.ResponseW
 		RemoveLink:   httputils.BoolValue(r, "link"),
 	}
 
-	if err := s.backend.ContainerRm(name, config); err != nil {
+	if err := s.backend.ContainerRm(ctx, name, config); err != nil {
 		// Force a 404 for the empty string
 		if strings.Contains(strings.ToLower(err.Error()), "prefix can't be empty") {
 			return fmt.Errorf("no such container: \"\"")
@@ -404,7 +421,7 @@ func (s *containerRouter) postContainersResize(ctx context.Context, w http.Respo
 		return err
 	}
 
-	return s.backend.ContainerResize(vars["name"], height, width)
+	return s.backend.ContainerResize(ctx, vars["name"], height, width)
 }
 
 func (s *containerRouter) postContainersAttach(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -455,7 +472,7 @@ func (s *containerRouter) postContainersAttach(ctx context.Context, w http.Respo
 		MuxStreams: true,
 	}
 
-	if err = s.backend.ContainerAttach(containerName, attachConfig); err != nil {
+	if err = s.backend.ContainerAttach(ctx, containerName, attachConfig); err != nil {
 		logrus.Errorf("Handler for %s %s returned error: %v", r.Method, r.URL.Path, err)
 		// Remember to close stream if error happens
 		conn, _, errHijack := hijacker.Hijack()
@@ -511,7 +528,7 @@ func (s *containerRouter) wsContainersAttach(ctx context.Context, w http.Respons
 		MuxStreams: false, // TODO: this should be true since it's a single stream for both stdout and stderr
 	}
 
-	err = s.backend.ContainerAttach(containerName, attachConfig)
+	err = s.backend.ContainerAttach(ctx, containerName, attachConfig)
 	close(done)
 	select {
 	case <-started:
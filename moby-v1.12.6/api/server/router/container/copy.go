@@ -36,7 +36,7 @@ func (s *containerRouter) postContainersCopy(ctx context.Context, w http.Respons
 		return fmt.Errorf("Path cannot be empty")
 	}
 
-	data, err := s.backend.ContainerCopy(vars["name"], cfg.Resource)
+	data, err := s.backend.ContainerCopy(ctx, vars["name"], cfg.Resource)
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "no such container") {
 			w.WriteHeader(http.StatusNotFound)
@@ -92,7 +92,7 @@ func (s *containerRouter) getContainersArchive(ctx context.Context, w http.Respo
 		return err
 	}
 
-	tarArchive, stat, err := s.backend.ContainerArchivePath(v.Name, v.Path)
+	tarArchive, stat, err := s.backend.ContainerArchivePath(ctx, v.Name, v.Path)
 	if err != nil {
 		return err
 	}
@@ -115,5 +115,5 @@ func (s *containerRouter) putContainersArchive(ctx context.Context, w http.Respo
 	}
 
 	noOverwriteDirNonDir := httputils.BoolValue(r, "noOverwriteDirNonDir")
-	return s.backend.ContainerExtractToDir(v.Name, v.Path, noOverwriteDirNonDir, r.Body)
+	return s.backend.ContainerExtractToDir(ctx, v.Name, v.Path, noOverwriteDirNonDir, r.Body)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 // Backend is all the methods that need to be implemented
@@ -13,8 +14,10 @@ type Backend interface {
 	GetNetworkByName(idName string) (libnetwork.Network, error)
 	GetNetworksByID(partialID string) []libnetwork.Network
 	GetNetworks() []libnetwork.Network
-	CreateNetwork(nc types.NetworkCreateRequest) (*types.NetworkCreateResponse, error)
-	ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error
-	DisconnectContainerFromNetwork(containerName string, network libnetwork.Network, force bool) error
-	DeleteNetwork(name string) error
+	CreateNetwork(ctx context.Context, nc types.NetworkCreateRequest) (*types.NetworkCreateResponse, error)
+	ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, endpointConfig *network.EndpointSettings) error
+	DisconnectContainerFromNetwork(ctx context.Context, containerName string, network libnetwork.Network, force bool) error
+	DeleteNetwork(ctx context.Context, name string) error
+	UpdateNetwork(ctx context.Context, name string, update types.NetworkUpdate) error
+	DiagnoseNetwork(target string, options types.NetworkDiagnoseOptions) (*types.NetworkDiagnostics, error)
 }
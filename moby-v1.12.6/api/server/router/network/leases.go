@@ -0,0 +1,153 @@
+package network
+
+import (
+	"bytes"
+	"math"
+	"net"
+	"sort"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/network"
+	"github.com/docker/libnetwork"
+)
+
+// buildIpamLeases fills the addresses allocated in the network, and the
+// usage of its address pools.
+func buildIpamLeases(r *types.NetworkResource, nw libnetwork.Network) {
+	info := nw.Info()
+	_, _, v4Conf, v6Conf := info.IpamConfig()
+	v4Info, v6Info := info.IpamInfo()
+
+	var (
+		leases []network.IPAMLease
+		ips    []net.IP
+		index  = make(map[string]int)
+	)
+	add := func(ip net.IP, lease network.IPAMLease) {
+		lease.Address = ip.String()
+		if _, ok := index[lease.Address]; ok {
+			return
+		}
+		index[lease.Address] = len(leases)
+		leases = append(leases, lease)
+		ips = append(ips, ip)
+	}
+
+	for _, d := range append(v4Info, v6Info...) {
+		if d.Gateway != nil {
+			add(d.Gateway.IP, network.IPAMLease{Type: "gateway"})
+		}
+		for name, addr := range d.AuxAddresses {
+			if addr != nil {
+				add(addr.IP, network.IPAMLease{Type: "auxiliary", Name: name})
+			}
+		}
+	}
+
+	for _, e := range nw.Endpoints() {
+		ei := e.Info()
+		if ei == nil || ei.Iface() == nil {
+			continue
+		}
+		containerID := ""
+		if sb := ei.Sandbox(); sb != nil {
+			containerID = sb.ContainerID()
+		}
+		for _, addr := range []*net.IPNet{ei.Iface().Address(), ei.Iface().AddressIPv6()} {
+			if addr == nil || len(addr.IP) == 0 {
+				continue
+			}
+			// An auxiliary address reserved under the name of the
+			// endpoint is listed once, with the endpoint holding it.
+			if i, ok := index[addr.IP.String()]; ok {
+				leases[i].EndpointID = e.ID()
+				leases[i].ContainerID = containerID
+				continue
+			}
+			add(addr.IP, network.IPAMLease{
+				Type:        "endpoint",
+				Name:        e.Name(),
+				EndpointID:  e.ID(),
+				ContainerID: containerID,
+			})
+		}
+	}
+
+	sort.Sort(byAddress{leases, ips})
+	r.Leases = leases
+
+	for i, d := range v4Info {
+		r.PoolUsage = append(r.PoolUsage, poolUsage(d, v4Conf, i, ips))
+	}
+	for i, d := range v6Info {
+		if d.Pool != nil {
+			r.PoolUsage = append(r.PoolUsage, poolUsage(d, v6Conf, i, ips))
+		}
+	}
+}
+
+// poolUsage counts the addresses of the i-th address pool, restricted to its
+// sub-range if it has one, and the allocated ones among them.
+func poolUsage(d *libnetwork.IpamInfo, conf []*libnetwork.IpamConf, i int, ips []net.IP) network.IPAMPoolUsage {
+	usage := network.IPAMPoolUsage{Subnet: d.Pool.String()}
+	addrRange := d.Pool
+	if i < len(conf) && conf[i].SubPool != "" {
+		if _, subPool, err := net.ParseCIDR(conf[i].SubPool); err == nil {
+			usage.IPRange = subPool.String()
+			addrRange = subPool
+		}
+	}
+
+	usage.Size = poolSize(d.Pool, addrRange)
+	for _, ip := range ips {
+		if addrRange.Contains(ip) {
+			usage.Allocated++
+		}
+	}
+	if usage.Allocated < usage.Size {
+		usage.Available = usage.Size - usage.Allocated
+	}
+	return usage
+}
+
+// poolSize returns the number of addresses of the range which can be
+// allocated in the subnet, leaving out the network address of the subnet,
+// and its broadcast address for IPv4. It saturates for the ranges with 64
+// host bits or more.
+func poolSize(subnet, addrRange *net.IPNet) uint64 {
+	ones, bits := addrRange.Mask.Size()
+	if bits-ones >= 64 {
+		return math.MaxUint64
+	}
+	size := uint64(1) << uint(bits-ones)
+
+	if addrRange.Contains(subnet.IP) && size > 0 {
+		size--
+	}
+	if ip4 := subnet.IP.To4(); ip4 != nil {
+		broadcast := make(net.IP, len(ip4))
+		for i := range ip4 {
+			broadcast[i] = ip4[i] | ^subnet.Mask[len(subnet.Mask)-len(ip4)+i]
+		}
+		if addrRange.Contains(broadcast) && size > 0 {
+			size--
+		}
+	}
+	return size
+}
+
+type byAddress struct {
+	leases []network.IPAMLease
+	ips    []net.IP
+}
+
+func (b byAddress) Len() int { return len(b.leases) }
+
+func (b byAddress) Less(i, j int) bool {
+	return bytes.Compare(b.ips[i].To16(), b.ips[j].To16()) < 0
+}
+
+func (b byAddress) Swap(i, j int) {
+	b.leases[i], b.leases[j] = b.leases[j], b.leases[i]
+	b.ips[i], b.ips[j] = b.ips[j], b.ips[i]
+}
@@ -0,0 +1,70 @@
+package network
+
+import (
+	"math"
+	"net"
+	"testing"
+
+	"github.com/docker/libnetwork"
+)
+
+func mustParseCIDR(t *testing.T, s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ipNet
+}
+
+func TestPoolSize(t *testing.T) {
+	cases := []struct {
+		subnet    string
+		addrRange string
+		expected  uint64
+	}{
+		{"172.20.0.0/16", "172.20.0.0/16", 65534},
+		{"172.20.0.0/24", "172.20.0.0/24", 254},
+		{"172.20.0.0/16", "172.20.10.0/24", 256},
+		{"172.20.0.0/16", "172.20.0.0/24", 255},
+		{"172.20.0.0/16", "172.20.255.0/24", 255},
+		{"172.20.0.0/30", "172.20.0.0/30", 2},
+		{"172.20.0.0/31", "172.20.0.0/31", 0},
+		{"172.20.0.0/32", "172.20.0.0/32", 0},
+		{"fd00::/120", "fd00::/120", 255},
+		{"fd00::/64", "fd00::/120", 255},
+		{"fd00::/64", "fd00::100/120", 256},
+		{"fd00::/64", "fd00::/64", math.MaxUint64},
+		{"fd00::/48", "fd00::/48", math.MaxUint64},
+	}
+
+	for _, c := range cases {
+		if got := poolSize(mustParseCIDR(t, c.subnet), mustParseCIDR(t, c.addrRange)); got != c.expected {
+			t.Fatalf("Expected %d addresses for %s in %s, got %d", c.expected, c.addrRange, c.subnet, got)
+		}
+	}
+}
+
+func TestPoolUsage(t *testing.T) {
+	ips := []net.IP{net.ParseIP("172.20.0.1"), net.ParseIP("172.20.10.1"), net.ParseIP("172.20.10.50"), net.ParseIP("fd00::1")}
+	d := &libnetwork.IpamInfo{}
+	d.Pool = mustParseCIDR(t, "172.20.0.0/16")
+
+	cases := []struct {
+		conf      []*libnetwork.IpamConf
+		ipRange   string
+		size      uint64
+		allocated uint64
+	}{
+		{nil, "", 65534, 3},
+		{[]*libnetwork.IpamConf{{SubPool: "172.20.10.0/24"}}, "172.20.10.0/24", 256, 2},
+		{[]*libnetwork.IpamConf{{SubPool: "172.20.11.0/24"}}, "172.20.11.0/24", 256, 0},
+	}
+
+	for _, c := range cases {
+		usage := poolUsage(d, c.conf, 0, ips)
+		if usage.Subnet != "172.20.0.0/16" || usage.IPRange != c.ipRange || usage.Size != c.size ||
+			usage.Allocated != c.allocated || usage.Available != c.size-c.allocated {
+			t.Fatalf("Unexpected usage %+v for %v", usage, c.conf)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"golang.org/x/net/context"
 
@@ -65,7 +66,37 @@ func (n *networkRouter) getNetwork(ctx context.Context, w http.ResponseWriter, r
 		}
 		return err
 	}
-	return httputils.WriteJSON(w, http.StatusOK, n.buildNetworkResource(nw))
+	nr := n.buildNetworkResource(nw)
+	if httputils.BoolValue(r, "verbose") {
+		buildIpamLeases(nr, nw)
+	}
+	return httputils.WriteJSON(w, http.StatusOK, nr)
+}
+
+func (n *networkRouter) getNetworkDiagnose(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	options := types.NetworkDiagnoseOptions{
+		Probe: r.Form.Get("probe"),
+	}
+	if p := r.Form.Get("port"); p != "" {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return errors.NewBadRequestError(fmt.Errorf("invalid port to probe: %s", p))
+		}
+		options.ProbePort = port
+	}
+	if options.ProbePort != 0 && options.Probe == "" {
+		return errors.NewBadRequestError(fmt.Errorf("a port can only be given with a probe target"))
+	}
+
+	diagnostics, err := n.backend.DiagnoseNetwork(vars["id"], options)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, diagnostics)
 }
 
 func (n *networkRouter) postNetworkCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -87,7 +118,7 @@ func (n *networkRouter) postNetworkCreate(ctx context.Context, w http.ResponseWr
 		return libnetwork.NetworkNameError(create.Name)
 	}
 
-	nw, err := n.backend.CreateNetwork(create)
+	nw, err := n.backend.CreateNetwork(ctx, create)
 	if err != nil {
 		if _, ok := err.(libnetwork.ManagerRedirectError); !ok {
 			return err
@@ -126,7 +157,12 @@ func (n *networkRouter) postNetworkConnect(ctx context.Context, w http.ResponseW
 		return errors.NewRequestForbiddenError(err)
 	}
 
-	return n.backend.ConnectContainerToNetwork(connect.Container, nw.Name(), connect.EndpointConfig)
+	if !nw.Info().Attachable() {
+		err := fmt.Errorf("network %s is not attachable: containers can no longer be connected to it", nw.Name())
+		return errors.NewRequestForbiddenError(err)
+	}
+
+	return n.backend.ConnectContainerToNetwork(ctx, connect.Container, nw.Name(), connect.EndpointConfig)
 }
 
 func (n *networkRouter) postNetworkDisconnect(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -153,7 +189,38 @@ func (n *networkRouter) postNetworkDisconnect(ctx context.Context, w http.Respon
 		return errors.NewRequestForbiddenError(err)
 	}
 
-	return n.backend.DisconnectContainerFromNetwork(disconnect.Container, nw, disconnect.Force)
+	return n.backend.DisconnectContainerFromNetwork(ctx, disconnect.Container, nw, disconnect.Force)
+}
+
+func (n *networkRouter) postNetworkUpdate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var update types.NetworkUpdate
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		return err
+	}
+
+	nw, err := n.backend.FindNetwork(vars["id"])
+	if err != nil {
+		if _, err := n.clusterProvider.GetNetwork(vars["id"]); err == nil {
+			err := fmt.Errorf("operation not supported for swarm scoped networks")
+			return errors.NewRequestForbiddenError(err)
+		}
+		return err
+	}
+
+	if nw.Info().Dynamic() {
+		err := fmt.Errorf("operation not supported for swarm scoped networks")
+		return errors.NewRequestForbiddenError(err)
+	}
+
+	return n.backend.UpdateNetwork(ctx, nw.ID(), update)
 }
 
 func (n *networkRouter) deleteNetwork(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -167,7 +234,7 @@ func (n *networkRouter) deleteNetwork(ctx context.Context, w http.ResponseWriter
 		w.WriteHeader(http.StatusNoContent)
 		return nil
 	}
-	if err := n.backend.DeleteNetwork(vars["id"]); err != nil {
+	if err := n.backend.DeleteNetwork(ctx, vars["id"]); err != nil {
 		return err
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -194,6 +261,7 @@ func (n *networkRouter) buildNetworkResource(nw libnetwork.Network) *types.Netwo
 	r.Driver = nw.Type()
 	r.EnableIPv6 = info.IPv6Enabled()
 	r.Internal = info.Internal()
+	r.Attachable = info.Attachable() && !info.Dynamic()
 	r.Options = info.DriverOptions()
 	r.Containers = make(map[string]types.EndpointResource)
 	buildIpamResources(r, info)
@@ -29,7 +29,10 @@ func (sr *swarmRouter) initRoutes() {
 		router.NewPostRoute("/swarm/leave", sr.leaveCluster),
 		router.NewGetRoute("/swarm", sr.inspectCluster),
 		router.NewPostRoute("/swarm/update", sr.updateCluster),
+		router.NewPostRoute("/swarm/unlock", sr.unlockCluster),
+		router.NewGetRoute("/swarm/unlockkey", sr.getUnlockKey),
 		router.NewGetRoute("/services", sr.getServices),
+		router.NewGetRoute("/services/{id}/logs", sr.getServiceLogs),
 		router.NewGetRoute("/services/{id:.*}", sr.getService),
 		router.NewPostRoute("/services/create", sr.createService),
 		router.NewPostRoute("/services/{id:.*}/update", sr.updateService),
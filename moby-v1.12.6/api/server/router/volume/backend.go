@@ -1,15 +1,21 @@
 package volume
 
 import (
+	"io"
+
 	// TODO return types need to be refactored into pkg
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // Backend is the methods that need to be implemented to provide
 // volume specific functionality
 type Backend interface {
-	Volumes(filter string) ([]*types.Volume, []string, error)
-	VolumeInspect(name string) (*types.Volume, error)
-	VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error)
-	VolumeRm(name string) error
+	Volumes(filter string, size bool) ([]*types.Volume, []string, error)
+	VolumeInspect(name string, size bool) (*types.Volume, error)
+	VolumeCreate(ctx context.Context, name, driverName string, opts, labels map[string]string) (*types.Volume, error)
+	VolumeRm(ctx context.Context, name string) error
+	VolumeClone(ctx context.Context, name, target string, opts, labels map[string]string) (*types.Volume, error)
+	VolumeExport(name string, out io.Writer) error
+	VolumeImport(name string, in io.Reader) error
 }
@@ -274,7 +274,7 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 
 	imageID := image.ID(b.image)
 	for _, rt := range repoAndTags {
-		if err := b.docker.TagImageWithReference(imageID, rt); err != nil {
+		if err := b.docker.TagImageWithReference(b.clientCtx, imageID, rt); err != nil {
 			return "", err
 		}
 	}
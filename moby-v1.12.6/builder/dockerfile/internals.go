@@ -78,7 +78,7 @@ func (b *Builder) commit(id string, autoCmd strslice.StrSlice, comment string) e
 	}
 
 	// Commit the container
-	imageID, err := b.docker.Commit(id, commitCfg)
+	imageID, err := b.docker.Commit(b.clientCtx, id, commitCfg)
 	if err != nil {
 		return err
 	}
@@ -181,7 +181,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 		return nil
 	}
 
-	container, err := b.docker.ContainerCreate(types.ContainerCreateConfig{Config: b.runConfig})
+	container, err := b.docker.ContainerCreate(b.clientCtx, types.ContainerCreateConfig{Config: b.runConfig})
 	if err != nil {
 		return err
 	}
@@ -505,7 +505,7 @@ func (b *Builder) create() (string, error) {
 	config := *b.runConfig
 
 	// Create the container
-	c, err := b.docker.ContainerCreate(types.ContainerCreateConfig{
+	c, err := b.docker.ContainerCreate(b.clientCtx, types.ContainerCreateConfig{
 		Config:     b.runConfig,
 		HostConfig: hostConfig,
 	})
@@ -532,7 +532,7 @@ var errCancelled = errors.New("build cancelled")
 func (b *Builder) run(cID string) (err error) {
 	errCh := make(chan error)
 	go func() {
-		errCh <- b.docker.ContainerAttachRaw(cID, nil, b.Stdout, b.Stderr, true)
+		errCh <- b.docker.ContainerAttachRaw(b.clientCtx, cID, nil, b.Stdout, b.Stderr, true)
 	}()
 
 	finished := make(chan struct{})
@@ -544,7 +544,7 @@ func (b *Builder) run(cID string) (err error) {
 		select {
 		case <-b.clientCtx.Done():
 			logrus.Debugln("Build cancelled, killing and removing container:", cID)
-			b.docker.ContainerKill(cID, 0)
+			b.docker.ContainerKill(b.clientCtx, cID, 0)
 			b.removeContainer(cID)
 			cancelErrCh <- errCancelled
 		case <-finished:
@@ -552,7 +552,7 @@ func (b *Builder) run(cID string) (err error) {
 		}
 	}()
 
-	if err := b.docker.ContainerStart(cID, nil); err != nil {
+	if err := b.docker.ContainerStart(b.clientCtx, cID, nil); err != nil {
 		return err
 	}
 
@@ -577,7 +577,7 @@ func (b *Builder) removeContainer(c string) error {
 		ForceRemove:  true,
 		RemoveVolume: true,
 	}
-	if err := b.docker.ContainerRm(c, rmConfig); err != nil {
+	if err := b.docker.ContainerRm(b.clientCtx, c, rmConfig); err != nil {
 		fmt.Fprintf(b.Stdout, "Error removing intermediate container %s: %v\n", stringid.TruncateID(c), err)
 		return err
 	}
@@ -4,6 +4,7 @@ import (
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/api/client/container"
 	"github.com/docker/docker/api/client/image"
+	"github.com/docker/docker/api/client/manifest"
 	"github.com/docker/docker/api/client/network"
 	"github.com/docker/docker/api/client/node"
 	"github.com/docker/docker/api/client/plugin"
@@ -62,6 +63,7 @@ func NewCobraAdaptor(clientFlags *cliflags.ClientFlags) CobraAdaptor {
 		container.NewRestartCommand(dockerCli),
 		container.NewRmCommand(dockerCli),
 		container.NewRunCommand(dockerCli),
+		container.NewSeccompProfileCommand(dockerCli),
 		container.NewStartCommand(dockerCli),
 		container.NewStatsCommand(dockerCli),
 		container.NewStopCommand(dockerCli),
@@ -79,6 +81,7 @@ func NewCobraAdaptor(clientFlags *cliflags.ClientFlags) CobraAdaptor {
 		image.NewSearchCommand(dockerCli),
 		image.NewImportCommand(dockerCli),
 		image.NewTagCommand(dockerCli),
+		manifest.NewManifestCommand(dockerCli),
 		network.NewNetworkCommand(dockerCli),
 		system.NewEventsCommand(dockerCli),
 		registry.NewLoginCommand(dockerCli),
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/server/middleware"
+	"github.com/docker/docker/daemon"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/go-units"
+)
+
+const (
+	auditLogSyslog   = "syslog"
+	defaultAuditTag  = "docker-audit"
+	defaultAuditSize = "100m"
+	defaultAuditFile = 5
+)
+
+// newAuditMiddleware creates the middleware writing API audit records to
+// the audit log configured for the daemon.
+func newAuditMiddleware(config *daemon.Config) (*middleware.AuditMiddleware, error) {
+	w, err := newAuditWriter(config.AuditLog, config.AuditLogOpts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit log: %v", err)
+	}
+	return middleware.NewAuditMiddleware(w, config.AuditLevels)
+}
+
+// newAuditWriter returns a writer for the audit log dest, which is either
+// "syslog" or the absolute path of a file rotated when it exceeds its
+// maximum size.
+func newAuditWriter(dest string, opts map[string]string) (io.Writer, error) {
+	if dest == auditLogSyslog {
+		return newAuditSyslogWriter(opts)
+	}
+	if !filepath.IsAbs(dest) {
+		return nil, fmt.Errorf("audit log must be %q or an absolute path, got %q", auditLogSyslog, dest)
+	}
+
+	maxSize, maxFiles := defaultAuditSize, defaultAuditFile
+	for k, v := range opts {
+		switch k {
+		case "max-size":
+			maxSize = v
+		case "max-file":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("max-file must be a positive integer, got %q", v)
+			}
+			maxFiles = n
+		default:
+			return nil, fmt.Errorf("unknown audit log option %s for a file", k)
+		}
+	}
+	capacity, err := units.FromHumanSize(maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return loggerutils.NewRotateFileWriter(dest, capacity, maxFiles)
+}
+
+// newAuditSyslogWriter returns a writer sending audit records to syslog
+// with the syslog log driver.
+func newAuditSyslogWriter(opts map[string]string) (io.Writer, error) {
+	config := map[string]string{"tag": defaultAuditTag}
+	for k, v := range opts {
+		if k != "tag" && !strings.HasPrefix(k, "syslog-") {
+			return nil, fmt.Errorf("unknown audit log option %s for syslog", k)
+		}
+		config[k] = v
+	}
+	if err := logger.ValidateLogOpts(auditLogSyslog, config); err != nil {
+		return nil, err
+	}
+	creator, err := logger.GetLogDriver(auditLogSyslog)
+	if err != nil {
+		return nil, err
+	}
+	l, err := creator(logger.Context{Config: config})
+	if err != nil {
+		return nil, err
+	}
+	return &loggerWriter{l: l}, nil
+}
+
+// loggerWriter writes each record as a message of a log driver.
+type loggerWriter struct {
+	l logger.Logger
+}
+
+func (w *loggerWriter) Write(b []byte) (int, error) {
+	if err := w.l.Log(&logger.Message{
+		Line:      bytes.TrimSuffix(b, []byte("\n")),
+		Source:    "stdout",
+		Timestamp: time.Now(),
+	}); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
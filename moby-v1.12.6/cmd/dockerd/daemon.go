@@ -32,6 +32,7 @@ import (
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/opts"
+	"github.com/docker/docker/pkg/authentication"
 	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/pkg/jsonlog"
 	"github.com/docker/docker/pkg/listeners"
@@ -57,6 +58,9 @@ type DaemonCli struct {
 
 	api *apiserver.Server
 	d   *daemon.Daemon
+
+	authzMiddleware *authorization.Middleware
+	authzPolicy     *authorization.Policy
 }
 
 func presentInHelp(usage string) string { return usage }
@@ -68,6 +72,7 @@ func NewDaemonCli() *DaemonCli {
 	daemonConfig := new(daemon.Config)
 	daemonConfig.LogConfig.Config = make(map[string]string)
 	daemonConfig.ClusterOpts = make(map[string]string)
+	daemonConfig.AuditLogOpts = make(map[string]string)
 
 	if runtime.GOOS != "linux" {
 		daemonConfig.V2Only = true
@@ -288,7 +293,9 @@ func (cli *DaemonCli) start() (err error) {
 		"graphdriver": d.GraphDriverName(),
 	}).Info("Docker daemon")
 
-	cli.initMiddlewares(api, serverConfig)
+	if err := cli.initMiddlewares(api, serverConfig, d); err != nil {
+		logrus.Fatalf("Error setting up API middlewares: %v", err)
+	}
 	initRouter(api, d, c)
 
 	cli.d = d
@@ -322,6 +329,9 @@ func (cli *DaemonCli) reloadConfig() {
 			logrus.Errorf("Error reconfiguring the daemon: %v", err)
 			return
 		}
+		if err := cli.reloadAuthorizationPolicy(config); err != nil {
+			logrus.Errorf("Error reloading the authorization policy: %v", err)
+		}
 		if config.IsValueSet("debug") {
 			debugEnabled := utils.IsDebugEnabled()
 			switch {
@@ -425,7 +435,7 @@ func initRouter(s *apiserver.Server, d *daemon.Daemon, c *cluster.Cluster) {
 	s.InitRouter(utils.IsDebugEnabled(), routers...)
 }
 
-func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config) {
+func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config, d *daemon.Daemon) error {
 	v := cfg.Version
 
 	vm := middleware.NewVersionMiddleware(v, api.DefaultVersion, api.MinVersion)
@@ -439,9 +449,103 @@ func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config
 	u := middleware.NewUserAgentMiddleware(v)
 	s.UseMiddleware(u)
 
-	if len(cli.Config.AuthorizationPlugins) > 0 {
-		authZPlugins := authorization.NewPlugins(cli.Config.AuthorizationPlugins)
-		handleAuthorization := authorization.NewMiddleware(authZPlugins)
-		s.UseMiddleware(handleAuthorization)
+	// The authorization middleware is always installed, so that a policy
+	// can be added when reloading the configuration.
+	if cli.Config.AuthorizationPolicy != "" {
+		policy, err := authorization.NewPolicy(cli.Config.AuthorizationPolicy, runconfig.ContainerDecoder{})
+		if err != nil {
+			return err
+		}
+		cli.authzPolicy = policy
+	}
+	cli.authzMiddleware = authorization.NewMiddleware(cli.authorizationPlugins())
+	s.UseMiddleware(cli.authzMiddleware)
+
+	// The audit middleware runs after the authentication middleware, to
+	// record the user, and before the authorization middleware, to record
+	// denied requests.
+	if cli.Config.AuditLog != "" {
+		handleAudit, err := newAuditMiddleware(cli.Config)
+		if err != nil {
+			return err
+		}
+		s.UseMiddleware(handleAudit)
+	}
+
+	// The authentication middleware is added last so that it runs first,
+	// identifying the user before the authorization middleware runs.
+	handleAuthentication, err := newAuthenticationMiddleware(cli.Config)
+	if err != nil {
+		return err
+	}
+	s.UseMiddleware(handleAuthentication)
+	return nil
+}
+
+// authorizationPlugins returns the plugins authorizing API requests: the
+// built-in policy, if any, followed by the configured authorization plugins.
+func (cli *DaemonCli) authorizationPlugins() []authorization.Plugin {
+	var plugins []authorization.Plugin
+	if cli.authzPolicy != nil {
+		plugins = append(plugins, cli.authzPolicy)
+	}
+	return append(plugins, authorization.NewPlugins(cli.Config.AuthorizationPlugins)...)
+}
+
+// reloadAuthorizationPolicy reads the authorization policy file again, or
+// loads the policy file set in the reloaded configuration.
+func (cli *DaemonCli) reloadAuthorizationPolicy(config *daemon.Config) error {
+	policyFile := cli.Config.AuthorizationPolicy
+	if config.IsValueSet("authorization-policy") {
+		policyFile = config.AuthorizationPolicy
+	}
+	if policyFile == "" {
+		return nil
 	}
+
+	if cli.authzPolicy != nil {
+		if err := cli.authzPolicy.Reload(policyFile); err != nil {
+			return err
+		}
+	} else {
+		policy, err := authorization.NewPolicy(policyFile, runconfig.ContainerDecoder{})
+		if err != nil {
+			return err
+		}
+		cli.authzPolicy = policy
+		cli.authzMiddleware.SetPlugins(cli.authorizationPlugins())
+	}
+	cli.Config.AuthorizationPolicy = policyFile
+	return nil
+}
+
+// newAuthenticationMiddleware creates the middleware identifying API users
+// with the authenticators configured for the daemon.
+func newAuthenticationMiddleware(config *daemon.Config) (authentication.Middleware, error) {
+	var authenticators []authentication.Authenticator
+	if config.AuthenticationTokenFile != "" {
+		a, err := authentication.NewTokenAuthenticator(config.AuthenticationTokenFile)
+		if err != nil {
+			return authentication.Middleware{}, err
+		}
+		authenticators = append(authenticators, a)
+	}
+	if config.AuthenticationHtpasswdFile != "" {
+		a, err := authentication.NewHtpasswdAuthenticator(config.AuthenticationHtpasswdFile)
+		if err != nil {
+			return authentication.Middleware{}, err
+		}
+		authenticators = append(authenticators, a)
+	}
+	authenticators = append(authenticators, authentication.NewPlugins(config.AuthenticationPlugins)...)
+
+	var groups map[string][]string
+	if config.AuthenticationGroupFile != "" {
+		var err error
+		if groups, err = authentication.ReadGroupFile(config.AuthenticationGroupFile); err != nil {
+			return authentication.Middleware{}, err
+		}
+	}
+
+	return authentication.NewMiddleware(authenticators, groups), nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
@@ -47,6 +48,10 @@ import (
 // config.v2.json 即为容器的配置文件。
 const configFileName = "config.v2.json"
 
+// backupSuffix names the previous content of the configuration files of a
+// container, kept to recover from a write interrupted by a crash.
+const backupSuffix = ".bak"
+
 var (
 	errInvalidEndpoint = fmt.Errorf("invalid endpoint while building port map info")
 	errInvalidNetwork  = fmt.Errorf("invalid network settings while building port map info")
@@ -86,8 +91,12 @@ type CommonContainer struct {
 	HasBeenStartedBefore   bool
 	HasBeenManuallyStopped bool // used for unless-stopped restart policy
 	MountPoints            map[string]*volume.MountPoint
-	HostConfig             *containertypes.HostConfig `json:"-"` // do not serialize the host config in the json, otherwise we'll make the container unportable
-	ExecCommands           *exec.Store                `json:"-"`
+	// UIDMaps and GIDMaps are the user namespace mappings of a container
+	// using its own range of IDs, allocated from the daemon pool.
+	UIDMaps      []idtools.IDMap            `json:",omitempty"`
+	GIDMaps      []idtools.IDMap            `json:",omitempty"`
+	HostConfig   *containertypes.HostConfig `json:"-"` // do not serialize the host config in the json, otherwise we'll make the container unportable
+	ExecCommands *exec.Store                `json:"-"`
 	// logDriver for closing
 	LogDriver      logger.Logger  `json:"-"`
 	LogCopier      *logger.Copier `json:"-"`
@@ -119,16 +128,8 @@ func (container *Container) FromDisk() error {
 		return err
 	}
 
-	jsonSource, err := os.Open(pth)
-	if err != nil {
-		return err
-	}
-	defer jsonSource.Close()
-
-	dec := json.NewDecoder(jsonSource)
-
 	// Load container settings
-	if err := dec.Decode(container); err != nil {
+	if err := readJSON(pth, container); err != nil {
 		return err
 	}
 
@@ -146,16 +147,8 @@ func (container *Container) ToDisk() error {
 		return err
 	}
 
-	jsonSource, err := ioutils.NewAtomicFileWriter(pth, 0666)
-	if err != nil {
-		return err
-	}
-	defer jsonSource.Close()
-
-	enc := json.NewEncoder(jsonSource)
-
 	// Save container settings
-	if err := enc.Encode(container); err != nil {
+	if err := writeJSON(pth, container); err != nil {
 		return err
 	}
 
@@ -171,6 +164,15 @@ func (container *Container) ToDiskLocking() error {
 	return err
 }
 
+// CheckpointTo saves the container configuration on disk and refreshes its
+// copy in the given ViewDB. The caller must hold the container lock.
+func (container *Container) CheckpointTo(store ViewDB) error {
+	if err := container.ToDisk(); err != nil {
+		return err
+	}
+	return store.Save(container)
+}
+
 // @anxk: 从本地磁盘加载容器的主机配置文件。
 // readHostConfig reads the host configuration from disk for the container.
 func (container *Container) readHostConfig() error {
@@ -183,18 +185,12 @@ func (container *Container) readHostConfig() error {
 		return err
 	}
 
-	f, err := os.Open(pth)
-	if err != nil {
+	if err := readJSON(pth, &container.HostConfig); err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
 		return err
 	}
-	defer f.Close()
-
-	if err := json.NewDecoder(f).Decode(&container.HostConfig); err != nil {
-		return err
-	}
 
 	container.InitDNSHostConfig()
 
@@ -209,13 +205,49 @@ func (container *Container) WriteHostConfig() error {
 		return err
 	}
 
-	f, err := ioutils.NewAtomicFileWriter(pth, 0666)
+	return writeJSON(pth, &container.HostConfig)
+}
+
+// writeJSON atomically writes v as JSON to path, keeping the previous content
+// of the file next to it to recover from.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	return ioutils.AtomicWriteFileWithBackup(path, path+backupSuffix, data, 0666)
+}
+
+// readJSON decodes the JSON file at path into v. When the file is missing or
+// truncated, as it can be after a power loss, the previous content kept by
+// writeJSON is decoded instead, and put back in place of the file.
+func readJSON(path string, v interface{}) error {
+	err := decodeJSONFile(path, v)
+	if err == nil {
+		return nil
+	}
+
+	data, backupErr := ioutil.ReadFile(path + backupSuffix)
+	if backupErr != nil {
+		return err
+	}
+	if backupErr := json.Unmarshal(data, v); backupErr != nil {
+		return err
+	}
+	logrus.Warnf("Failed to read %s, recovered its previous content: %v", path, err)
+	if err := ioutils.AtomicWriteFile(path, data, 0666); err != nil {
+		logrus.Warnf("Failed to restore %s: %v", path, err)
+	}
+	return nil
+}
 
-	return json.NewEncoder(f).Encode(&container.HostConfig)
+func decodeJSONFile(path string, v interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
 }
 
 // SetupWorkingDirectory sets up the container's working directory as set in container.Config.WorkingDir
@@ -317,6 +349,11 @@ func (container *Container) ConfigPath() (string, error) {
 	return container.GetRootResourcePath(configFileName)
 }
 
+// SeccompProfilePath returns the path to the seccomp profile recorded for the container
+func (container *Container) SeccompProfilePath() (string, error) {
+	return container.GetRootResourcePath("seccomp.json")
+}
+
 // StartLogger starts a new logger driver for the container.
 func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Logger, error) {
 	c, err := logger.GetLogDriver(cfg.Type)
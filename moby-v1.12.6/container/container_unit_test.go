@@ -1,6 +1,8 @@
 package container
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/docker/docker/pkg/signal"
@@ -34,3 +36,58 @@ func TestContainerStopSignal(t *testing.T) {
 		t.Fatalf("Expected 9, got %v", s)
 	}
 }
+
+func TestContainerFromDiskRecoversTruncatedConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "container-from-disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	c := NewBaseContainer("id", root)
+	c.Config = &container.Config{Hostname: "first"}
+	c.HostConfig = &container.HostConfig{ShmSize: 1}
+	if err := c.ToDisk(); err != nil {
+		t.Fatal(err)
+	}
+	c.Config.Hostname = "second"
+	c.HostConfig.ShmSize = 2
+	if err := c.ToDisk(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a power loss can leave a truncated file behind
+	configPath, err := c.ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostConfigPath, err := c.HostConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{configPath, hostConfigPath} {
+		if err := os.Truncate(path, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded := NewBaseContainer("id", root)
+	if err := loaded.FromDisk(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Config.Hostname != "first" || loaded.HostConfig.ShmSize != 1 {
+		t.Fatalf("Expected the previous configuration, got hostname %q and shm size %d", loaded.Config.Hostname, loaded.HostConfig.ShmSize)
+	}
+
+	// the recovered configuration is put back in place
+	loaded = NewBaseContainer("id", root)
+	if err := os.Remove(configPath + backupSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.FromDisk(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Config.Hostname != "first" {
+		t.Fatalf("Expected the restored configuration, got hostname %q", loaded.Config.Hostname)
+	}
+}
@@ -18,12 +18,18 @@ import (
 	"github.com/docker/docker/utils"
 	"github.com/docker/docker/volume"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
 // DefaultSHMSize is the default size (64MB) of the SHM which will be mounted in the container
 const DefaultSHMSize int64 = 67108864
 
+// WritablePathsLabel is the label of an image listing the comma-separated
+// paths its containers write to, mounted as tmpfs when the root filesystem
+// of a container is read-only.
+const WritablePathsLabel = "com.docker.read-only.writable-paths"
+
 // @anxk: unix 下的容器。
 // Container holds the fields specific to unixen implementations.
 // See CommonContainer for standard fields common to all containers.
@@ -298,6 +304,18 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	if resources.KernelMemory != 0 {
 		cResources.KernelMemory = resources.KernelMemory
 	}
+	if resources.NetworkIngressRate != 0 {
+		cResources.NetworkIngressRate = resources.NetworkIngressRate
+	}
+	if resources.NetworkIngressBurst != 0 {
+		cResources.NetworkIngressBurst = resources.NetworkIngressBurst
+	}
+	if resources.NetworkEgressRate != 0 {
+		cResources.NetworkEgressRate = resources.NetworkEgressRate
+	}
+	if resources.NetworkEgressBurst != 0 {
+		cResources.NetworkEgressBurst = resources.NetworkEgressBurst
+	}
 
 	// update HostConfig of container
 	if hostConfig.RestartPolicy.Name != "" {
@@ -408,9 +426,66 @@ func (container *Container) TmpfsMounts() []Mount {
 			Data:        data,
 		})
 	}
+	for _, m := range container.HostConfig.Mounts {
+		if m.Type != mounttypes.TypeTmpfs {
+			continue
+		}
+		mounts = append(mounts, Mount{
+			Source:      "tmpfs",
+			Destination: filepath.Clean(m.Target),
+			Data:        volume.ConvertTmpfsOptions(m.TmpfsOptions, m.ReadOnly),
+		})
+	}
+
+	// Paths which are already mounted stay as they are.
+	mounted := make(map[string]bool)
+	for _, m := range mounts {
+		mounted[m.Destination] = true
+	}
+	for _, path := range container.WritablePaths() {
+		if mounted[path] || container.MountPoints[path] != nil {
+			continue
+		}
+		mounts = append(mounts, Mount{
+			Source:      "tmpfs",
+			Destination: path,
+		})
+		mounted[path] = true
+	}
 	return mounts
 }
 
+// WritablePaths returns the paths mounted as tmpfs for the container to be
+// able to write to them, when its root filesystem is read-only. They are
+// the paths set on the container, and the ones listed by the image with
+// the WritablePathsLabel label.
+func (container *Container) WritablePaths() []string {
+	if !container.HostConfig.ReadonlyRootfs {
+		return nil
+	}
+
+	paths := append([]string{}, container.HostConfig.ReadonlyWritablePaths...)
+	if container.Config != nil && container.Config.Labels[WritablePathsLabel] != "" {
+		paths = append(paths, strings.Split(container.Config.Labels[WritablePathsLabel], ",")...)
+	}
+
+	var out []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if !filepath.IsAbs(path) {
+			continue
+		}
+		path = filepath.Clean(path)
+		if path == "/" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		out = append(out, path)
+	}
+	return out
+}
+
 // cleanResourcePath cleans a resource path and prepares to combine with mnt path
 func cleanResourcePath(path string) string {
 	return filepath.Join(string(os.PathSeparator), path)
@@ -0,0 +1,47 @@
+// +build linux freebsd
+
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/volume"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+func TestWritablePathsMounts(t *testing.T) {
+	c := &Container{
+		CommonContainer: CommonContainer{
+			Config: &containertypes.Config{
+				Labels: map[string]string{WritablePathsLabel: "/var/run, /var/cache/app,relative"},
+			},
+			HostConfig: &containertypes.HostConfig{
+				ReadonlyWritablePaths: []string{"/tmp/", "/var/run", "/data", "/"},
+				Tmpfs:                 map[string]string{"/tmp": "size=1m"},
+			},
+			MountPoints: map[string]*volume.MountPoint{
+				"/data": {Destination: "/data"},
+			},
+		},
+	}
+
+	if paths := c.WritablePaths(); paths != nil {
+		t.Fatalf("Expected no writable paths without a read-only root filesystem, got %v", paths)
+	}
+
+	c.HostConfig.ReadonlyRootfs = true
+	expected := []string{"/tmp", "/var/run", "/data", "/var/cache/app"}
+	if paths := c.WritablePaths(); !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Expected writable paths %v, got %v", expected, paths)
+	}
+
+	mounts := make(map[string]string)
+	for _, m := range c.TmpfsMounts() {
+		mounts[m.Destination] = m.Data
+	}
+	expectedMounts := map[string]string{"/tmp": "size=1m", "/var/run": "", "/var/cache/app": ""}
+	if !reflect.DeepEqual(mounts, expectedMounts) {
+		t.Fatalf("Expected tmpfs mounts %v, got %v", expectedMounts, mounts)
+	}
+}
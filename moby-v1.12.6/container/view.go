@@ -0,0 +1,324 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/docker/docker/image"
+)
+
+const (
+	memdbContainersTable = "containers"
+	memdbVolumesTable    = "volumes"
+	memdbNetworksTable   = "networks"
+
+	memdbIDIndex        = "id"
+	memdbImageIndex     = "image"
+	memdbContainerIndex = "container"
+	memdbValueIndex     = "value"
+)
+
+// ViewDB is an in-memory, transactional and indexed copy of the state of the
+// containers, updated every time a container is checkpointed. Readers query
+// it through a View, without taking the lock of any container.
+type ViewDB interface {
+	// Snapshot returns a consistent, read-only view of the containers.
+	Snapshot() View
+	// Save stores a copy of the container, the caller must hold its lock.
+	Save(*Container) error
+	// Delete removes the container from the view.
+	Delete(*Container) error
+}
+
+// View is a read-only, point in time view of the containers. The containers
+// it returns are copies that must not be modified.
+type View interface {
+	// All returns all the containers, ordered by creation date, newest first.
+	All() ([]*Container, error)
+	// Get returns the container with the given full ID.
+	Get(id string) (*Container, error)
+	// ByImage returns the IDs of the containers created from the image.
+	ByImage(id image.ID) ([]string, error)
+	// ByVolume returns the IDs of the containers mounting the volume, given
+	// its name, source or destination.
+	ByVolume(name string) ([]string, error)
+	// ByNetwork returns the IDs of the containers connected to the network,
+	// given its name or ID.
+	ByNetwork(name string) ([]string, error)
+}
+
+// containerAttr is a row of the volumes and networks tables, associating a
+// container with one of the values it can be looked up by.
+type containerAttr struct {
+	ContainerID string
+	Value       string
+}
+
+var schema = &memdb.DBSchema{
+	Tables: map[string]*memdb.TableSchema{
+		memdbContainersTable: {
+			Name: memdbContainersTable,
+			Indexes: map[string]*memdb.IndexSchema{
+				memdbIDIndex: {
+					Name:    memdbIDIndex,
+					Unique:  true,
+					Indexer: &memdb.StringFieldIndex{Field: "ID"},
+				},
+				memdbImageIndex: {
+					Name:         memdbImageIndex,
+					AllowMissing: true,
+					Indexer:      &memdb.StringFieldIndex{Field: "ImageID"},
+				},
+			},
+		},
+		memdbVolumesTable:  attrTableSchema(memdbVolumesTable),
+		memdbNetworksTable: attrTableSchema(memdbNetworksTable),
+	},
+}
+
+func attrTableSchema(name string) *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: name,
+		Indexes: map[string]*memdb.IndexSchema{
+			memdbIDIndex: {
+				Name:   memdbIDIndex,
+				Unique: true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{Field: "ContainerID"},
+						&memdb.StringFieldIndex{Field: "Value"},
+					},
+				},
+			},
+			memdbContainerIndex: {
+				Name:    memdbContainerIndex,
+				Indexer: &memdb.StringFieldIndex{Field: "ContainerID"},
+			},
+			memdbValueIndex: {
+				Name:    memdbValueIndex,
+				Indexer: &memdb.StringFieldIndex{Field: "Value"},
+			},
+		},
+	}
+}
+
+type memDB struct {
+	store *memdb.MemDB
+}
+
+// NewViewDB returns an empty in-memory ViewDB.
+func NewViewDB() (ViewDB, error) {
+	store, err := memdb.NewMemDB(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &memDB{store: store}, nil
+}
+
+// Snapshot returns a read-only view of the database as it is now.
+func (db *memDB) Snapshot() View {
+	return &memdbView{txn: db.store.Txn(false)}
+}
+
+// Save replaces the copy of the container and its index entries.
+func (db *memDB) Save(c *Container) error {
+	snapshot, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	txn := db.store.Txn(true)
+	defer txn.Abort()
+
+	if err := deleteAttrs(txn, c.ID); err != nil {
+		return err
+	}
+	if err := txn.Insert(memdbContainersTable, snapshot); err != nil {
+		return err
+	}
+	for _, name := range volumeNames(snapshot) {
+		if err := txn.Insert(memdbVolumesTable, &containerAttr{ContainerID: c.ID, Value: name}); err != nil {
+			return err
+		}
+	}
+	for _, name := range networkNames(snapshot) {
+		if err := txn.Insert(memdbNetworksTable, &containerAttr{ContainerID: c.ID, Value: name}); err != nil {
+			return err
+		}
+	}
+	txn.Commit()
+	return nil
+}
+
+// Delete removes the container and its index entries.
+func (db *memDB) Delete(c *Container) error {
+	txn := db.store.Txn(true)
+	defer txn.Abort()
+
+	if err := deleteAttrs(txn, c.ID); err != nil {
+		return err
+	}
+	if _, err := txn.DeleteAll(memdbContainersTable, memdbIDIndex, c.ID); err != nil {
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
+func deleteAttrs(txn *memdb.Txn, id string) error {
+	for _, table := range []string{memdbVolumesTable, memdbNetworksTable} {
+		if _, err := txn.DeleteAll(table, memdbContainerIndex, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type memdbView struct {
+	txn *memdb.Txn
+}
+
+// All returns all the containers of the view, newest first.
+func (v *memdbView) All() ([]*Container, error) {
+	iter, err := v.txn.Get(memdbContainersTable, memdbIDIndex)
+	if err != nil {
+		return nil, err
+	}
+	var all History
+	for item := iter.Next(); item != nil; item = iter.Next() {
+		all = append(all, item.(*Container))
+	}
+	all.sort()
+	return all, nil
+}
+
+// Get returns the container with the given full ID.
+func (v *memdbView) Get(id string) (*Container, error) {
+	item, err := v.txn.First(memdbContainersTable, memdbIDIndex, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("No such container: %s", id)
+	}
+	return item.(*Container), nil
+}
+
+// ByImage returns the IDs of the containers created from the image.
+func (v *memdbView) ByImage(id image.ID) ([]string, error) {
+	iter, err := v.txn.Get(memdbContainersTable, memdbImageIndex, id.String())
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for item := iter.Next(); item != nil; item = iter.Next() {
+		ids = append(ids, item.(*Container).ID)
+	}
+	return ids, nil
+}
+
+// ByVolume returns the IDs of the containers mounting the volume.
+func (v *memdbView) ByVolume(name string) ([]string, error) {
+	return v.byAttr(memdbVolumesTable, name)
+}
+
+// ByNetwork returns the IDs of the containers connected to the network.
+func (v *memdbView) ByNetwork(name string) ([]string, error) {
+	return v.byAttr(memdbNetworksTable, name)
+}
+
+func (v *memdbView) byAttr(table, value string) ([]string, error) {
+	iter, err := v.txn.Get(table, memdbValueIndex, value)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for item := iter.Next(); item != nil; item = iter.Next() {
+		ids = append(ids, item.(*containerAttr).ContainerID)
+	}
+	return ids, nil
+}
+
+// snapshot returns a deep copy of the container, sharing only the fields
+// that are safe for concurrent use on their own: the volumes, the exec store
+// and the read-write layer. The caller must hold the container lock.
+func (container *Container) snapshot() (*Container, error) {
+	data, err := json.Marshal(container)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Container{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.State == nil {
+		snapshot.State = &State{}
+	}
+	snapshot.exitCode = container.exitCode
+	snapshot.error = container.error
+	for dest, m := range snapshot.MountPoints {
+		if orig, ok := container.MountPoints[dest]; ok {
+			m.Volume = orig.Volume
+		}
+	}
+
+	if container.HostConfig != nil {
+		data, err := json.Marshal(container.HostConfig)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.HostConfig = &containertypes.HostConfig{}
+		if err := json.Unmarshal(data, snapshot.HostConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot.Root = container.Root
+	snapshot.BaseFS = container.BaseFS
+	snapshot.RWLayer = container.RWLayer
+	snapshot.ExecCommands = container.ExecCommands
+	return snapshot, nil
+}
+
+// volumeNames returns the names, sources and destinations of the mounts of
+// the container, which the volume filter of ps matches on.
+func volumeNames(c *Container) []string {
+	seen := make(map[string]bool)
+	for dest, m := range c.MountPoints {
+		seen[dest] = true
+		if m.Name != "" {
+			seen[m.Name] = true
+		} else if m.Source != "" {
+			seen[m.Source] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// networkNames returns the names and IDs of the networks the container is
+// connected to.
+func networkNames(c *Container) []string {
+	seen := make(map[string]bool)
+	if c.NetworkSettings != nil {
+		for name, nw := range c.NetworkSettings.Networks {
+			seen[name] = true
+			if nw != nil && nw.NetworkID != "" {
+				seen[nw.NetworkID] = true
+			}
+		}
+	}
+	return sortedKeys(seen)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/volume"
+	containertypes "github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+)
+
+func newViewContainer(id string, created time.Time) *Container {
+	c := NewBaseContainer(id, "root")
+	c.Created = created
+	c.Config = &containertypes.Config{}
+	c.HostConfig = &containertypes.HostConfig{}
+	c.NetworkSettings = &network.Settings{}
+	return c
+}
+
+func TestViewSaveAndGet(t *testing.T) {
+	db, err := NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newViewContainer("id1", time.Now())
+	c.SetRunning(42, true)
+	if err := db.Save(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// the view is a copy, later changes are only visible after a new save
+	view := db.Snapshot()
+	c.SetStopped(&ExitStatus{ExitCode: 3})
+
+	s, err := view.Get("id1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Running || s.Pid != 42 {
+		t.Fatalf("expected running container with pid 42, got running=%v pid=%d", s.Running, s.Pid)
+	}
+	if s == c {
+		t.Fatal("expected a copy of the container")
+	}
+
+	if err := db.Save(c); err != nil {
+		t.Fatal(err)
+	}
+	s, err = db.Snapshot().Get("id1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Running || s.ExitCode() != 3 {
+		t.Fatalf("expected exited container with code 3, got running=%v code=%d", s.Running, s.ExitCode())
+	}
+
+	if _, err := view.Get("nothere"); err == nil {
+		t.Fatal("expected an error for a missing container")
+	}
+}
+
+func TestViewAllSortedByCreation(t *testing.T) {
+	db, err := NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, id := range []string{"b", "c", "a"} {
+		if err := db.Save(newViewContainer(id, now.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := db.Snapshot().All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, c := range all {
+		ids = append(ids, c.ID)
+	}
+	if len(ids) != 3 || ids[0] != "a" || ids[1] != "c" || ids[2] != "b" {
+		t.Fatalf("expected containers newest first, got %v", ids)
+	}
+}
+
+func TestViewIndexes(t *testing.T) {
+	db, err := NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1 := newViewContainer("id1", time.Now())
+	c1.ImageID = "sha256:img1"
+	c1.MountPoints["/data"] = &volume.MountPoint{Name: "vol1", Destination: "/data"}
+	c1.NetworkSettings.Networks = map[string]*networktypes.EndpointSettings{
+		"net1": {NetworkID: "netid1"},
+	}
+	c2 := newViewContainer("id2", time.Now())
+	c2.ImageID = "sha256:img1"
+	c2.MountPoints["/src"] = &volume.MountPoint{Source: "/host/src", Destination: "/src"}
+	for _, c := range []*Container{c1, c2} {
+		if err := db.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := func(what string, ids []string, err error, expected ...string) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := make(map[string]bool)
+		for _, id := range ids {
+			found[id] = true
+		}
+		if len(ids) != len(expected) {
+			t.Fatalf("%s: expected %v, got %v", what, expected, ids)
+		}
+		for _, id := range expected {
+			if !found[id] {
+				t.Fatalf("%s: expected %v, got %v", what, expected, ids)
+			}
+		}
+	}
+
+	view := db.Snapshot()
+	ids, err := view.ByImage("sha256:img1")
+	expect("image", ids, err, "id1", "id2")
+	ids, err = view.ByVolume("vol1")
+	expect("volume name", ids, err, "id1")
+	ids, err = view.ByVolume("/data")
+	expect("volume destination", ids, err, "id1")
+	ids, err = view.ByVolume("/host/src")
+	expect("volume source", ids, err, "id2")
+	ids, err = view.ByNetwork("net1")
+	expect("network name", ids, err, "id1")
+	ids, err = view.ByNetwork("netid1")
+	expect("network id", ids, err, "id1")
+
+	// updates replace the index entries of the container
+	delete(c1.NetworkSettings.Networks, "net1")
+	if err := db.Save(c1); err != nil {
+		t.Fatal(err)
+	}
+	ids, err = db.Snapshot().ByNetwork("net1")
+	expect("network name after disconnect", ids, err)
+
+	if err := db.Delete(c2); err != nil {
+		t.Fatal(err)
+	}
+	view = db.Snapshot()
+	ids, err = view.ByImage("sha256:img1")
+	expect("image after delete", ids, err, "id1")
+	ids, err = view.ByVolume("/host/src")
+	expect("volume after delete", ids, err)
+}
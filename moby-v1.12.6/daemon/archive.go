@@ -15,6 +15,7 @@ import (
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // ErrExtractPointNotDirectory is used to convey that the operation to extract
@@ -24,7 +25,7 @@ var ErrExtractPointNotDirectory = errors.New("extraction point is not a director
 
 // ContainerCopy performs a deprecated operation of archiving the resource at
 // the specified path in the container identified by the given name.
-func (daemon *Daemon) ContainerCopy(name string, res string) (io.ReadCloser, error) {
+func (daemon *Daemon) ContainerCopy(ctx context.Context, name string, res string) (io.ReadCloser, error) {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return nil, err
@@ -34,7 +35,7 @@ func (daemon *Daemon) ContainerCopy(name string, res string) (io.ReadCloser, err
 		res = res[1:]
 	}
 
-	return daemon.containerCopy(container, res)
+	return daemon.containerCopy(ctx, container, res)
 }
 
 // ContainerStatPath stats the filesystem resource at the specified path in the
@@ -51,13 +52,13 @@ func (daemon *Daemon) ContainerStatPath(name string, path string) (stat *types.C
 // ContainerArchivePath creates an archive of the filesystem resource at the
 // specified path in the container identified by the given name. Returns a
 // tar archive of the resource and whether it was a directory or a single file.
-func (daemon *Daemon) ContainerArchivePath(name string, path string) (content io.ReadCloser, stat *types.ContainerPathStat, err error) {
+func (daemon *Daemon) ContainerArchivePath(ctx context.Context, name string, path string) (content io.ReadCloser, stat *types.ContainerPathStat, err error) {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return daemon.containerArchivePath(container, path)
+	return daemon.containerArchivePath(ctx, container, path)
 }
 
 // ContainerExtractToDir extracts the given archive to the specified location
@@ -66,13 +67,13 @@ func (daemon *Daemon) ContainerArchivePath(name string, path string) (content io
 // be ErrExtractPointNotDirectory. If noOverwriteDirNonDir is true then it will
 // be an error if unpacking the given content would cause an existing directory
 // to be replaced with a non-directory and vice versa.
-func (daemon *Daemon) ContainerExtractToDir(name, path string, noOverwriteDirNonDir bool, content io.Reader) error {
+func (daemon *Daemon) ContainerExtractToDir(ctx context.Context, name, path string, noOverwriteDirNonDir bool, content io.Reader) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
 
-	return daemon.containerExtractToDir(container, path, noOverwriteDirNonDir, content)
+	return daemon.containerExtractToDir(ctx, container, path, noOverwriteDirNonDir, content)
 }
 
 // containerStatPath stats the filesystem resource at the specified path in this
@@ -103,7 +104,7 @@ func (daemon *Daemon) containerStatPath(container *container.Container, path str
 // containerArchivePath creates an archive of the filesystem resource at the specified
 // path in this container. Returns a tar archive of the resource and stat info
 // about the resource.
-func (daemon *Daemon) containerArchivePath(container *container.Container, path string) (content io.ReadCloser, stat *types.ContainerPathStat, err error) {
+func (daemon *Daemon) containerArchivePath(ctx context.Context, container *container.Container, path string) (content io.ReadCloser, stat *types.ContainerPathStat, err error) {
 	container.Lock()
 
 	defer func() {
@@ -163,7 +164,7 @@ func (daemon *Daemon) containerArchivePath(container *container.Container, path
 		return err
 	})
 
-	daemon.LogContainerEvent(container, "archive-path")
+	daemon.LogContainerEventWithAttributes(container, "archive-path", requestAttributes(ctx))
 
 	return content, stat, nil
 }
@@ -174,7 +175,7 @@ func (daemon *Daemon) containerArchivePath(container *container.Container, path
 // noOverwriteDirNonDir is true then it will be an error if unpacking the
 // given content would cause an existing directory to be replaced with a non-
 // directory and vice versa.
-func (daemon *Daemon) containerExtractToDir(container *container.Container, path string, noOverwriteDirNonDir bool, content io.Reader) (err error) {
+func (daemon *Daemon) containerExtractToDir(ctx context.Context, container *container.Container, path string, noOverwriteDirNonDir bool, content io.Reader) (err error) {
 	container.Lock()
 	defer container.Unlock()
 
@@ -257,7 +258,7 @@ func (daemon *Daemon) containerExtractToDir(container *container.Container, path
 		return ErrRootFSReadOnly
 	}
 
-	uid, gid := daemon.GetRemappedUIDGID()
+	uid, gid := daemon.containerRemappedUIDGID(container)
 	options := &archive.TarOptions{
 		NoOverwriteDirNonDir: noOverwriteDirNonDir,
 		ChownOpts: &archive.TarChownOptions{
@@ -268,12 +269,12 @@ func (daemon *Daemon) containerExtractToDir(container *container.Container, path
 		return err
 	}
 
-	daemon.LogContainerEvent(container, "extract-to-dir")
+	daemon.LogContainerEventWithAttributes(container, "extract-to-dir", requestAttributes(ctx))
 
 	return nil
 }
 
-func (daemon *Daemon) containerCopy(container *container.Container, resource string) (rc io.ReadCloser, err error) {
+func (daemon *Daemon) containerCopy(ctx context.Context, container *container.Container, resource string) (rc io.ReadCloser, err error) {
 	container.Lock()
 
 	defer func() {
@@ -334,7 +335,7 @@ func (daemon *Daemon) containerCopy(container *container.Container, resource str
 		container.Unlock()
 		return err
 	})
-	daemon.LogContainerEvent(container, "copy")
+	daemon.LogContainerEventWithAttributes(container, "copy", requestAttributes(ctx))
 	return reader, nil
 }
 
@@ -346,7 +347,6 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 	srcPath := src.Path()
 	destExists := true
 	destDir := false
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
 
 	// Work in daemon-local OS specific file paths
 	destPath = filepath.FromSlash(destPath)
@@ -355,6 +355,7 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 	if err != nil {
 		return err
 	}
+	rootUID, rootGID := daemon.containerRemappedUIDGID(c)
 	err = daemon.Mount(c)
 	if err != nil {
 		return err
@@ -384,7 +385,7 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 		destExists = false
 	}
 
-	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	uidMaps, gidMaps := daemon.containerIDMaps(c)
 	archiver := &archive.Archiver{
 		Untar:   chrootarchive.Untar,
 		UIDMaps: uidMaps,
@@ -12,10 +12,11 @@ import (
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
+	"golang.org/x/net/context"
 )
 
 // ContainerAttach attaches to logs according to the config passed in. See ContainerAttachConfig.
-func (daemon *Daemon) ContainerAttach(prefixOrName string, c *backend.ContainerAttachConfig) error {
+func (daemon *Daemon) ContainerAttach(ctx context.Context, prefixOrName string, c *backend.ContainerAttachConfig) error {
 	keys := []byte{}
 	var err error
 	if c.DetachKeys != "" {
@@ -58,22 +59,22 @@ func (daemon *Daemon) ContainerAttach(prefixOrName string, c *backend.ContainerA
 		stderr = errStream
 	}
 
-	if err := daemon.containerAttach(container, stdin, stdout, stderr, c.Logs, c.Stream, keys); err != nil {
+	if err := daemon.containerAttach(ctx, container, stdin, stdout, stderr, c.Logs, c.Stream, keys); err != nil {
 		fmt.Fprintf(outStream, "Error attaching: %s\n", err)
 	}
 	return nil
 }
 
 // ContainerAttachRaw attaches the provided streams to the container's stdio
-func (daemon *Daemon) ContainerAttachRaw(prefixOrName string, stdin io.ReadCloser, stdout, stderr io.Writer, stream bool) error {
+func (daemon *Daemon) ContainerAttachRaw(ctx context.Context, prefixOrName string, stdin io.ReadCloser, stdout, stderr io.Writer, stream bool) error {
 	container, err := daemon.GetContainer(prefixOrName)
 	if err != nil {
 		return err
 	}
-	return daemon.containerAttach(container, stdin, stdout, stderr, false, stream, nil)
+	return daemon.containerAttach(ctx, container, stdin, stdout, stderr, false, stream, nil)
 }
 
-func (daemon *Daemon) containerAttach(c *container.Container, stdin io.ReadCloser, stdout, stderr io.Writer, logs, stream bool, keys []byte) error {
+func (daemon *Daemon) containerAttach(ctx context.Context, c *container.Container, stdin io.ReadCloser, stdout, stderr io.Writer, logs, stream bool, keys []byte) error {
 	if logs {
 		logDriver, err := daemon.getLogger(c)
 		if err != nil {
@@ -105,7 +106,7 @@ func (daemon *Daemon) containerAttach(c *container.Container, stdin io.ReadClose
 		}
 	}
 
-	daemon.LogContainerEvent(c, "attach")
+	daemon.LogContainerEventWithAttributes(c, "attach", requestAttributes(ctx))
 
 	//stream
 	if stream {
@@ -131,7 +132,7 @@ func (daemon *Daemon) containerAttach(c *container.Container, stdin io.ReadClose
 		err := <-c.Attach(stdinPipe, stdout, stderr, keys)
 		if err != nil {
 			if _, ok := err.(container.DetachError); ok {
-				daemon.LogContainerEvent(c, "detach")
+				daemon.LogContainerEventWithAttributes(c, "detach", requestAttributes(ctx))
 			} else {
 				logrus.Errorf("attach failed with error: %v", err)
 			}
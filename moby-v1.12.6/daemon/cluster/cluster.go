@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	stdliberrors "errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,18 +17,24 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/cluster/convert"
 	executorpkg "github.com/docker/docker/daemon/cluster/executor"
 	"github.com/docker/docker/daemon/cluster/executor/container"
+	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/runconfig"
 	apitypes "github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
 	types "github.com/docker/engine-api/types/swarm"
+	timetypes "github.com/docker/engine-api/types/time"
 	swarmagent "github.com/docker/swarmkit/agent"
 	swarmapi "github.com/docker/swarmkit/api"
+	"github.com/docker/swarmkit/manager/encryption"
+	"github.com/docker/swarmkit/protobuf/ptypes"
 	"golang.org/x/net/context"
 )
 
@@ -37,6 +45,9 @@ const swarmRequestTimeout = 20 * time.Second
 const stateFile = "docker-state.json"
 const defaultAddr = "0.0.0.0:2377"
 
+// contextPrefix namespaces the log context attached to service log lines.
+const contextPrefix = "com.docker.swarm"
+
 const (
 	initialReconnectDelay = 100 * time.Millisecond
 	maxReconnectDelay     = 30 * time.Second
@@ -54,6 +65,15 @@ var ErrPendingSwarmExists = fmt.Errorf("This node is processing an existing join
 // ErrSwarmJoinTimeoutReached is returned when cluster join could not complete before timeout was reached.
 var ErrSwarmJoinTimeoutReached = fmt.Errorf("Timeout was reached before node was joined. The attempt to join the swarm will continue in the background. Use the \"docker info\" command to see the current swarm status of your node.")
 
+// ErrSwarmLocked is returned if the swarm is encrypted and needs a key to unlock it.
+var ErrSwarmLocked = fmt.Errorf("Swarm is encrypted and needs to be unlocked before it can be used. Please use \"docker swarm unlock\" to unlock it.")
+
+// ErrSwarmNotLocked is returned on unlock request for a swarm that is not locked.
+var ErrSwarmNotLocked = fmt.Errorf("Swarm is not locked.")
+
+// ErrInvalidUnlockKey is returned if the key provided to unlock the swarm is invalid.
+var ErrInvalidUnlockKey = fmt.Errorf("swarm could not be unlocked: invalid key provided")
+
 // defaultSpec contains some sane defaults if cluster options are missing on init
 var defaultSpec = types.Spec{
 	Raft: types.RaftConfig{
@@ -122,6 +142,8 @@ type Cluster struct {
 	stop            bool
 	err             error
 	cancelDelay     func()
+	locked          bool
+	unlockKey       []byte // kept to restart the node after a failure, not persisted
 }
 
 type node struct {
@@ -153,8 +175,14 @@ func New(config Config) (*Cluster, error) {
 		return nil, err
 	}
 
-	n, err := c.startNewNode(false, st.LocalAddr, st.RemoteAddr, st.ListenAddr, st.AdvertiseAddr, "", "")
+	n, err := c.startNewNode(false, false, st.LocalAddr, st.RemoteAddr, st.ListenAddr, st.AdvertiseAddr, "", "")
 	if err != nil {
+		if err == swarmagent.ErrInvalidUnlockKey {
+			// the node stays down until "docker swarm unlock" provides
+			// the key it is encrypted with.
+			c.locked = true
+			return c, nil
+		}
 		return nil, err
 	}
 
@@ -163,6 +191,10 @@ func New(config Config) (*Cluster, error) {
 		logrus.Errorf("swarm component could not be started before timeout was reached")
 	case <-n.Ready():
 	case <-n.done:
+		if c.err == swarmagent.ErrInvalidUnlockKey {
+			c.locked = true
+			return c, nil
+		}
 		return nil, fmt.Errorf("swarm component could not be started: %v", c.err)
 	}
 	go c.reconnectOnFailure(n)
@@ -209,6 +241,13 @@ func (c *Cluster) reconnectOnFailure(n *node) {
 			c.Unlock()
 			return
 		}
+		if c.err == swarmagent.ErrInvalidUnlockKey {
+			// the unlock key was rotated by another manager, retrying
+			// with the key kept cannot succeed.
+			c.lock()
+			c.Unlock()
+			return
+		}
 		n.reconnectDelay *= 2
 		if n.reconnectDelay > maxReconnectDelay {
 			n.reconnectDelay = maxReconnectDelay
@@ -227,16 +266,28 @@ func (c *Cluster) reconnectOnFailure(n *node) {
 			return
 		}
 		var err error
-		n, err = c.startNewNode(false, c.localAddr, c.getRemoteAddress(), c.listenAddr, c.advertiseAddr, c.getRemoteAddress(), "")
+		n, err = c.startNewNode(false, false, c.localAddr, c.getRemoteAddress(), c.listenAddr, c.advertiseAddr, c.getRemoteAddress(), "")
 		if err != nil {
 			c.err = err
+			if err == swarmagent.ErrInvalidUnlockKey {
+				c.lock()
+				c.Unlock()
+				return
+			}
 			close(n.done)
 		}
 		c.Unlock()
 	}
 }
 
-func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, listenAddr, advertiseAddr, joinAddr, joinToken string) (*node, error) {
+// lock marks the node as locked: it stays down until "docker swarm unlock"
+// provides the key it is encrypted with. Call with the write lock held.
+func (c *Cluster) lock() {
+	c.locked = true
+	c.unlockKey = nil
+}
+
+func (c *Cluster) startNewNode(forceNewCluster, autoLockManagers bool, localAddr, remoteAddr, listenAddr, advertiseAddr, joinAddr, joinToken string) (*node, error) {
 	if err := c.config.Backend.IsSwarmCompatible(); err != nil {
 		return nil, err
 	}
@@ -285,6 +336,8 @@ func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, list
 		Executor:           container.NewExecutor(c.config.Backend),
 		HeartbeatTick:      1,
 		ElectionTick:       3,
+		AutoLockManagers:   autoLockManagers,
+		UnlockKey:          c.unlockKey,
 	})
 	if err != nil {
 		return nil, err
@@ -353,6 +406,10 @@ func (c *Cluster) startNewNode(forceNewCluster bool, localAddr, remoteAddr, list
 // Init initializes new cluster from user provided request.
 func (c *Cluster) Init(req types.InitRequest) (string, error) {
 	c.Lock()
+	if c.locked {
+		c.Unlock()
+		return "", ErrSwarmLocked
+	}
 	if node := c.node; node != nil {
 		if !req.ForceNewCluster {
 			c.Unlock()
@@ -368,6 +425,7 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 		c.Unlock()
 		return "", err
 	}
+	req.Spec.EncryptionConfig.AutoLockManagers = req.AutoLockManagers
 
 	listenHost, listenPort, err := resolveListenAddr(req.ListenAddr)
 	if err != nil {
@@ -411,7 +469,7 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 	}
 
 	// todo: check current state existing
-	n, err := c.startNewNode(req.ForceNewCluster, localAddr, "", net.JoinHostPort(listenHost, listenPort), net.JoinHostPort(advertiseHost, advertisePort), "", "")
+	n, err := c.startNewNode(req.ForceNewCluster, req.AutoLockManagers, localAddr, "", net.JoinHostPort(listenHost, listenPort), net.JoinHostPort(advertiseHost, advertisePort), "", "")
 	if err != nil {
 		c.Unlock()
 		return "", err
@@ -420,9 +478,13 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 
 	select {
 	case <-n.Ready():
-		if err := initClusterSpec(n, req.Spec); err != nil {
+		unlockKey, err := initClusterSpec(n, req.Spec)
+		if err != nil {
 			return "", err
 		}
+		c.Lock()
+		c.unlockKey = unlockKey
+		c.Unlock()
 		go c.reconnectOnFailure(n)
 		return n.NodeID(), nil
 	case <-n.done:
@@ -440,6 +502,10 @@ func (c *Cluster) Init(req types.InitRequest) (string, error) {
 // Join makes current Cluster part of an existing swarm cluster.
 func (c *Cluster) Join(req types.JoinRequest) error {
 	c.Lock()
+	if c.locked {
+		c.Unlock()
+		return ErrSwarmLocked
+	}
 	if node := c.node; node != nil {
 		c.Unlock()
 		return ErrSwarmExists
@@ -466,7 +532,7 @@ func (c *Cluster) Join(req types.JoinRequest) error {
 	}
 
 	// todo: check current state existing
-	n, err := c.startNewNode(false, "", req.RemoteAddrs[0], net.JoinHostPort(listenHost, listenPort), advertiseAddr, req.RemoteAddrs[0], req.JoinToken)
+	n, err := c.startNewNode(false, false, "", req.RemoteAddrs[0], net.JoinHostPort(listenHost, listenPort), advertiseAddr, req.RemoteAddrs[0], req.JoinToken)
 	if err != nil {
 		c.Unlock()
 		return err
@@ -488,6 +554,79 @@ func (c *Cluster) Join(req types.JoinRequest) error {
 	}
 }
 
+// GetUnlockKey returns the unlock key for the swarm.
+func (c *Cluster) GetUnlockKey() (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if !c.isActiveManager() {
+		return "", c.errNoManager()
+	}
+
+	ctx, cancel := c.getRequestContext()
+	defer cancel()
+
+	r, err := c.client.GetUnlockKey(ctx, &swarmapi.GetUnlockKeyRequest{})
+	if err != nil {
+		return "", err
+	}
+
+	if len(r.UnlockKey) == 0 {
+		// no key
+		return "", nil
+	}
+
+	return encryption.HumanReadableKey(r.UnlockKey), nil
+}
+
+// UnlockSwarm provides a key to decrypt data that is encrypted at rest.
+func (c *Cluster) UnlockSwarm(req types.UnlockRequest) error {
+	c.Lock()
+	if !c.locked {
+		c.Unlock()
+		return ErrSwarmNotLocked
+	}
+
+	key, err := encryption.ParseHumanReadableKey(req.UnlockKey)
+	if err != nil {
+		c.Unlock()
+		return ErrInvalidUnlockKey
+	}
+
+	st, err := c.loadState()
+	if err != nil {
+		c.Unlock()
+		return err
+	}
+
+	c.unlockKey = key
+	n, err := c.startNewNode(false, false, st.LocalAddr, st.RemoteAddr, st.ListenAddr, st.AdvertiseAddr, "", "")
+	if err != nil {
+		c.unlockKey = nil
+		c.Unlock()
+		if err == swarmagent.ErrInvalidUnlockKey {
+			return ErrInvalidUnlockKey
+		}
+		return err
+	}
+	c.locked = false
+	c.Unlock()
+
+	select {
+	case <-n.Ready():
+	case <-n.done:
+		c.Lock()
+		defer c.Unlock()
+		if c.err == swarmagent.ErrInvalidUnlockKey {
+			c.lock()
+			return ErrInvalidUnlockKey
+		}
+		return c.err
+	}
+	go c.reconnectOnFailure(n)
+	return nil
+}
+
 // stopNode is a helper that stops the active c.node and waits until it has
 // shut down. Call while keeping the cluster lock.
 func (c *Cluster) stopNode() error {
@@ -517,6 +656,17 @@ func (c *Cluster) Leave(force bool) error {
 	c.Lock()
 	node := c.node
 	if node == nil {
+		if c.locked {
+			if !force {
+				c.Unlock()
+				return fmt.Errorf("Swarm is encrypted and locked. Leaving it erases the swarm state stored on this node, including any manager data. Use `--force` to ignore this message.")
+			}
+			c.locked = false
+			c.Unlock()
+			// the node can't be started without its key, so there are
+			// no containers to remove.
+			return c.clearState()
+		}
 		c.Unlock()
 		return ErrNoSwarm
 	}
@@ -550,7 +700,7 @@ func (c *Cluster) Leave(force bool) error {
 	c.Unlock()
 	if nodeID := node.NodeID(); nodeID != "" {
 		for _, id := range c.config.Backend.ListContainersForNode(nodeID) {
-			if err := c.config.Backend.ContainerRm(id, &apitypes.ContainerRmConfig{ForceRemove: true}); err != nil {
+			if err := c.config.Backend.ContainerRm(context.Background(), id, &apitypes.ContainerRmConfig{ForceRemove: true}); err != nil {
 				logrus.Errorf("error removing %v: %v", id, err)
 			}
 		}
@@ -605,6 +755,15 @@ func (c *Cluster) Inspect() (types.Swarm, error) {
 
 // Update updates configuration of a managed swarm cluster.
 func (c *Cluster) Update(version uint64, spec types.Spec, flags types.UpdateFlags) error {
+	if err := c.updateSwarm(version, spec, flags); err != nil {
+		return err
+	}
+	// the update may have rotated the unlock key, or enabled or disabled
+	// autolock: keep the current key to restart the node after a failure.
+	return c.updateUnlockKey()
+}
+
+func (c *Cluster) updateSwarm(version uint64, spec types.Spec, flags types.UpdateFlags) error {
 	c.RLock()
 	defer c.RUnlock()
 
@@ -634,14 +793,36 @@ func (c *Cluster) Update(version uint64, spec types.Spec, flags types.UpdateFlag
 				Index: version,
 			},
 			Rotation: swarmapi.JoinTokenRotation{
-				RotateWorkerToken:  flags.RotateWorkerToken,
-				RotateManagerToken: flags.RotateManagerToken,
+				RotateWorkerToken:      flags.RotateWorkerToken,
+				RotateManagerToken:     flags.RotateManagerToken,
+				RotateManagerUnlockKey: flags.RotateManagerUnlockKey,
 			},
 		},
 	)
 	return err
 }
 
+// updateUnlockKey keeps the key the managers of the swarm are locked with.
+func (c *Cluster) updateUnlockKey() error {
+	c.RLock()
+	if !c.isActiveManager() {
+		c.RUnlock()
+		return c.errNoManager()
+	}
+	ctx, cancel := c.getRequestContext()
+	r, err := c.client.GetUnlockKey(ctx, &swarmapi.GetUnlockKeyRequest{})
+	cancel()
+	c.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	c.unlockKey = r.UnlockKey
+	c.Unlock()
+	return nil
+}
+
 // IsManager returns true if Cluster is participating as a manager.
 func (c *Cluster) IsManager() bool {
 	c.RLock()
@@ -724,6 +905,9 @@ func (c *Cluster) Info() types.Info {
 		if c.cancelDelay != nil {
 			info.LocalNodeState = types.LocalNodeStateError
 		}
+		if c.locked {
+			info.LocalNodeState = types.LocalNodeStateLocked
+		}
 	} else {
 		info.LocalNodeState = types.LocalNodeStatePending
 		if c.ready == true {
@@ -776,6 +960,9 @@ func (c *Cluster) isActiveManager() bool {
 // Call with read lock.
 func (c *Cluster) errNoManager() error {
 	if c.node == nil {
+		if c.locked {
+			return ErrSwarmLocked
+		}
 		return fmt.Errorf("This node is not a swarm manager. Use \"docker swarm init\" or \"docker swarm join\" to connect this node to swarm and try again.")
 	}
 	if c.node.Manager() != nil {
@@ -874,7 +1061,7 @@ func (c *Cluster) GetService(input string) (types.Service, error) {
 }
 
 // UpdateService updates existing service to match new properties.
-func (c *Cluster) UpdateService(serviceID string, version uint64, spec types.ServiceSpec, encodedAuth string) error {
+func (c *Cluster) UpdateService(serviceID string, version uint64, spec types.ServiceSpec, flags apitypes.ServiceUpdateOptions) error {
 	c.RLock()
 	defer c.RUnlock()
 
@@ -885,29 +1072,43 @@ func (c *Cluster) UpdateService(serviceID string, version uint64, spec types.Ser
 	ctx, cancel := c.getRequestContext()
 	defer cancel()
 
-	err := c.populateNetworkID(ctx, c.client, &spec)
+	currentService, err := getService(ctx, c.client, serviceID)
 	if err != nil {
 		return err
 	}
 
-	serviceSpec, err := convert.ServiceSpecToGRPC(spec)
-	if err != nil {
-		return err
+	var serviceSpec swarmapi.ServiceSpec
+	switch flags.Rollback {
+	case "":
+		err := c.populateNetworkID(ctx, c.client, &spec)
+		if err != nil {
+			return err
+		}
+
+		serviceSpec, err = convert.ServiceSpecToGRPC(spec)
+		if err != nil {
+			return err
+		}
+	case "previous":
+		// The previous spec kept by the cluster is restored, including
+		// the registry authentication it was created with.
+		if currentService.PreviousSpec == nil {
+			return fmt.Errorf("service %s does not have a previous spec", serviceID)
+		}
+		serviceSpec = *currentService.PreviousSpec.Copy()
+	default:
+		return fmt.Errorf("unrecognized rollback option %s", flags.Rollback)
 	}
 
-	if encodedAuth != "" {
+	if flags.EncodedRegistryAuth != "" {
 		ctnr := serviceSpec.Task.GetContainer()
 		if ctnr == nil {
 			return fmt.Errorf("service does not use container tasks")
 		}
-		ctnr.PullOptions = &swarmapi.ContainerSpec_PullOptions{RegistryAuth: encodedAuth}
-	} else {
+		ctnr.PullOptions = &swarmapi.ContainerSpec_PullOptions{RegistryAuth: flags.EncodedRegistryAuth}
+	} else if flags.Rollback == "" {
 		// this is needed because if the encodedAuth isn't being updated then we
 		// shouldn't lose it, and continue to use the one that was already present
-		currentService, err := getService(ctx, c.client, serviceID)
-		if err != nil {
-			return err
-		}
 		ctnr := currentService.Spec.Task.GetContainer()
 		if ctnr == nil {
 			return fmt.Errorf("service does not use container tasks")
@@ -951,6 +1152,122 @@ func (c *Cluster) RemoveService(input string) error {
 	return nil
 }
 
+// ServiceLogs collects the logs of the tasks of a service and writes them to
+// config.OutStream, multiplexed by stream. Each line is prefixed with the
+// node, service and task it came from.
+func (c *Cluster) ServiceLogs(ctx context.Context, input string, config *backend.ContainerLogsConfig, started chan struct{}) error {
+	if !(config.ShowStdout || config.ShowStderr) {
+		return fmt.Errorf("You must choose at least one stream")
+	}
+
+	options, err := logSubscriptionOptions(config)
+	if err != nil {
+		return err
+	}
+
+	c.RLock()
+	if !c.isActiveManager() {
+		c.RUnlock()
+		return c.errNoManager()
+	}
+
+	requestCtx, cancel := c.getRequestContext()
+	service, err := getService(requestCtx, c.client, input)
+	cancel()
+	if err != nil {
+		c.RUnlock()
+		return err
+	}
+
+	stream, err := swarmapi.NewLogsClient(c.conn).SubscribeLogs(ctx, &swarmapi.SubscribeLogsRequest{
+		Selector: &swarmapi.LogSelector{
+			ServiceIDs: []string{service.ID},
+		},
+		Options: options,
+	})
+	c.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	wf := ioutils.NewWriteFlusher(config.OutStream)
+	defer wf.Close()
+	close(started)
+	wf.Flush()
+
+	outStream := stdcopy.NewStdWriter(wf, stdcopy.Stdout)
+	errStream := stdcopy.NewStdWriter(wf, stdcopy.Stderr)
+
+	for {
+		subscribeMsg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range subscribeMsg.Messages {
+			var data []byte
+			if config.Timestamps {
+				ts, err := ptypes.Timestamp(msg.Timestamp)
+				if err != nil {
+					return err
+				}
+				data = append(data, []byte(ts.Format(logger.TimeFormat)+" ")...)
+			}
+
+			data = append(data, []byte(fmt.Sprintf("%s.node.id=%s,%s.service.id=%s,%s.task.id=%s ",
+				contextPrefix, msg.Context.NodeID,
+				contextPrefix, msg.Context.ServiceID,
+				contextPrefix, msg.Context.TaskID,
+			))...)
+			data = append(data, msg.Data...)
+
+			switch msg.Stream {
+			case swarmapi.LogStreamStdout:
+				outStream.Write(data)
+			case swarmapi.LogStreamStderr:
+				errStream.Write(data)
+			}
+		}
+	}
+}
+
+// logSubscriptionOptions converts the container logs options into the options
+// of a swarm log subscription.
+func logSubscriptionOptions(config *backend.ContainerLogsConfig) (*swarmapi.LogSubscriptionOptions, error) {
+	options := &swarmapi.LogSubscriptionOptions{
+		Follow: config.Follow,
+	}
+	if config.ShowStdout {
+		options.Streams = append(options.Streams, swarmapi.LogStreamStdout)
+	}
+	if config.ShowStderr {
+		options.Streams = append(options.Streams, swarmapi.LogStreamStderr)
+	}
+
+	// swarm counts the tail from the end of the logs offset by one, zero
+	// meaning all logs. Like container logs, anything but a count is all.
+	if n, err := strconv.Atoi(config.Tail); err == nil && n >= 0 {
+		options.Tail = int64(-n) - 1
+	}
+
+	if config.Since != "" {
+		s, n, err := timetypes.ParseTimestamps(config.Since, 0)
+		if err != nil {
+			return nil, err
+		}
+		since, err := ptypes.TimestampProto(time.Unix(s, n))
+		if err != nil {
+			return nil, err
+		}
+		options.Since = since
+	}
+
+	return options, nil
+}
+
 // GetNodes returns a list of all nodes known to a cluster.
 func (c *Cluster) GetNodes(options apitypes.NodeListOptions) ([]types.Node, error) {
 	c.RLock()
@@ -1379,11 +1696,13 @@ func validateAddr(addr string) (string, error) {
 	return strings.TrimPrefix(newaddr, "tcp://"), nil
 }
 
-func initClusterSpec(node *node, spec types.Spec) error {
+// initClusterSpec sets the spec of a new swarm, and returns the key its
+// managers are locked with, if any.
+func initClusterSpec(node *node, spec types.Spec) ([]byte, error) {
 	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
 	for conn := range node.ListenControlSocket(ctx) {
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return nil, ctx.Err()
 		}
 		if conn != nil {
 			client := swarmapi.NewControlClient(conn)
@@ -1391,21 +1710,21 @@ func initClusterSpec(node *node, spec types.Spec) error {
 			for i := 0; ; i++ {
 				lcr, err := client.ListClusters(ctx, &swarmapi.ListClustersRequest{})
 				if err != nil {
-					return fmt.Errorf("error on listing clusters: %v", err)
+					return nil, fmt.Errorf("error on listing clusters: %v", err)
 				}
 				if len(lcr.Clusters) == 0 {
 					if i < 10 {
 						time.Sleep(200 * time.Millisecond)
 						continue
 					}
-					return fmt.Errorf("empty list of clusters was returned")
+					return nil, fmt.Errorf("empty list of clusters was returned")
 				}
 				cluster = lcr.Clusters[0]
 				break
 			}
 			newspec, err := convert.SwarmSpecToGRPC(spec)
 			if err != nil {
-				return fmt.Errorf("error updating cluster settings: %v", err)
+				return nil, fmt.Errorf("error updating cluster settings: %v", err)
 			}
 			_, err = client.UpdateCluster(ctx, &swarmapi.UpdateClusterRequest{
 				ClusterID:      cluster.ID,
@@ -1413,10 +1732,14 @@ func initClusterSpec(node *node, spec types.Spec) error {
 				Spec:           &newspec,
 			})
 			if err != nil {
-				return fmt.Errorf("error updating cluster settings: %v", err)
+				return nil, fmt.Errorf("error updating cluster settings: %v", err)
 			}
-			return nil
+			r, err := client.GetUnlockKey(ctx, &swarmapi.GetUnlockKeyRequest{})
+			if err != nil {
+				return nil, fmt.Errorf("error getting the unlock key: %v", err)
+			}
+			return r.UnlockKey, nil
 		}
 	}
-	return ctx.Err()
+	return nil, ctx.Err()
 }
@@ -0,0 +1,313 @@
+package cluster
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/docker/docker/api/types/backend"
+	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	clustertypes "github.com/docker/docker/daemon/cluster/provider"
+	"github.com/docker/docker/pkg/stdcopy"
+	apitypes "github.com/docker/engine-api/types"
+	types "github.com/docker/engine-api/types/swarm"
+	"github.com/docker/libnetwork/cluster"
+	networktypes "github.com/docker/libnetwork/types"
+	swarmapi "github.com/docker/swarmkit/api"
+	"github.com/docker/swarmkit/manager/encryption"
+	"github.com/docker/swarmkit/protobuf/ptypes"
+	"golang.org/x/net/context"
+)
+
+func TestLogSubscriptionOptions(t *testing.T) {
+	since, err := ptypes.TimestampProto(time.Unix(1476000000, 500000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		options  apitypes.ContainerLogsOptions
+		expected *swarmapi.LogSubscriptionOptions
+	}{
+		{
+			options: apitypes.ContainerLogsOptions{ShowStdout: true, Tail: "all"},
+			expected: &swarmapi.LogSubscriptionOptions{
+				Streams: []swarmapi.LogStream{swarmapi.LogStreamStdout},
+			},
+		},
+		{
+			options: apitypes.ContainerLogsOptions{ShowStderr: true, Follow: true},
+			expected: &swarmapi.LogSubscriptionOptions{
+				Streams: []swarmapi.LogStream{swarmapi.LogStreamStderr},
+				Follow:  true,
+			},
+		},
+		{
+			options: apitypes.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Tail: "0"},
+			expected: &swarmapi.LogSubscriptionOptions{
+				Streams: []swarmapi.LogStream{swarmapi.LogStreamStdout, swarmapi.LogStreamStderr},
+				Tail:    -1,
+			},
+		},
+		{
+			options: apitypes.ContainerLogsOptions{ShowStdout: true, Tail: "10"},
+			expected: &swarmapi.LogSubscriptionOptions{
+				Streams: []swarmapi.LogStream{swarmapi.LogStreamStdout},
+				Tail:    -11,
+			},
+		},
+		{
+			options: apitypes.ContainerLogsOptions{ShowStdout: true, Tail: "-5"},
+			expected: &swarmapi.LogSubscriptionOptions{
+				Streams: []swarmapi.LogStream{swarmapi.LogStreamStdout},
+			},
+		},
+		{
+			options: apitypes.ContainerLogsOptions{ShowStdout: true, Since: "1476000000.5"},
+			expected: &swarmapi.LogSubscriptionOptions{
+				Streams: []swarmapi.LogStream{swarmapi.LogStreamStdout},
+				Since:   since,
+			},
+		},
+	} {
+		options, err := logSubscriptionOptions(&backend.ContainerLogsConfig{ContainerLogsOptions: c.options})
+		if err != nil {
+			t.Fatalf("%+v: %v", c.options, err)
+		}
+		if !reflect.DeepEqual(options, c.expected) {
+			t.Errorf("%+v: expected subscription options %+v, got %+v", c.options, c.expected, options)
+		}
+	}
+
+	if _, err := logSubscriptionOptions(&backend.ContainerLogsConfig{
+		ContainerLogsOptions: apitypes.ContainerLogsOptions{ShowStdout: true, Since: "yesterday"},
+	}); err == nil {
+		t.Fatal("expected an invalid since to be rejected")
+	}
+}
+
+// fakeControl serves the service it holds, by ID or name.
+type fakeControl struct {
+	swarmapi.ControlServer
+	service *swarmapi.Service
+}
+
+func (f *fakeControl) GetService(ctx context.Context, request *swarmapi.GetServiceRequest) (*swarmapi.GetServiceResponse, error) {
+	if request.ServiceID != f.service.ID {
+		return nil, grpc.Errorf(codes.NotFound, "service %s not found", request.ServiceID)
+	}
+	return &swarmapi.GetServiceResponse{Service: f.service}, nil
+}
+
+func (f *fakeControl) ListServices(ctx context.Context, request *swarmapi.ListServicesRequest) (*swarmapi.ListServicesResponse, error) {
+	response := &swarmapi.ListServicesResponse{}
+	if request.Filters != nil {
+		for _, name := range request.Filters.Names {
+			if name == f.service.Spec.Annotations.Name {
+				response.Services = append(response.Services, f.service)
+			}
+		}
+	}
+	return response, nil
+}
+
+// fakeLogs sends its messages to the subscribers and records their
+// requests.
+type fakeLogs struct {
+	requests chan *swarmapi.SubscribeLogsRequest
+	messages []swarmapi.LogMessage
+}
+
+func (f *fakeLogs) SubscribeLogs(request *swarmapi.SubscribeLogsRequest, stream swarmapi.Logs_SubscribeLogsServer) error {
+	f.requests <- request
+	return stream.Send(&swarmapi.SubscribeLogsMessage{Messages: f.messages})
+}
+
+func TestServiceLogs(t *testing.T) {
+	ts := time.Unix(1476000000, 0).UTC()
+	tsProto, err := ptypes.TimestampProto(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logContext := swarmapi.LogContext{ServiceID: "service1", NodeID: "node1", TaskID: "task1"}
+
+	logs := &fakeLogs{
+		requests: make(chan *swarmapi.SubscribeLogsRequest, 1),
+		messages: []swarmapi.LogMessage{
+			{Context: logContext, Timestamp: tsProto, Stream: swarmapi.LogStreamStdout, Data: []byte("out\n")},
+			{Context: logContext, Timestamp: tsProto, Stream: swarmapi.LogStreamStderr, Data: []byte("err\n")},
+		},
+	}
+	server := grpc.NewServer()
+	swarmapi.RegisterControlServer(server, &fakeControl{service: &swarmapi.Service{
+		ID:   "service1",
+		Spec: swarmapi.ServiceSpec{Annotations: swarmapi.Annotations{Name: "web"}},
+	}})
+	swarmapi.RegisterLogsServer(server, logs)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(l)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := &Cluster{node: &node{conn: conn, client: swarmapi.NewControlClient(conn)}}
+
+	var buf bytes.Buffer
+	config := &backend.ContainerLogsConfig{
+		ContainerLogsOptions: apitypes.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Timestamps: true,
+			Tail:       "2",
+		},
+		OutStream: &buf,
+	}
+	if err := c.ServiceLogs(context.Background(), "web", config, make(chan struct{})); err != nil {
+		t.Fatal(err)
+	}
+
+	request := <-logs.requests
+	if !reflect.DeepEqual(request.Selector.ServiceIDs, []string{"service1"}) {
+		t.Fatalf("expected the logs of service1 to be requested, got selector %+v", request.Selector)
+	}
+	if request.Options.Tail != -3 {
+		t.Fatalf("expected a tail of -3, got %d", request.Options.Tail)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, &buf); err != nil {
+		t.Fatal(err)
+	}
+	prefix := "2016-10-09T08:00:00.000000000Z com.docker.swarm.node.id=node1,com.docker.swarm.service.id=service1,com.docker.swarm.task.id=task1 "
+	if expected := prefix + "out\n"; stdout.String() != expected {
+		t.Fatalf("expected stdout %q, got %q", expected, stdout.String())
+	}
+	if expected := prefix + "err\n"; stderr.String() != expected {
+		t.Fatalf("expected stderr %q, got %q", expected, stderr.String())
+	}
+}
+
+func TestServiceLogsErrors(t *testing.T) {
+	c := &Cluster{}
+
+	config := &backend.ContainerLogsConfig{OutStream: &bytes.Buffer{}}
+	if err := c.ServiceLogs(context.Background(), "web", config, make(chan struct{})); err == nil {
+		t.Fatal("expected logs without any stream to be rejected")
+	}
+
+	config.ShowStdout = true
+	if err := c.ServiceLogs(context.Background(), "web", config, make(chan struct{})); err == nil {
+		t.Fatal("expected logs to be rejected on a node which is not a manager")
+	}
+}
+
+// swarmBackend runs a node without any task.
+type swarmBackend struct {
+	executorpkg.Backend
+}
+
+func (b *swarmBackend) IsSwarmCompatible() error            { return nil }
+func (b *swarmBackend) SetClusterProvider(cluster.Provider) {}
+func (b *swarmBackend) SystemInfo() (*apitypes.Info, error) {
+	return &apitypes.Info{Name: "node1", OSType: "linux", Architecture: "x86_64"}, nil
+}
+func (b *swarmBackend) SetupIngress(clustertypes.NetworkCreateRequest, string) error {
+	return nil
+}
+func (b *swarmBackend) SetNetworkBootstrapKeys([]*networktypes.EncryptionKey) error {
+	return nil
+}
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestUnlockSwarm(t *testing.T) {
+	root, err := ioutil.TempDir("", "docker-cluster-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	config := Config{Root: root, Name: "node1", Backend: &swarmBackend{}}
+
+	c, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := freeAddr(t)
+	if _, err := c.Init(types.InitRequest{
+		ListenAddr:       addr,
+		AdvertiseAddr:    addr,
+		AutoLockManagers: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c.RLock()
+	key := c.unlockKey
+	c.RUnlock()
+	if key == nil {
+		t.Fatal("expected the unlock key to be kept after init")
+	}
+	c.Cleanup()
+
+	// the restarted node stays locked until it is given its key.
+	c, err = New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Cleanup()
+	if !c.locked {
+		t.Fatal("expected the swarm to be locked after a restart")
+	}
+
+	wrongKey := encryption.HumanReadableKey(encryption.GenerateSecretKey())
+	if err := c.UnlockSwarm(types.UnlockRequest{UnlockKey: wrongKey}); err != ErrInvalidUnlockKey {
+		t.Fatalf("expected unlocking with a wrong key to fail with %v, got %v", ErrInvalidUnlockKey, err)
+	}
+	if !c.locked || c.unlockKey != nil {
+		t.Fatal("expected the swarm to stay locked after a wrong key")
+	}
+
+	if err := c.UnlockSwarm(types.UnlockRequest{UnlockKey: encryption.HumanReadableKey(key)}); err != nil {
+		t.Fatal(err)
+	}
+	c.RLock()
+	if c.locked || !bytes.Equal(c.unlockKey, key) {
+		c.RUnlock()
+		t.Fatal("expected the swarm to be unlocked with its key")
+	}
+	c.RUnlock()
+
+	// a rotation replaces the key kept to restart the node.
+	swarm, err := c.Inspect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(swarm.Version.Index, swarm.Spec, types.UpdateFlags{RotateManagerUnlockKey: true}); err != nil {
+		t.Fatal(err)
+	}
+	c.RLock()
+	defer c.RUnlock()
+	if c.unlockKey == nil || bytes.Equal(c.unlockKey, key) {
+		t.Fatal("expected the rotated unlock key to be kept")
+	}
+}
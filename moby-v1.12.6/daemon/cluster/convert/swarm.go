@@ -26,6 +26,9 @@ func SwarmFromGRPC(c swarmapi.Cluster) types.Swarm {
 					HeartbeatTick:              c.Spec.Raft.HeartbeatTick,
 					ElectionTick:               c.Spec.Raft.ElectionTick,
 				},
+				EncryptionConfig: types.EncryptionConfig{
+					AutoLockManagers: c.Spec.EncryptionConfig.AutoLockManagers,
+				},
 			},
 		},
 		JoinTokens: types.JoinTokens{
@@ -82,6 +85,9 @@ func SwarmSpecToGRPC(s types.Spec) (swarmapi.ClusterSpec, error) {
 		CAConfig: swarmapi.CAConfig{
 			NodeCertExpiry: ptypes.DurationProto(s.CAConfig.NodeCertExpiry),
 		},
+		EncryptionConfig: swarmapi.EncryptionConfig{
+			AutoLockManagers: s.EncryptionConfig.AutoLockManagers,
+		},
 	}
 
 	for _, ca := range s.CAConfig.ExternalCAs {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	clustertypes "github.com/docker/docker/daemon/cluster/provider"
+	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
 	"github.com/docker/engine-api/types/events"
@@ -24,16 +25,19 @@ type Backend interface {
 	SetupIngress(req clustertypes.NetworkCreateRequest, nodeIP string) error
 	PullImage(ctx context.Context, image, tag string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error
 	CreateManagedContainer(config types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
-	ContainerStart(name string, hostConfig *container.HostConfig) error
-	ContainerStop(name string, seconds int) error
-	ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error
+	ContainerStart(ctx context.Context, name string, hostConfig *container.HostConfig) error
+	ContainerStop(ctx context.Context, name string, seconds int) error
+	ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, endpointConfig *network.EndpointSettings) error
 	UpdateContainerServiceConfig(containerName string, serviceConfig *clustertypes.ServiceConfig) error
 	ContainerInspectCurrent(name string, size bool) (*types.ContainerJSON, error)
 	ContainerWaitWithContext(ctx context.Context, name string) error
-	ContainerRm(name string, config *types.ContainerRmConfig) error
-	ContainerKill(name string, sig uint64) error
+	ContainerRm(ctx context.Context, name string, config *types.ContainerRmConfig) error
+	ContainerKill(ctx context.Context, name string, sig uint64) error
+	ContainerLogMessages(ctx context.Context, name string, config logger.ReadConfig, fn func(*logger.Message) error) error
 	SystemInfo() (*types.Info, error)
-	VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error)
+	VolumeCreate(ctx context.Context, name, driverName string, opts, labels map[string]string) (*types.Volume, error)
+	VolumeInspect(name string, size bool) (*types.Volume, error)
+	VolumeDriverScope(name string) (string, error)
 	ListContainersForNode(nodeID string) []string
 	SetNetworkBootstrapKeys([]*networktypes.EncryptionKey) error
 	SetClusterProvider(provider cluster.Provider)
@@ -11,11 +11,15 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/events"
 	"github.com/docker/libnetwork"
+	"github.com/docker/swarmkit/agent/exec"
 	"github.com/docker/swarmkit/api"
 	"github.com/docker/swarmkit/log"
+	"github.com/docker/swarmkit/protobuf/ptypes"
 	"golang.org/x/net/context"
 )
 
@@ -134,7 +138,7 @@ func (c *containerAdapter) create(ctx context.Context, backend executorpkg.Backe
 
 	if nc != nil {
 		for n, ep := range nc.EndpointsConfig {
-			if err := backend.ConnectContainerToNetwork(cr.ID, n, ep); err != nil {
+			if err := backend.ConnectContainerToNetwork(ctx, cr.ID, n, ep); err != nil {
 				return err
 			}
 		}
@@ -148,7 +152,7 @@ func (c *containerAdapter) create(ctx context.Context, backend executorpkg.Backe
 }
 
 func (c *containerAdapter) start(ctx context.Context) error {
-	return c.backend.ContainerStart(c.container.name(), nil)
+	return c.backend.ContainerStart(ctx, c.container.name(), nil)
 }
 
 func (c *containerAdapter) inspect(ctx context.Context) (types.ContainerJSON, error) {
@@ -209,20 +213,83 @@ func (c *containerAdapter) shutdown(ctx context.Context) error {
 	if spec.StopGracePeriod != nil {
 		stopgrace = int(spec.StopGracePeriod.Seconds)
 	}
-	return c.backend.ContainerStop(c.container.name(), stopgrace)
+	return c.backend.ContainerStop(ctx, c.container.name(), stopgrace)
 }
 
 func (c *containerAdapter) terminate(ctx context.Context) error {
-	return c.backend.ContainerKill(c.container.name(), uint64(syscall.SIGKILL))
+	return c.backend.ContainerKill(ctx, c.container.name(), uint64(syscall.SIGKILL))
 }
 
 func (c *containerAdapter) remove(ctx context.Context) error {
-	return c.backend.ContainerRm(c.container.name(), &types.ContainerRmConfig{
+	return c.backend.ContainerRm(ctx, c.container.name(), &types.ContainerRmConfig{
 		RemoveVolume: true,
 		ForceRemove:  true,
 	})
 }
 
+// logs publishes the log messages of the container selected by the options.
+func (c *containerAdapter) logs(ctx context.Context, options api.LogSubscriptionOptions, publisher exec.LogPublisher) error {
+	// swarmkit counts a negative tail from the end of the stream, offset by
+	// one, and a positive tail from the start.
+	config := logger.ReadConfig{
+		Tail:   -1,
+		Follow: options.Follow,
+	}
+	skip := options.Tail
+	if options.Tail < 0 {
+		config.Tail = int(-options.Tail - 1)
+		skip = 0
+	}
+	if options.Since != nil {
+		since, err := ptypes.Timestamp(options.Since)
+		if err != nil {
+			return err
+		}
+		config.Since = since
+	}
+
+	streams := map[api.LogStream]bool{}
+	for _, stream := range options.Streams {
+		streams[stream] = true
+	}
+
+	logCtx := api.LogContext{
+		ServiceID: c.container.task.ServiceID,
+		NodeID:    c.container.task.NodeID,
+		TaskID:    c.container.task.ID,
+	}
+
+	return c.backend.ContainerLogMessages(ctx, c.container.name(), config, func(msg *logger.Message) error {
+		var stream api.LogStream
+		switch msg.Source {
+		case "stdout":
+			stream = api.LogStreamStdout
+		case "stderr":
+			stream = api.LogStreamStderr
+		}
+		if len(streams) > 0 && !streams[stream] {
+			return nil
+		}
+
+		if skip > 0 {
+			skip--
+			return nil
+		}
+
+		ts, err := ptypes.TimestampProto(msg.Timestamp)
+		if err != nil {
+			return err
+		}
+
+		return publisher.Publish(ctx, api.LogMessage{
+			Context:   logCtx,
+			Timestamp: ts,
+			Stream:    stream,
+			Data:      msg.Line,
+		})
+	})
+}
+
 func (c *containerAdapter) createVolumes(ctx context.Context, backend executorpkg.Backend) error {
 	// Create plugin volumes that are embedded inside a Mount
 	for _, mount := range c.container.task.Spec.GetContainer().Mounts {
@@ -240,8 +307,19 @@ func (c *containerAdapter) createVolumes(ctx context.Context, backend executorpk
 
 		req := c.container.volumeCreateRequest(&mount)
 
+		// A volume of a driver managing volumes across the cluster is
+		// created once, by the first task using it.
+		if v, err := backend.VolumeInspect(req.Name, false); err == nil && v.Scope == volume.GlobalScope {
+			continue
+		}
+
 		// Check if this volume exists on the engine
-		if _, err := backend.VolumeCreate(req.Name, req.Driver, req.DriverOpts, req.Labels); err != nil {
+		if _, err := backend.VolumeCreate(ctx, req.Name, req.Driver, req.DriverOpts, req.Labels); err != nil {
+			// Another node may have just created the same cluster-wide
+			// volume.
+			if v, inspectErr := backend.VolumeInspect(req.Name, false); inspectErr == nil && v.Scope == volume.GlobalScope {
+				continue
+			}
 			// TODO(amitshukla): Today, volume create through the engine api does not return an error
 			// when the named volume with the same parameters already exists.
 			// It returns an error if the driver name is different - that is a valid error
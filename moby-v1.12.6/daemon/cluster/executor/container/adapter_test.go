@@ -0,0 +1,122 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/swarmkit/agent/exec"
+	"github.com/docker/swarmkit/api"
+	"github.com/docker/swarmkit/protobuf/ptypes"
+	"golang.org/x/net/context"
+)
+
+// logsBackend reads a fixed set of log messages, recording the read config.
+type logsBackend struct {
+	executorpkg.Backend
+	messages []*logger.Message
+	config   logger.ReadConfig
+}
+
+func (b *logsBackend) ContainerLogMessages(ctx context.Context, name string, config logger.ReadConfig, fn func(*logger.Message) error) error {
+	b.config = config
+	for _, msg := range b.messages {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestContainerAdapterLogs(t *testing.T) {
+	since := time.Unix(1476000000, 0).UTC()
+	sinceProto, err := ptypes.TimestampProto(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []*logger.Message{
+		{Line: []byte("out1"), Source: "stdout", Timestamp: since},
+		{Line: []byte("err1"), Source: "stderr", Timestamp: since},
+		{Line: []byte("out2"), Source: "stdout", Timestamp: since},
+		{Line: []byte("err2"), Source: "stderr", Timestamp: since},
+		{Line: []byte("out3"), Source: "stdout", Timestamp: since},
+	}
+
+	for _, c := range []struct {
+		options  api.LogSubscriptionOptions
+		config   logger.ReadConfig
+		expected []string
+	}{
+		{
+			options:  api.LogSubscriptionOptions{},
+			config:   logger.ReadConfig{Tail: -1},
+			expected: []string{"out1", "err1", "out2", "err2", "out3"},
+		},
+		{
+			// the last two messages, read by the backend.
+			options:  api.LogSubscriptionOptions{Tail: -3, Follow: true},
+			config:   logger.ReadConfig{Tail: 2, Follow: true},
+			expected: []string{"out1", "err1", "out2", "err2", "out3"},
+		},
+		{
+			options:  api.LogSubscriptionOptions{Tail: -1},
+			config:   logger.ReadConfig{Tail: 0},
+			expected: []string{"out1", "err1", "out2", "err2", "out3"},
+		},
+		{
+			// skip the first two messages.
+			options:  api.LogSubscriptionOptions{Tail: 2},
+			config:   logger.ReadConfig{Tail: -1},
+			expected: []string{"out2", "err2", "out3"},
+		},
+		{
+			// skip the first message of the selected streams only.
+			options:  api.LogSubscriptionOptions{Tail: 1, Streams: []api.LogStream{api.LogStreamStderr}},
+			config:   logger.ReadConfig{Tail: -1},
+			expected: []string{"err2"},
+		},
+		{
+			options:  api.LogSubscriptionOptions{Since: sinceProto, Streams: []api.LogStream{api.LogStreamStdout}},
+			config:   logger.ReadConfig{Tail: -1, Since: since},
+			expected: []string{"out1", "out2", "out3"},
+		},
+	} {
+		backend := &logsBackend{messages: messages}
+		adapter, err := newContainerAdapter(backend, &api.Task{
+			ID:        "task1",
+			ServiceID: "service1",
+			NodeID:    "node1",
+			Spec: api.TaskSpec{
+				Runtime: &api.TaskSpec_Container{
+					Container: &api.ContainerSpec{Image: "image"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var published []string
+		publisher := exec.LogPublisherFunc(func(ctx context.Context, message api.LogMessage) error {
+			expected := api.LogContext{ServiceID: "service1", NodeID: "node1", TaskID: "task1"}
+			if message.Context != expected {
+				t.Errorf("expected log context %v, got %v", expected, message.Context)
+			}
+			published = append(published, string(message.Data))
+			return nil
+		})
+
+		if err := adapter.logs(context.Background(), c.options, publisher); err != nil {
+			t.Fatal(err)
+		}
+		if !backend.config.Since.Equal(c.config.Since) || backend.config.Tail != c.config.Tail || backend.config.Follow != c.config.Follow {
+			t.Errorf("options %v: expected read config %v, got %v", c.options, c.config, backend.config)
+		}
+		if !reflect.DeepEqual(published, c.expected) {
+			t.Errorf("options %v: expected messages %v, got %v", c.options, c.expected, published)
+		}
+	}
+}
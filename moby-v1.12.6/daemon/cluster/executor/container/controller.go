@@ -32,6 +32,7 @@ type controller struct {
 }
 
 var _ exec.Controller = &controller{}
+var _ exec.ControllerLogs = &controller{}
 
 // NewController returns a dockerexec runner for the provided task.
 func newController(b executorpkg.Backend, task *api.Task) (*controller, error) {
@@ -362,6 +363,23 @@ func (r *controller) Remove(ctx context.Context) error {
 	return nil
 }
 
+// Logs publishes the logs of the container. Tasks whose container has not
+// been created yet have no logs.
+func (r *controller) Logs(ctx context.Context, publisher exec.LogPublisher, options api.LogSubscriptionOptions) error {
+	if err := r.checkClosed(); err != nil {
+		return err
+	}
+
+	if err := r.adapter.logs(ctx, options, publisher); err != nil {
+		if isUnknownContainer(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed reading container logs")
+	}
+
+	return nil
+}
+
 // Close the runner and clean up any ephemeral resources.
 func (r *controller) Close() error {
 	select {
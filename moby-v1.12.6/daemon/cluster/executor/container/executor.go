@@ -4,8 +4,10 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Sirupsen/logrus"
 	executorpkg "github.com/docker/docker/daemon/cluster/executor"
 	clustertypes "github.com/docker/docker/daemon/cluster/provider"
+	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/network"
 	networktypes "github.com/docker/libnetwork/types"
@@ -43,6 +45,20 @@ func (e *executor) Describe(ctx context.Context) (*api.NodeDescription, error) {
 	}
 
 	addPlugins("Volume", info.Plugins.Volume)
+	// Report the volume drivers managing volumes across the cluster, so
+	// that the scheduler does not tie tasks to the node of their volumes.
+	var globalVolumes []string
+	for _, name := range info.Plugins.Volume {
+		scope, err := e.backend.VolumeDriverScope(name)
+		if err != nil {
+			logrus.Warnf("Error getting the scope of volume driver %s: %v", name, err)
+			continue
+		}
+		if scope == volume.GlobalScope {
+			globalVolumes = append(globalVolumes, name)
+		}
+	}
+	addPlugins(api.GlobalVolumePluginType, globalVolumes)
 	// Add builtin driver "overlay" (the only builtin multi-host driver) to
 	// the plugin list by default.
 	addPlugins("Network", append([]string{"overlay"}, info.Plugins.Network...))
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"testing"
+
+	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/swarmkit/api"
+	"golang.org/x/net/context"
+)
+
+type describeBackend struct {
+	executorpkg.Backend
+	volumeScopes map[string]string
+}
+
+func (b *describeBackend) SystemInfo() (*types.Info, error) {
+	info := &types.Info{}
+	for name := range b.volumeScopes {
+		info.Plugins.Volume = append(info.Plugins.Volume, name)
+	}
+	return info, nil
+}
+
+func (b *describeBackend) VolumeDriverScope(name string) (string, error) {
+	return b.volumeScopes[name], nil
+}
+
+func TestDescribeGlobalVolumeDrivers(t *testing.T) {
+	e := NewExecutor(&describeBackend{volumeScopes: map[string]string{
+		"local":  volume.LocalScope,
+		"shared": volume.GlobalScope,
+	}})
+
+	description, err := e.Describe(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := make(map[api.PluginDescription]bool)
+	for _, p := range description.Engine.Plugins {
+		plugins[p] = true
+	}
+	for _, p := range []api.PluginDescription{
+		{Type: "Volume", Name: "local"},
+		{Type: "Volume", Name: "shared"},
+		{Type: api.GlobalVolumePluginType, Name: "shared"},
+	} {
+		if !plugins[p] {
+			t.Fatalf("Expected plugin %v to be described, got %v", p, description.Engine.Plugins)
+		}
+	}
+	if plugins[api.PluginDescription{Type: api.GlobalVolumePluginType, Name: "local"}] {
+		t.Fatalf("Expected the local volume driver not to be described as global")
+	}
+}
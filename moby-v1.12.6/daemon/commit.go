@@ -18,6 +18,7 @@ import (
 	"github.com/docker/docker/reference"
 	containertypes "github.com/docker/engine-api/types/container"
 	"github.com/docker/go-connections/nat"
+	"golang.org/x/net/context"
 )
 
 // merge merges two Config, the image container configuration (defaults values),
@@ -118,7 +119,7 @@ func merge(userConf, imageConf *containertypes.Config) error {
 
 // Commit creates a new filesystem image from the current state of a container.
 // The image can optionally be tagged into a repository.
-func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (string, error) {
+func (daemon *Daemon) Commit(ctx context.Context, name string, c *backend.ContainerCommitConfig) (string, error) {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return "", err
@@ -130,8 +131,8 @@ func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (str
 	}
 
 	if c.Pause && !container.IsPaused() {
-		daemon.containerPause(container)
-		defer daemon.containerUnpause(container)
+		daemon.containerPause(ctx, container)
+		defer daemon.containerUnpause(ctx, container)
 	}
 
 	newConfig, err := dockerfile.BuildFromConfig(c.Config, c.Changes)
@@ -234,14 +235,13 @@ func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (str
 				return "", err
 			}
 		}
-		if err := daemon.TagImageWithReference(id, newTag); err != nil {
+		if err := daemon.TagImageWithReference(ctx, id, newTag); err != nil {
 			return "", err
 		}
 	}
 
-	attributes := map[string]string{
-		"comment": c.Comment,
-	}
+	attributes := requestAttributes(ctx)
+	attributes["comment"] = c.Comment
 	daemon.LogContainerEventWithAttributes(container, "commit", attributes)
 	return id.String(), nil
 }
@@ -26,6 +26,9 @@ const (
 	// maximum number of uploads that
 	// may take place at a time for each push.
 	defaultMaxConcurrentUploads = 5
+	// defaultPushCompression is the default compression used for
+	// layers pushed to a registry.
+	defaultPushCompression = "gzip"
 	// stockRuntimeName is the reserved name/alias used to represent the
 	// OCI runtime being shipped with the docker daemon package.
 	stockRuntimeName = "runc"
@@ -43,6 +46,7 @@ const (
 var flatOptions = map[string]bool{
 	"cluster-store-opts": true,
 	"log-opts":           true,
+	"audit-log-opts":     true,
 	"runtimes":           true,
 }
 
@@ -75,26 +79,27 @@ type CommonTLSOptions struct {
 // It includes json tags to deserialize configuration from a file
 // using the same names that the flags in the command line use.
 type CommonConfig struct {
-	AuthorizationPlugins []string            `json:"authorization-plugins,omitempty"` // AuthorizationPlugins holds list of authorization plugins
-	AutoRestart          bool                `json:"-"`
-	Context              map[string][]string `json:"-"`
-	DisableBridge        bool                `json:"-"`
-	DNS                  []string            `json:"dns,omitempty"`
-	DNSOptions           []string            `json:"dns-opts,omitempty"`
-	DNSSearch            []string            `json:"dns-search,omitempty"`
-	ExecOptions          []string            `json:"exec-opts,omitempty"`
-	GraphDriver          string              `json:"storage-driver,omitempty"`
-	GraphOptions         []string            `json:"storage-opts,omitempty"`
-	Labels               []string            `json:"labels,omitempty"`
-	Mtu                  int                 `json:"mtu,omitempty"`
-	Pidfile              string              `json:"pidfile,omitempty"`
-	RawLogs              bool                `json:"raw-logs,omitempty"`
-	Root                 string              `json:"graph,omitempty"`
-	SocketGroup          string              `json:"group,omitempty"`
-	TrustKeyPath         string              `json:"-"`
-	CorsHeaders          string              `json:"api-cors-header,omitempty"`
-	EnableCors           bool                `json:"api-enable-cors,omitempty"`
-	LiveRestore          bool                `json:"live-restore,omitempty"`
+	AuthenticationPlugins []string            `json:"authentication-plugins,omitempty"` // AuthenticationPlugins holds list of authentication plugins
+	AuthorizationPlugins  []string            `json:"authorization-plugins,omitempty"`  // AuthorizationPlugins holds list of authorization plugins
+	AutoRestart           bool                `json:"-"`
+	Context               map[string][]string `json:"-"`
+	DisableBridge         bool                `json:"-"`
+	DNS                   []string            `json:"dns,omitempty"`
+	DNSOptions            []string            `json:"dns-opts,omitempty"`
+	DNSSearch             []string            `json:"dns-search,omitempty"`
+	ExecOptions           []string            `json:"exec-opts,omitempty"`
+	GraphDriver           string              `json:"storage-driver,omitempty"`
+	GraphOptions          []string            `json:"storage-opts,omitempty"`
+	Labels                []string            `json:"labels,omitempty"`
+	Mtu                   int                 `json:"mtu,omitempty"`
+	Pidfile               string              `json:"pidfile,omitempty"`
+	RawLogs               bool                `json:"raw-logs,omitempty"`
+	Root                  string              `json:"graph,omitempty"`
+	SocketGroup           string              `json:"group,omitempty"`
+	TrustKeyPath          string              `json:"-"`
+	CorsHeaders           string              `json:"api-cors-header,omitempty"`
+	EnableCors            bool                `json:"api-enable-cors,omitempty"`
+	LiveRestore           bool                `json:"live-restore,omitempty"`
 
 	// ClusterStore is the storage backend used for the cluster information. It is used by both
 	// multihost networking (to store networks and endpoints information) and by the node discovery
@@ -110,6 +115,33 @@ type CommonConfig struct {
 	// reachable by other hosts.
 	ClusterAdvertise string `json:"cluster-advertise,omitempty"`
 
+	// AuthenticationTokenFile is the path of a file of static bearer
+	// tokens to authenticate API users with.
+	AuthenticationTokenFile string `json:"authentication-token-file,omitempty"`
+
+	// AuthenticationHtpasswdFile is the path of an htpasswd file to
+	// authenticate API users with, using HTTP basic authentication.
+	AuthenticationHtpasswdFile string `json:"authentication-htpasswd-file,omitempty"`
+
+	// AuthenticationGroupFile is the path of a file listing the groups
+	// of authenticated API users.
+	AuthenticationGroupFile string `json:"authentication-group-file,omitempty"`
+
+	// AuthorizationPolicy is the path of a policy file for the built-in
+	// authorization engine, which is reloaded with the configuration.
+	AuthorizationPolicy string `json:"authorization-policy,omitempty"`
+
+	// AuditLog is the destination of the API audit log, "syslog" or the
+	// path of a file. API requests are not audited when it is empty.
+	AuditLog string `json:"audit-log,omitempty"`
+
+	// AuditLogOpts holds the options of the API audit log destination.
+	AuditLogOpts map[string]string `json:"audit-log-opts,omitempty"`
+
+	// AuditLevels holds the audit level of all endpoints ("level"), or of
+	// a group of endpoints ("group=level").
+	AuditLevels []string `json:"audit-levels,omitempty"`
+
 	// MaxConcurrentDownloads is the maximum number of downloads that
 	// may take place at a time for each pull.
 	MaxConcurrentDownloads *int `json:"max-concurrent-downloads,omitempty"`
@@ -118,6 +150,10 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// PushCompression is the compression ("gzip" or "zstd") used for
+	// layers pushed to a registry.
+	PushCompression string `json:"push-compression,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -152,7 +188,15 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	config.ServiceOptions.InstallCliFlags(cmd, usageFn)
 
 	cmd.Var(opts.NewNamedListOptsRef("storage-opts", &config.GraphOptions, nil), []string{"-storage-opt"}, usageFn("Storage driver options"))
+	cmd.Var(opts.NewNamedListOptsRef("authentication-plugins", &config.AuthenticationPlugins, nil), []string{"-authentication-plugin"}, usageFn("Authentication plugins to load"))
+	cmd.StringVar(&config.AuthenticationTokenFile, []string{"-authentication-token-file"}, "", usageFn("File of bearer tokens to authenticate API users with"))
+	cmd.StringVar(&config.AuthenticationHtpasswdFile, []string{"-authentication-htpasswd-file"}, "", usageFn("Htpasswd file to authenticate API users with"))
+	cmd.StringVar(&config.AuthenticationGroupFile, []string{"-authentication-group-file"}, "", usageFn("File of groups of API users"))
 	cmd.Var(opts.NewNamedListOptsRef("authorization-plugins", &config.AuthorizationPlugins, nil), []string{"-authorization-plugin"}, usageFn("Authorization plugins to load"))
+	cmd.StringVar(&config.AuthorizationPolicy, []string{"-authorization-policy"}, "", usageFn("Authorization policy file to load"))
+	cmd.StringVar(&config.AuditLog, []string{"-audit-log"}, "", usageFn("Write an audit log of API requests to syslog or a file"))
+	cmd.Var(opts.NewNamedMapOpts("audit-log-opts", config.AuditLogOpts, nil), []string{"-audit-log-opt"}, usageFn("Audit log options"))
+	cmd.Var(opts.NewNamedListOptsRef("audit-levels", &config.AuditLevels, nil), []string{"-audit-level"}, usageFn("Audit level of API endpoints"))
 	cmd.Var(opts.NewNamedListOptsRef("exec-opts", &config.ExecOptions, nil), []string{"-exec-opt"}, usageFn("Runtime execution options"))
 	cmd.StringVar(&config.Pidfile, []string{"p", "-pidfile"}, defaultPidFile, usageFn("Path to use for daemon PID file"))
 	cmd.StringVar(&config.Root, []string{"g", "-graph"}, defaultGraph, usageFn("Root of the Docker runtime"))
@@ -173,6 +217,7 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.CorsHeaders, []string{"-api-cors-header"}, "", usageFn("Set CORS headers in the remote API"))
 	cmd.IntVar(&maxConcurrentDownloads, []string{"-max-concurrent-downloads"}, defaultMaxConcurrentDownloads, usageFn("Set the max concurrent downloads for each pull"))
 	cmd.IntVar(&maxConcurrentUploads, []string{"-max-concurrent-uploads"}, defaultMaxConcurrentUploads, usageFn("Set the max concurrent uploads for each push"))
+	cmd.StringVar(&config.PushCompression, []string{"-push-compression"}, defaultPushCompression, usageFn("Compression used for pushed layers (gzip or zstd)"))
 
 	cmd.StringVar(&config.SwarmDefaultAdvertiseAddr, []string{"-swarm-default-advertise-addr"}, "", usageFn("Set default address or interface for swarm advertised address"))
 
@@ -402,7 +447,8 @@ func findConfigurationConflicts(config map[string]interface{}, flags *flag.FlagS
 
 // ValidateConfiguration validates some specific configs.
 // such as config.DNS, config.Labels, config.DNSSearch,
-// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads.
+// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads
+// and config.PushCompression.
 func ValidateConfiguration(config *Config) error {
 	// validate DNS
 	for _, dns := range config.DNS {
@@ -435,6 +481,11 @@ func ValidateConfiguration(config *Config) error {
 		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
 	}
 
+	// validate PushCompression
+	if _, err := pushCompression(config.PushCompression); err != nil {
+		return err
+	}
+
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
 		if _, ok := runtimes[stockRuntimeName]; ok {
@@ -275,4 +275,26 @@ func TestValidateConfiguration(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
+
+	c7 := &Config{
+		CommonConfig: CommonConfig{
+			PushCompression: "zstd",
+		},
+	}
+
+	err = ValidateConfiguration(c7)
+	if err != nil {
+		t.Fatalf("expected no error, got error %v", err)
+	}
+
+	c8 := &Config{
+		CommonConfig: CommonConfig{
+			PushCompression: "xz",
+		},
+	}
+
+	err = ValidateConfiguration(c8)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
 }
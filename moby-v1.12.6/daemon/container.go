@@ -94,12 +94,27 @@ func (daemon *Daemon) Register(c *container.Container) error {
 		c.StreamConfig.NewNopInputPipe()
 	}
 
+	c.Lock()
+	err := daemon.containersReplica.Save(c)
+	c.Unlock()
+	if err != nil {
+		return err
+	}
+
 	daemon.containers.Add(c.ID, c)
 	daemon.idIndex.Add(c.ID)
 
 	return nil
 }
 
+// checkpointAndSave saves the container to disk and refreshes its copy in
+// the containers replica, taking the container lock.
+func (daemon *Daemon) checkpointAndSave(c *container.Container) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.CheckpointTo(daemon.containersReplica)
+}
+
 func (daemon *Daemon) newContainer(name string, config *containertypes.Config, imgID image.ID, managed bool) (*container.Container, error) {
 	var (
 		id             string
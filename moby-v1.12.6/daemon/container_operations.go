@@ -21,6 +21,7 @@ import (
 	"github.com/docker/libnetwork/netlabel"
 	"github.com/docker/libnetwork/options"
 	"github.com/docker/libnetwork/types"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -331,6 +332,10 @@ func errClusterNetworkOnRun(n string) error {
 	return fmt.Errorf("swarm-scoped network (%s) is not compatible with `docker create` or `docker run`. This network can only be used by a docker service", n)
 }
 
+func errNetworkNotAttachable(n string) error {
+	return fmt.Errorf("network %s is not attachable: containers can no longer be connected to it", n)
+}
+
 // updateContainerNetworkSettings update the network settings
 func (daemon *Daemon) updateContainerNetworkSettings(container *container.Container, endpointsConfig map[string]*networktypes.EndpointSettings) error {
 	var (
@@ -355,6 +360,9 @@ func (daemon *Daemon) updateContainerNetworkSettings(container *container.Contai
 		if !container.Managed && n.Info().Dynamic() {
 			return errClusterNetworkOnRun(networkName)
 		}
+		if !container.Managed && !n.Info().Attachable() {
+			return errNetworkNotAttachable(n.Name())
+		}
 		networkName = n.Name()
 	}
 	if container.NetworkSettings == nil {
@@ -383,7 +391,7 @@ func (daemon *Daemon) updateContainerNetworkSettings(container *container.Contai
 	return nil
 }
 
-func (daemon *Daemon) allocateNetwork(container *container.Container) error {
+func (daemon *Daemon) allocateNetwork(ctx context.Context, container *container.Container) error {
 	controller := daemon.netController
 
 	if daemon.netController == nil {
@@ -414,7 +422,7 @@ func (daemon *Daemon) allocateNetwork(container *container.Container) error {
 	// on first network connecting.
 	defaultNetName := runconfig.DefaultDaemonNetworkMode().NetworkName()
 	if nConf, ok := container.NetworkSettings.Networks[defaultNetName]; ok {
-		if err := daemon.connectToNetwork(container, defaultNetName, nConf, updateSettings); err != nil {
+		if err := daemon.connectToNetwork(ctx, container, defaultNetName, nConf, updateSettings); err != nil {
 			return err
 		}
 
@@ -423,7 +431,7 @@ func (daemon *Daemon) allocateNetwork(container *container.Container) error {
 		if n == defaultNetName {
 			continue
 		}
-		if err := daemon.connectToNetwork(container, n, nConf, updateSettings); err != nil {
+		if err := daemon.connectToNetwork(ctx, container, n, nConf, updateSettings); err != nil {
 			return err
 		}
 	}
@@ -548,7 +556,7 @@ func (daemon *Daemon) updateNetworkConfig(container *container.Container, idOrNa
 	return n, nil
 }
 
-func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings, updateSettings bool) (err error) {
+func (daemon *Daemon) connectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings, updateSettings bool) (err error) {
 	if endpointConfig == nil {
 		endpointConfig = &networktypes.EndpointSettings{}
 	}
@@ -571,6 +579,11 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 	endpointName := strings.TrimPrefix(container.Name, "/")
 	ep, err := n.CreateEndpoint(endpointName, createOptions...)
 	if err != nil {
+		// The addresses of swarm tasks are allocated by the managers,
+		// so the exhaustion of a pool is only seen here.
+		if _, ok := err.(*libnetwork.NoAvailableAddressesError); ok {
+			daemon.LogNetworkEventWithAttributes(n, "exhausted", map[string]string{"container": container.ID})
+		}
 		return err
 	}
 	defer func() {
@@ -614,7 +627,9 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 
 	container.NetworkSettings.Ports = getPortMapInfo(sb)
 
-	daemon.LogNetworkEventWithAttributes(n, "connect", map[string]string{"container": container.ID})
+	attributes := requestAttributes(ctx)
+	attributes["container"] = container.ID
+	daemon.LogNetworkEventWithAttributes(n, "connect", attributes)
 	return nil
 }
 
@@ -676,7 +691,7 @@ func disconnectFromNetwork(container *container.Container, n libnetwork.Network,
 	return nil
 }
 
-func (daemon *Daemon) initializeNetworking(container *container.Container) error {
+func (daemon *Daemon) initializeNetworking(ctx context.Context, container *container.Container) error {
 	var err error
 
 	if container.HostConfig.NetworkMode.IsContainer() {
@@ -700,7 +715,7 @@ func (daemon *Daemon) initializeNetworking(container *container.Container) error
 		}
 	}
 
-	if err := daemon.allocateNetwork(container); err != nil {
+	if err := daemon.allocateNetwork(ctx, container); err != nil {
 		return err
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]string, error) {
@@ -15,7 +16,7 @@ func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]s
 }
 
 // ConnectToNetwork connects a container to a network
-func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
+func (daemon *Daemon) ConnectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
 	return fmt.Errorf("Solaris does not support connecting a running container to a network")
 }
 
@@ -25,7 +26,7 @@ func (daemon *Daemon) getSize(container *container.Container) (int64, int64) {
 }
 
 // DisconnectFromNetwork disconnects a container from the network
-func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectFromNetwork(ctx context.Context, container *container.Container, n libnetwork.Network, force bool) error {
 	return fmt.Errorf("Solaris does not support disconnecting a running container from a network")
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/opencontainers/runc/libcontainer/devices"
 	"github.com/opencontainers/runc/libcontainer/label"
 	"github.com/opencontainers/specs/specs-go"
+	"golang.org/x/net/context"
 )
 
 func u32Ptr(i int64) *uint32     { u := uint32(i); return &u }
@@ -100,7 +101,7 @@ func (daemon *Daemon) getSize(container *container.Container) (int64, int64) {
 }
 
 // ConnectToNetwork connects a container to a network
-func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
+func (daemon *Daemon) ConnectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
 	if endpointConfig == nil {
 		endpointConfig = &networktypes.EndpointSettings{}
 	}
@@ -113,18 +114,26 @@ func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName
 		}
 		container.NetworkSettings.Networks[idOrName] = endpointConfig
 	} else {
-		if err := daemon.connectToNetwork(container, idOrName, endpointConfig, true); err != nil {
+		if err := daemon.connectToNetwork(ctx, container, idOrName, endpointConfig, true); err != nil {
 			return err
 		}
+		if err := daemon.applyNetworkLimits(container); err != nil {
+			if n, nErr := daemon.FindNetwork(idOrName); nErr == nil {
+				if dErr := disconnectFromNetwork(container, n, false); dErr != nil {
+					logrus.Warnf("Failed to disconnect container %s from network %s: %v", container.ID, idOrName, dErr)
+				}
+			}
+			return fmt.Errorf("Failed to apply the network rate limits of container %s: %v", container.ID, err)
+		}
 	}
-	if err := container.ToDiskLocking(); err != nil {
+	if err := daemon.checkpointAndSave(container); err != nil {
 		return fmt.Errorf("Error saving container to disk: %v", err)
 	}
 	return nil
 }
 
 // DisconnectFromNetwork disconnects container from network n.
-func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectFromNetwork(ctx context.Context, container *container.Container, n libnetwork.Network, force bool) error {
 	if container.HostConfig.NetworkMode.IsHost() && containertypes.NetworkMode(n.Type()).IsHost() {
 		return runconfig.ErrConflictHostNetwork
 	}
@@ -143,13 +152,12 @@ func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n li
 		}
 	}
 
-	if err := container.ToDiskLocking(); err != nil {
+	if err := daemon.checkpointAndSave(container); err != nil {
 		return fmt.Errorf("Error saving container to disk: %v", err)
 	}
 
-	attributes := map[string]string{
-		"container": container.ID,
-	}
+	attributes := requestAttributes(ctx)
+	attributes["container"] = container.ID
 	daemon.LogNetworkEventWithAttributes(n, "disconnect", attributes)
 	return nil
 }
@@ -204,7 +212,7 @@ func (daemon *Daemon) setupIpcDirs(c *container.Container) error {
 		}
 		c.ShmPath = "/dev/shm"
 	} else {
-		rootUID, rootGID := daemon.GetRemappedUIDGID()
+		rootUID, rootGID := daemon.containerRemappedUIDGID(c)
 		if !c.HasMountFor("/dev/shm") {
 			shmPath, err := c.ShmResourcePath()
 			if err != nil {
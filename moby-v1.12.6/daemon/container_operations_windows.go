@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]string, error) {
@@ -15,12 +16,12 @@ func (daemon *Daemon) setupLinkedContainers(container *container.Container) ([]s
 }
 
 // ConnectToNetwork connects a container to a network
-func (daemon *Daemon) ConnectToNetwork(container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
+func (daemon *Daemon) ConnectToNetwork(ctx context.Context, container *container.Container, idOrName string, endpointConfig *networktypes.EndpointSettings) error {
 	return fmt.Errorf("Windows does not support connecting a running container to a network")
 }
 
 // DisconnectFromNetwork disconnects container from a network.
-func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectFromNetwork(ctx context.Context, container *container.Container, n libnetwork.Network, force bool) error {
 	return fmt.Errorf("Windows does not support disconnecting a running container from a network")
 }
 
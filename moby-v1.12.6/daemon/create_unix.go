@@ -21,7 +21,7 @@ func (daemon *Daemon) createContainerPlatformSpecificSettings(container *contain
 	}
 	defer daemon.Unmount(container)
 
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	rootUID, rootGID := daemon.containerRemappedUIDGID(container)
 	if err := container.SetupWorkingDirectory(rootUID, rootGID); err != nil {
 		return err
 	}
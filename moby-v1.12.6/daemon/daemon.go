@@ -57,6 +57,7 @@ import (
 	"github.com/docker/libnetwork"
 	nwconfig "github.com/docker/libnetwork/config"
 	"github.com/docker/libtrust"
+	"golang.org/x/net/context"
 )
 
 var (
@@ -67,11 +68,16 @@ var (
 	errSystemNotSupported = fmt.Errorf("The Docker daemon is not supported on this platform.")
 )
 
+// partialDownloadMaxAge is how long the data of an interrupted layer
+// download is kept for a later pull to resume from.
+const partialDownloadMaxAge = 7 * 24 * time.Hour
+
 // Daemon holds information about the Docker daemon.
 type Daemon struct {
 	ID                        string
 	repository                string
 	containers                container.Store
+	containersReplica         container.ViewDB
 	execCommands              *exec.Store
 	referenceStore            reference.Store
 	downloadManager           *xfer.LayerDownloadManager
@@ -92,6 +98,7 @@ type Daemon struct {
 	shutdown                  bool
 	uidMaps                   []idtools.IDMap
 	gidMaps                   []idtools.IDMap
+	idRanges                  *idRangePool
 	layerStore                layer.Store
 	imageStore                image.Store
 	nameIndex                 *registrar.Registrar
@@ -100,6 +107,12 @@ type Daemon struct {
 	containerdRemote          libcontainerd.Remote
 	defaultIsolation          containertypes.Isolation // Default isolation mode on Windows
 	clusterProvider           cluster.Provider
+
+	// stateEvents holds the attributes of the events of the state changes
+	// containerd reports while API requests start, pause or unpause
+	// containers, by container ID.
+	stateEventsLock sync.Mutex
+	stateEvents     map[string]map[string]string
 }
 
 func (daemon *Daemon) restore() error {
@@ -117,32 +130,46 @@ func (daemon *Daemon) restore() error {
 		return err
 	}
 
+	// limit the number of containers restored at once, so dense nodes do
+	// not run out of file descriptors or threads
+	sem := make(chan struct{}, restoreParallelism())
+
+	var (
+		group   sync.WaitGroup
+		mapLock sync.Mutex
+	)
 	for _, v := range dir {
-		id := v.Name()
-		container, err := daemon.load(id)
-		if !debug && logrus.GetLevel() == logrus.InfoLevel {
-			fmt.Print(".")
-		}
-		if err != nil {
-			logrus.Errorf("Failed to load container %v: %v", id, err)
-			continue
-		}
+		group.Add(1)
+		go func(id string) {
+			defer group.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		// Ignore the container if it does not support the current driver being used by the graph
-		if (container.Driver == "" && currentDriver == "aufs") || container.Driver == currentDriver {
-			rwlayer, err := daemon.layerStore.GetRWLayer(container.ID)
+			container, err := daemon.load(id)
 			if err != nil {
-				logrus.Errorf("Failed to load container mount %v: %v", id, err)
-				continue
+				logrus.Errorf("Failed to load container %v: %v", id, err)
+				return
 			}
-			container.RWLayer = rwlayer
-			logrus.Debugf("Loaded container %v", container.ID)
 
-			containers[container.ID] = container
-		} else {
-			logrus.Debugf("Cannot load container %s because it was created with another graph driver.", container.ID)
-		}
+			// Ignore the container if it does not support the current driver being used by the graph
+			if (container.Driver == "" && currentDriver == "aufs") || container.Driver == currentDriver {
+				rwlayer, err := daemon.layerStore.GetRWLayer(container.ID)
+				if err != nil {
+					logrus.Errorf("Failed to load container mount %v: %v", id, err)
+					return
+				}
+				container.RWLayer = rwlayer
+				logrus.Debugf("Loaded container %v", container.ID)
+
+				mapLock.Lock()
+				containers[container.ID] = container
+				mapLock.Unlock()
+			} else {
+				logrus.Debugf("Cannot load container %s because it was created with another graph driver.", container.ID)
+			}
+		}(v.Name())
 	}
+	group.Wait()
 
 	var migrateLegacyLinks bool
 	restartContainers := make(map[*container.Container]chan struct{})
@@ -156,6 +183,13 @@ func (daemon *Daemon) restore() error {
 			logrus.Errorf("Failed to register container %s: %s", c.ID, err)
 			continue
 		}
+		if len(c.UIDMaps) > 0 {
+			if daemon.idRanges == nil {
+				logrus.Warnf("Container %s uses a range of the user namespace pool, but no pool is configured", c.ID)
+			} else if err := daemon.idRanges.reserve(c.ID, c.HostConfig.UsernsMode.RangeName(), c.UIDMaps); err != nil {
+				logrus.Errorf("Failed to reserve the user namespace range of container %s: %s", c.ID, err)
+			}
+		}
 
 		// The LogConfig.Type is empty if the container was created before docker 1.12 with default log driver.
 		// We should rewrite it to use the daemon defaults.
@@ -165,14 +199,20 @@ func (daemon *Daemon) restore() error {
 				logrus.Errorf("Failed to verify log config for container %s: %q", c.ID, err)
 				continue
 			}
+			if err := daemon.containersReplica.Save(c); err != nil {
+				logrus.Errorf("Failed to save container %s: %v", c.ID, err)
+			}
 		}
 	}
-	var wg sync.WaitGroup
-	var mapLock sync.Mutex
+	daemon.restoreTraversablePaths(containers)
+
 	for _, c := range containers {
-		wg.Add(1)
+		group.Add(1)
 		go func(c *container.Container) {
-			defer wg.Done()
+			defer group.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
 			rm := c.RestartManager(false)
 			if c.IsRunning() || c.IsPaused() {
 				if err := daemon.containerd.Restore(c.ID, c.InitializeStdio, libcontainerd.WithRestartManager(rm)); err != nil {
@@ -210,16 +250,18 @@ func (daemon *Daemon) restore() error {
 				logrus.Debugf("Resetting RemovalInProgress flag from %v", c.ID)
 				c.ResetRemovalInProgress()
 				c.SetDead()
-				c.ToDisk()
+				c.CheckpointTo(daemon.containersReplica)
 			}
 
 			// if c.hostConfig.Links is nil (not just empty), then it is using the old sqlite links and needs to be migrated
 			if c.HostConfig != nil && c.HostConfig.Links == nil {
+				mapLock.Lock()
 				migrateLegacyLinks = true
+				mapLock.Unlock()
 			}
 		}(c)
 	}
-	wg.Wait()
+	group.Wait()
 	daemon.netController, err = daemon.initNetworkController(daemon.configStore, activeSandboxes)
 	if err != nil {
 		return fmt.Errorf("Error initializing network controller: %v", err)
@@ -248,7 +290,6 @@ func (daemon *Daemon) restore() error {
 		}
 	}
 
-	group := sync.WaitGroup{}
 	for c, notifier := range restartContainers {
 		group.Add(1)
 
@@ -270,9 +311,14 @@ func (daemon *Daemon) restore() error {
 				}
 			}
 
+			// take the slot only once the children are started, so that
+			// waiting parents cannot hold all of them
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
 			// Make sure networks are available before starting
 			daemon.waitForNetworks(c)
-			if err := daemon.containerStart(c); err != nil {
+			if err := daemon.containerStart(context.Background(), c); err != nil {
 				logrus.Errorf("Failed to start container %s: %s", c.ID, err)
 			}
 			close(chNotify)
@@ -297,6 +343,9 @@ func (daemon *Daemon) restore() error {
 		group.Add(1)
 		go func(c *container.Container) {
 			defer group.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
 			if err := daemon.prepareMountPoints(c); err != nil {
 				logrus.Error(err)
 			}
@@ -306,15 +355,22 @@ func (daemon *Daemon) restore() error {
 	group.Wait()
 
 	if !debug {
-		if logrus.GetLevel() == logrus.InfoLevel {
-			fmt.Println()
-		}
 		logrus.Info("Loading containers: done.")
 	}
 
 	return nil
 }
 
+// restoreParallelism returns the number of containers restored concurrently
+// at daemon startup.
+func restoreParallelism() int {
+	n := 128 * runtime.NumCPU()
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
 // waitForNetworks is used during daemon initialization when starting up containers
 // It ensures that all of a container's networks are available before the daemon tries to start the container.
 // In practice it just makes sure the discovery service is available for containers which use a network that require discovery.
@@ -420,6 +476,10 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	if err != nil {
 		return nil, err
 	}
+	idRanges, err := setupIDRangePool(config)
+	if err != nil {
+		return nil, err
+	}
 
 	// get the canonical path to the Docker root directory
 	var realRoot string
@@ -504,7 +564,14 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	}
 
 	logrus.Debugf("Max Concurrent Downloads: %d", *config.MaxConcurrentDownloads)
-	d.downloadManager = xfer.NewLayerDownloadManager(d.layerStore, *config.MaxConcurrentDownloads)
+	partialDownloads, err := xfer.NewPartialDownloadStore(filepath.Join(realTmp, "partial-downloads"))
+	if err != nil {
+		return nil, err
+	}
+	if err := partialDownloads.Prune(partialDownloadMaxAge); err != nil {
+		logrus.Warnf("Failed to prune partial downloads: %v", err)
+	}
+	d.downloadManager = xfer.NewLayerDownloadManager(d.layerStore, *config.MaxConcurrentDownloads, xfer.WithPartialDownloadStore(partialDownloads))
 	logrus.Debugf("Max Concurrent Uploads: %d", *config.MaxConcurrentUploads)
 	d.uploadManager = xfer.NewLayerUploadManager(*config.MaxConcurrentUploads)
 
@@ -573,6 +640,9 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	d.ID = trustKey.PublicKey().KeyID()
 	d.repository = daemonRepo
 	d.containers = container.NewMemoryStore()
+	if d.containersReplica, err = container.NewViewDB(); err != nil {
+		return nil, err
+	}
 	d.execCommands = exec.NewStore()
 	d.referenceStore = referenceStore
 	d.distributionMetadataStore = distributionMetadataStore
@@ -589,6 +659,7 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	d.root = config.Root
 	d.uidMaps = uidMaps
 	d.gidMaps = gidMaps
+	d.idRanges = idRanges
 	d.seccompEnabled = sysInfo.Seccomp
 
 	d.nameIndex = registrar.NewRegistrar()
@@ -628,7 +699,7 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 		if err := daemon.kill(c, int(sig)); err != nil {
 			return fmt.Errorf("sending SIGTERM to container %s with error: %v", c.ID, err)
 		}
-		if err := daemon.containerUnpause(c); err != nil {
+		if err := daemon.containerUnpause(context.Background(), c); err != nil {
 			return fmt.Errorf("Failed to unpause container %s with error: %v", c.ID, err)
 		}
 		if _, err := c.WaitStop(10 * time.Second); err != nil {
@@ -645,7 +716,7 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 		}
 	}
 	// If container failed to exit in 10 seconds of SIGTERM, then using the force
-	if err := daemon.containerStop(c, 10); err != nil {
+	if err := daemon.containerStop(context.Background(), c, 10); err != nil {
 		return fmt.Errorf("Failed to stop container %s with error: %v", c.ID, err)
 	}
 
@@ -831,9 +902,47 @@ func tempDir(rootDir string, rootUID, rootGID int) (string, error) {
 	return tmpDir, idtools.MkdirAllAs(tmpDir, 0700, rootUID, rootGID)
 }
 
-func (daemon *Daemon) setupInitLayer(initPath string) error {
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
-	return setupInitLayer(initPath, rootUID, rootGID)
+// containerIDMaps returns the user namespace mappings of a container, which
+// are its own if it uses a range of the user namespace pool.
+func (daemon *Daemon) containerIDMaps(c *container.Container) ([]idtools.IDMap, []idtools.IDMap) {
+	if len(c.UIDMaps) > 0 {
+		return c.UIDMaps, c.GIDMaps
+	}
+	return daemon.uidMaps, daemon.gidMaps
+}
+
+// containerRemappedUIDGID returns the host uid and gid of the root user of
+// a container.
+func (daemon *Daemon) containerRemappedUIDGID(c *container.Container) (int, int) {
+	uid, gid, _ := idtools.GetRootUIDGID(daemon.containerIDMaps(c))
+	return uid, gid
+}
+
+// allocateIDMaps allocates a range of the user namespace pool to a
+// container with the "auto" user namespace mode.
+func (daemon *Daemon) allocateIDMaps(c *container.Container, mode containertypes.UsernsMode) error {
+	if !mode.IsAuto() {
+		return nil
+	}
+	if daemon.idRanges == nil {
+		return fmt.Errorf("--userns=auto requires the daemon to be started with --userns-pool")
+	}
+	uidMaps, gidMaps, err := daemon.idRanges.allocate(c.ID, mode.RangeName())
+	if err != nil {
+		return err
+	}
+	c.UIDMaps = uidMaps
+	c.GIDMaps = gidMaps
+	return nil
+}
+
+// containerInitLayer returns the function populating the init layer of a
+// container, owned by the root user of the container.
+func (daemon *Daemon) containerInitLayer(c *container.Container) layer.MountInit {
+	rootUID, rootGID := daemon.containerRemappedUIDGID(c)
+	return func(initPath string) error {
+		return setupInitLayer(initPath, rootUID, rootGID)
+	}
 }
 
 func setDefaultMtu(config *Config) {
@@ -39,6 +39,10 @@ func setupRemappedRoot(config *Config) ([]idtools.IDMap, []idtools.IDMap, error)
 	return nil, nil, nil
 }
 
+func setupIDRangePool(config *Config) (*idRangePool, error) {
+	return nil, nil
+}
+
 func setupDaemonRoot(config *Config, rootDir string, rootUID, rootGID int) error {
 	return nil
 }
@@ -57,6 +57,10 @@ const (
 	// constant for cgroup drivers
 	cgroupFsDriver      = "cgroupfs"
 	cgroupSystemdDriver = "systemd"
+
+	// seccompRecordProfile is the seccomp profile of the containers whose
+	// syscalls are recorded.
+	seccompRecordProfile = "record"
 )
 
 func getMemoryResources(config containertypes.Resources) *specs.Memory {
@@ -167,6 +171,9 @@ func parseSecurityOpt(container *container.Container, config *containertypes.Hos
 			case "apparmor":
 				container.AppArmorProfile = con[1]
 			case "seccomp":
+				if strings.HasPrefix(con[1], seccompRecordProfile+":") {
+					return fmt.Errorf("Invalid --security-opt: %q, the seccomp profile is recorded in the container directory, use seccomp=record and docker seccomp-profile", opt)
+				}
 				container.SeccompProfile = con[1]
 			default:
 				return fmt.Errorf("Invalid --security-opt 2: %q", opt)
@@ -178,6 +185,17 @@ func parseSecurityOpt(container *container.Container, config *containertypes.Hos
 	return err
 }
 
+// recordsSeccompProfile returns true if the security options record the
+// seccomp profile of the container.
+func recordsSeccompProfile(securityOpt []string) bool {
+	for _, opt := range securityOpt {
+		if opt == "seccomp="+seccompRecordProfile || opt == "seccomp:"+seccompRecordProfile {
+			return true
+		}
+	}
+	return false
+}
+
 func getBlkioThrottleDevices(devs []*blkiodev.ThrottleDevice) ([]specs.ThrottleDevice, error) {
 	var throttleDevices []specs.ThrottleDevice
 	var stat syscall.Stat_t
@@ -482,6 +500,19 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 	}
 	warnings = append(warnings, w...)
 
+	if err := verifyNetworkLimits(hostConfig, update); err != nil {
+		return warnings, err
+	}
+
+	if len(hostConfig.ReadonlyWritablePaths) > 0 && !hostConfig.ReadonlyRootfs {
+		return warnings, fmt.Errorf("Writable paths can only be set on a container with a read-only root filesystem")
+	}
+	for _, path := range hostConfig.ReadonlyWritablePaths {
+		if !filepath.IsAbs(path) || filepath.Clean(path) == "/" {
+			return warnings, fmt.Errorf("Invalid writable path %q: must be an absolute path other than /", path)
+		}
+	}
+
 	if hostConfig.ShmSize < 0 {
 		return warnings, fmt.Errorf("SHM size must be greater than 0")
 	}
@@ -495,8 +526,11 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		warnings = append(warnings, "IPv4 forwarding is disabled. Networking will not work.")
 		logrus.Warn("IPv4 forwarding is disabled. Networking will not work")
 	}
+	if hostConfig.UsernsMode.IsAuto() && daemon.idRanges == nil {
+		return warnings, fmt.Errorf("--userns=auto requires the daemon to be started with --userns-pool")
+	}
 	// check for various conflicting options with user namespaces
-	if daemon.configStore.RemappedRoot != "" && hostConfig.UsernsMode.IsPrivate() {
+	if (daemon.configStore.RemappedRoot != "" && hostConfig.UsernsMode.IsPrivate()) || hostConfig.UsernsMode.IsAuto() {
 		if hostConfig.Privileged {
 			return warnings, fmt.Errorf("Privileged mode is incompatible with user namespaces")
 		}
@@ -520,9 +554,15 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		hostConfig.Runtime = daemon.configStore.GetDefaultRuntimeName()
 	}
 
-	if rt := daemon.configStore.GetRuntime(hostConfig.Runtime); rt == nil {
+	rt := daemon.configStore.GetRuntime(hostConfig.Runtime)
+	if rt == nil {
 		return warnings, fmt.Errorf("Unknown runtime specified %s", hostConfig.Runtime)
 	}
+	if recordsSeccompProfile(hostConfig.SecurityOpt) && !hostConfig.Privileged {
+		if err := checkSeccompRecordSupport(rt.Path); err != nil {
+			return warnings, err
+		}
+	}
 
 	return warnings, nil
 }
@@ -986,6 +1026,44 @@ func setupRemappedRoot(config *Config) ([]idtools.IDMap, []idtools.IDMap, error)
 	return uidMaps, gidMaps, nil
 }
 
+// setupIDRangePool creates the pool of user namespace ranges allocated to
+// containers with --userns=auto, from the subordinate IDs of the user and
+// group set with --userns-pool.
+func setupIDRangePool(config *Config) (*idRangePool, error) {
+	if config.UsernsPool == "" {
+		return nil, nil
+	}
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("User namespaces are only supported on Linux")
+	}
+	if config.RemappedRoot != "" {
+		return nil, fmt.Errorf("--userns-pool cannot be used with --userns-remap")
+	}
+	username, groupname, err := parseRemappedRoot(config.UsernsPool)
+	if err != nil {
+		return nil, err
+	}
+	if username == "root" {
+		return nil, fmt.Errorf("the subordinate IDs of root cannot be used as a user namespace pool")
+	}
+	config.UsernsPool = fmt.Sprintf("%s:%s", username, groupname)
+
+	uidMaps, gidMaps, err := idtools.CreateIDMappings(username, groupname)
+	if err != nil {
+		return nil, fmt.Errorf("Can't create ID mappings: %v", err)
+	}
+	size := config.UsernsPoolSize
+	if size == 0 {
+		size = defaultUsernsPoolSize
+	}
+	pool, err := newIDRangePool(uidMaps, gidMaps, size)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("User namespaces: %d ranges of %d IDs will be allocated from the subuid/subgid ranges of: %s:%s", pool.capacity(), size, username, groupname)
+	return pool, nil
+}
+
 func setupDaemonRoot(config *Config, rootDir string, rootUID, rootGID int) error {
 	config.Root = rootDir
 	// the docker root metadata directory needs to have execute permissions for all users (g+x,o+x)
@@ -12,13 +12,14 @@ import (
 	"github.com/docker/docker/layer"
 	volumestore "github.com/docker/docker/volume/store"
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // ContainerRm removes the container id from the filesystem. An error
 // is returned if the container is not found, or if the remove
 // fails. If the remove succeeds, the container name is released, and
 // network links are removed.
-func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig) error {
+func (daemon *Daemon) ContainerRm(ctx context.Context, name string, config *types.ContainerRmConfig) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -28,7 +29,14 @@ func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig)
 	if inProgress := container.SetRemovalInProgress(); inProgress {
 		return nil
 	}
-	defer container.ResetRemovalInProgress()
+	daemon.saveRemovalState(container)
+	defer func() {
+		container.ResetRemovalInProgress()
+		// A removed container is gone from the replica already.
+		if daemon.containers.Get(container.ID) != nil {
+			daemon.saveRemovalState(container)
+		}
+	}()
 
 	// check if container wasn't deregistered by previous rm since Get
 	if c := daemon.containers.Get(container.ID); c == nil {
@@ -39,7 +47,7 @@ func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig)
 		return daemon.rmLink(container, name)
 	}
 
-	err = daemon.cleanupContainer(container, config.ForceRemove)
+	err = daemon.cleanupContainer(ctx, container, config.ForceRemove)
 	if err == nil || config.ForceRemove {
 		if e := daemon.removeMountPoints(container, config.RemoveVolume); e != nil {
 			logrus.Error(e)
@@ -49,6 +57,16 @@ func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig)
 	return err
 }
 
+// saveRemovalState refreshes the copy of the container in the containers
+// replica when its removal starts or ends without removing it.
+func (daemon *Daemon) saveRemovalState(c *container.Container) {
+	c.Lock()
+	defer c.Unlock()
+	if err := daemon.containersReplica.Save(c); err != nil {
+		logrus.Errorf("Error saving the removal state of container %s to the replica: %v", c.ID, err)
+	}
+}
+
 func (daemon *Daemon) rmLink(container *container.Container, name string) error {
 	if name[0] != '/' {
 		name = "/" + name
@@ -77,13 +95,13 @@ func (daemon *Daemon) rmLink(container *container.Container, name string) error
 
 // cleanupContainer unregisters a container from the daemon, stops stats
 // collection and cleanly removes contents and metadata from the filesystem.
-func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemove bool) (err error) {
+func (daemon *Daemon) cleanupContainer(ctx context.Context, container *container.Container, forceRemove bool) (err error) {
 	if container.IsRunning() {
 		if !forceRemove {
 			err := fmt.Errorf("You cannot remove a running container %s. Stop the container before attempting removal or use -f", container.ID)
 			return errors.NewRequestConflictError(err)
 		}
-		if err := daemon.Kill(container); err != nil {
+		if err := daemon.Kill(ctx, container); err != nil {
 			return fmt.Errorf("Could not kill running container %s, cannot remove - %v", container.ID, err)
 		}
 	}
@@ -92,7 +110,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 	// if stats are currently getting collected.
 	daemon.statsCollector.stopCollection(container)
 
-	if err = daemon.containerStop(container, 3); err != nil {
+	if err = daemon.containerStop(ctx, container, 3); err != nil {
 		return err
 	}
 
@@ -102,7 +120,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 	// Save container state to disk. So that if error happens before
 	// container meta file got removed from disk, then a restart of
 	// docker should not make a dead container alive.
-	if err := container.ToDiskLocking(); err != nil && !os.IsNotExist(err) {
+	if err := daemon.checkpointAndSave(container); err != nil && !os.IsNotExist(err) {
 		logrus.Errorf("Error saving dying container to disk: %v", err)
 	}
 
@@ -115,7 +133,13 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 			selinuxFreeLxcContexts(container.ProcessLabel)
 			daemon.idIndex.Delete(container.ID)
 			daemon.containers.Delete(container.ID)
-			daemon.LogContainerEvent(container, "destroy")
+			if err := daemon.containersReplica.Delete(container); err != nil {
+				logrus.Errorf("Error removing container %s from the replica: %v", container.ID, err)
+			}
+			if daemon.idRanges != nil {
+				daemon.idRanges.release(container.ID)
+			}
+			daemon.LogContainerEventWithAttributes(container, "destroy", requestAttributes(ctx))
 		}
 	}()
 
@@ -139,7 +163,7 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 // VolumeRm removes the volume with the given name.
 // If the volume is referenced by a container it is not removed
 // This is called directly from the remote API
-func (daemon *Daemon) VolumeRm(name string) error {
+func (daemon *Daemon) VolumeRm(ctx context.Context, name string) error {
 	v, err := daemon.volumes.Get(name)
 	if err != nil {
 		return err
@@ -152,6 +176,8 @@ func (daemon *Daemon) VolumeRm(name string) error {
 		}
 		return fmt.Errorf("Error while removing volume %s: %v", name, err)
 	}
-	daemon.LogVolumeEvent(v.Name(), "destroy", map[string]string{"driver": v.DriverName()})
+	attributes := requestAttributes(ctx)
+	attributes["driver"] = v.DriverName()
+	daemon.LogVolumeEvent(v.Name(), "destroy", attributes)
 	return nil
 }
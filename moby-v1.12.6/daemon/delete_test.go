@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 func TestContainerDoubleDelete(t *testing.T) {
@@ -36,7 +37,7 @@ func TestContainerDoubleDelete(t *testing.T) {
 
 	// Try to remove the container when its start is removalInProgress.
 	// It should ignore the container and not return an error.
-	if err := daemon.ContainerRm(container.ID, &types.ContainerRmConfig{ForceRemove: true}); err != nil {
+	if err := daemon.ContainerRm(context.Background(), container.ID, &types.ContainerRmConfig{ForceRemove: true}); err != nil {
 		t.Fatal(err)
 	}
 }
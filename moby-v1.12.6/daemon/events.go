@@ -6,11 +6,51 @@ import (
 
 	"github.com/docker/docker/container"
 	daemonevents "github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/pkg/authentication"
 	"github.com/docker/engine-api/types/events"
 	"github.com/docker/engine-api/types/filters"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
+// requestAttributes returns new attributes for the events of an API
+// request, holding the authenticated user making the request, if any.
+func requestAttributes(ctx context.Context) map[string]string {
+	attributes := map[string]string{}
+	if user := authentication.UserFromContext(ctx); user != nil {
+		attributes[daemonevents.UserAttribute] = user.Name
+	}
+	return attributes
+}
+
+// setStateEventAttributes sets the attributes of the events of the state
+// changes containerd reports for a container while it is locked, or clears
+// them if attributes is nil.
+func (daemon *Daemon) setStateEventAttributes(id string, attributes map[string]string) {
+	daemon.stateEventsLock.Lock()
+	defer daemon.stateEventsLock.Unlock()
+	if attributes == nil {
+		delete(daemon.stateEvents, id)
+		return
+	}
+	if daemon.stateEvents == nil {
+		daemon.stateEvents = make(map[string]map[string]string)
+	}
+	daemon.stateEvents[id] = attributes
+}
+
+// stateEventAttributes returns new attributes for the event of a state
+// change of a container.
+func (daemon *Daemon) stateEventAttributes(id string) map[string]string {
+	daemon.stateEventsLock.Lock()
+	defer daemon.stateEventsLock.Unlock()
+	attributes := map[string]string{}
+	for k, v := range daemon.stateEvents[id] {
+		attributes[k] = v
+	}
+	return attributes
+}
+
 // LogContainerEvent generates an event related to a container with only the default attributes.
 func (daemon *Daemon) LogContainerEvent(container *container.Container, action string) {
 	daemon.LogContainerEventWithAttributes(container, action, map[string]string{})
@@ -122,11 +162,15 @@ func (daemon *Daemon) UnsubscribeFromEvents(listener chan interface{}) {
 }
 
 // copyAttributes guarantees that labels are not mutated by event triggers.
+// Labels do not override the user of the request causing the event.
 func copyAttributes(attributes, labels map[string]string) {
 	if labels == nil {
 		return
 	}
 	for k, v := range labels {
+		if k == daemonevents.UserAttribute {
+			continue
+		}
 		attributes[k] = v
 	}
 }
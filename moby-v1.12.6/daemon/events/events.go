@@ -13,6 +13,11 @@ const (
 	bufferSize  = 1024
 )
 
+// UserAttribute is the event attribute holding the name of the
+// authenticated user whose API request caused the event. Label keys in the
+// com.docker namespace are reserved, and labels never override it.
+const UserAttribute = "com.docker.authn.user"
+
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
 	mu     sync.Mutex
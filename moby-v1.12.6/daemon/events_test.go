@@ -6,8 +6,10 @@ import (
 
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/pkg/authentication"
 	containertypes "github.com/docker/engine-api/types/container"
 	eventtypes "github.com/docker/engine-api/types/events"
+	"golang.org/x/net/context"
 )
 
 func TestLogContainerEventCopyLabels(t *testing.T) {
@@ -75,6 +77,54 @@ func TestLogContainerEventWithAttributes(t *testing.T) {
 	})
 }
 
+func TestLogContainerEventRequestUser(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	container := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:   "container_id",
+			Name: "container_name",
+			Config: &containertypes.Config{
+				Labels: map[string]string{
+					"user":               "label",
+					events.UserAttribute: "label",
+				},
+			},
+		},
+	}
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	ctx := authentication.WithUser(context.Background(), &authentication.User{Name: "alice"})
+	daemon.LogContainerEventWithAttributes(container, "create", requestAttributes(ctx))
+
+	validateTestAttributes(t, l, map[string]string{
+		"user":               "label",
+		events.UserAttribute: "alice",
+	})
+
+	daemon.setStateEventAttributes(container.ID, requestAttributes(ctx))
+	daemon.LogContainerEventWithAttributes(container, "start", daemon.stateEventAttributes(container.ID))
+	validateTestAttributes(t, l, map[string]string{
+		events.UserAttribute: "alice",
+	})
+
+	// Without a request, the label is still not copied into the attribute.
+	daemon.setStateEventAttributes(container.ID, nil)
+	daemon.LogContainerEventWithAttributes(container, "die", daemon.stateEventAttributes(container.ID))
+	select {
+	case ev := <-l:
+		event := ev.(eventtypes.Message)
+		if user, ok := event.Actor.Attributes[events.UserAttribute]; ok {
+			t.Fatalf("Expected no %s attribute, got %q", events.UserAttribute, user)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("LogEvent test timed out")
+	}
+}
+
 func validateTestAttributes(t *testing.T, l chan interface{}, expectedAttributesToTest map[string]string) {
 	select {
 	case ev := <-l:
@@ -3,7 +3,10 @@ package daemon
 import (
 	"fmt"
 	"io"
+	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"golang.org/x/net/context"
@@ -16,6 +19,7 @@ import (
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/term"
+	"github.com/docker/docker/utils"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/strslice"
 )
@@ -92,7 +96,7 @@ func (d *Daemon) getActiveContainer(name string) (*container.Container, error) {
 }
 
 // ContainerExecCreate sets up an exec in a running container.
-func (d *Daemon) ContainerExecCreate(name string, config *types.ExecConfig) (string, error) {
+func (d *Daemon) ContainerExecCreate(ctx context.Context, name string, config *types.ExecConfig) (string, error) {
 	container, err := d.getActiveContainer(name)
 	if err != nil {
 		return "", err
@@ -124,10 +128,24 @@ func (d *Daemon) ContainerExecCreate(name string, config *types.ExecConfig) (str
 	if len(execConfig.User) == 0 {
 		execConfig.User = container.Config.User
 	}
+	execConfig.WorkingDir = config.WorkingDir
+	if config.Timeout < 0 {
+		return "", fmt.Errorf("Invalid exec timeout (%d), must not be negative", config.Timeout)
+	}
+	execConfig.Timeout = time.Duration(config.Timeout) * time.Second
+	if len(config.Env) > 0 {
+		// The variables given for the exec override those of the container,
+		// which the exec would otherwise inherit.
+		linkedEnv, err := d.setupLinkedContainers(container)
+		if err != nil {
+			return "", err
+		}
+		execConfig.Env = utils.ReplaceOrAppendEnvValues(container.CreateDaemonEnvironment(linkedEnv), config.Env)
+	}
 
 	d.registerExecCommand(container, execConfig)
 
-	d.LogContainerEvent(container, "exec_create: "+execConfig.Entrypoint+" "+strings.Join(execConfig.Args, " "))
+	d.LogContainerEventWithAttributes(container, "exec_create: "+execConfig.Entrypoint+" "+strings.Join(execConfig.Args, " "), requestAttributes(ctx))
 
 	return execConfig.ID, nil
 }
@@ -169,7 +187,7 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 
 	c := d.containers.Get(ec.ContainerID)
 	logrus.Debugf("starting exec command %s in container %s", ec.ID, c.ID)
-	d.LogContainerEvent(c, "exec_start: "+ec.Entrypoint+" "+strings.Join(ec.Args, " "))
+	d.LogContainerEventWithAttributes(c, "exec_start: "+ec.Entrypoint+" "+strings.Join(ec.Args, " "), requestAttributes(ctx))
 
 	if ec.OpenStdin && stdin != nil {
 		r, w := io.Pipe()
@@ -208,6 +226,12 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 		return err
 	}
 
+	ec.Lock()
+	ec.StartTimeout(func() {
+		d.killExecOnTimeout(c, ec)
+	})
+	ec.Unlock()
+
 	select {
 	case <-ctx.Done():
 		logrus.Debugf("Sending TERM signal to process %v in container %v", name, c.ID)
@@ -231,6 +255,61 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 	return nil
 }
 
+// killExecOnTimeout kills the process of an exec which is still running
+// once its timeout has expired.
+func (d *Daemon) killExecOnTimeout(c *container.Container, ec *exec.Config) {
+	ec.Lock()
+	running := ec.Running
+	ec.Unlock()
+	if !running {
+		return
+	}
+
+	logrus.Infof("Container %v, exec %v failed to exit within its timeout of %v - killing it", c.ID, ec.ID, ec.Timeout)
+	if err := d.containerd.SignalProcess(c.ID, ec.ID, int(signal.SignalMap["KILL"])); err != nil {
+		logrus.Errorf("Error killing exec %v in container %v: %v", ec.ID, c.ID, err)
+		return
+	}
+	d.LogContainerEventWithAttributes(c, "exec_timeout", map[string]string{
+		"execID": ec.ID,
+	})
+}
+
+// ContainerExecKill sends the given signal to the process of a running
+// exec. If no signal is given, the process is killed.
+func (d *Daemon) ContainerExecKill(ctx context.Context, name string, sig uint64) error {
+	ec, err := d.getExecConfig(name)
+	if err != nil {
+		return err
+	}
+
+	if sig == 0 {
+		sig = uint64(signal.SignalMap["KILL"])
+	}
+	if !signal.ValidSignalForPlatform(syscall.Signal(sig)) {
+		return fmt.Errorf("The %s daemon does not support signal %d", runtime.GOOS, sig)
+	}
+
+	ec.Lock()
+	running := ec.Running
+	ec.Unlock()
+	if !running {
+		err := fmt.Errorf("Exec %s is not running", ec.ID)
+		return errors.NewRequestConflictError(err)
+	}
+
+	if err := d.containerd.SignalProcess(ec.ContainerID, ec.ID, int(sig)); err != nil {
+		return err
+	}
+
+	c := d.containers.Get(ec.ContainerID)
+	attributes := requestAttributes(ctx)
+	attributes["execID"] = ec.ID
+	attributes["signal"] = strconv.FormatUint(sig, 10)
+	d.LogContainerEventWithAttributes(c, "exec_kill", attributes)
+	return nil
+}
+
 // execCommandGC runs a ticker to clean up the daemon references
 // of exec configs that are no longer part of the container.
 func (d *Daemon) execCommandGC() {
@@ -3,6 +3,7 @@ package exec
 import (
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container/stream"
@@ -30,6 +31,11 @@ type Config struct {
 	Tty          bool
 	Privileged   bool
 	User         string
+	Env          []string
+	WorkingDir   string
+	Timeout      time.Duration
+
+	timeoutTimer *time.Timer
 }
 
 // NewConfig initializes the a new exec configuration
@@ -55,6 +61,25 @@ func (c *Config) InitializeStdio(iop libcontainerd.IOPipe) error {
 	return nil
 }
 
+// StartTimeout calls kill once the exec has been running for longer than its
+// timeout. It does nothing if the exec has no timeout. The caller must hold
+// the lock on c.
+func (c *Config) StartTimeout(kill func()) {
+	if c.Timeout <= 0 {
+		return
+	}
+	c.timeoutTimer = time.AfterFunc(c.Timeout, kill)
+}
+
+// StopTimeout cancels the timeout started by StartTimeout. The caller must
+// hold the lock on c.
+func (c *Config) StopTimeout() {
+	if c.timeoutTimer != nil {
+		c.timeoutTimer.Stop()
+		c.timeoutTimer = nil
+	}
+}
+
 // CloseStreams closes the stdio streams for the exec
 func (c *Config) CloseStreams() error {
 	return c.StreamConfig.CloseStreams()
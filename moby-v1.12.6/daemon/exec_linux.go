@@ -22,5 +22,11 @@ func execSetPlatformOpt(c *container.Container, ec *exec.Config, p *libcontainer
 	if ec.Privileged {
 		p.Capabilities = caps.GetAllCapabilities()
 	}
+	if len(ec.Env) > 0 {
+		p.Env = ec.Env
+	}
+	if ec.WorkingDir != "" {
+		p.Cwd = &ec.WorkingDir
+	}
 	return nil
 }
@@ -9,5 +9,11 @@ import (
 func execSetPlatformOpt(c *container.Container, ec *exec.Config, p *libcontainerd.Process) error {
 	// Process arguments need to be escaped before sending to OCI.
 	p.Args = escapeArgs(p.Args)
+	if len(ec.Env) > 0 {
+		p.Env = ec.Env
+	}
+	if ec.WorkingDir != "" {
+		p.Cwd = ec.WorkingDir
+	}
 	return nil
 }
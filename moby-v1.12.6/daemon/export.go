@@ -7,17 +7,18 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/ioutils"
+	"golang.org/x/net/context"
 )
 
 // ContainerExport writes the contents of the container to the given
 // writer. An error is returned if the container cannot be found.
-func (daemon *Daemon) ContainerExport(name string, out io.Writer) error {
+func (daemon *Daemon) ContainerExport(ctx context.Context, name string, out io.Writer) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
 
-	data, err := daemon.containerExport(container)
+	data, err := daemon.containerExport(ctx, container)
 	if err != nil {
 		return fmt.Errorf("Error exporting container %s: %v", name, err)
 	}
@@ -30,12 +31,12 @@ func (daemon *Daemon) ContainerExport(name string, out io.Writer) error {
 	return nil
 }
 
-func (daemon *Daemon) containerExport(container *container.Container) (archive.Archive, error) {
+func (daemon *Daemon) containerExport(ctx context.Context, container *container.Container) (archive.Archive, error) {
 	if err := daemon.Mount(container); err != nil {
 		return nil, err
 	}
 
-	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	uidMaps, gidMaps := daemon.containerIDMaps(container)
 	archive, err := archive.TarWithOptions(container.BaseFS, &archive.TarOptions{
 		Compression: archive.Uncompressed,
 		UIDMaps:     uidMaps,
@@ -50,6 +51,6 @@ func (daemon *Daemon) containerExport(container *container.Container) (archive.A
 		daemon.Unmount(container)
 		return err
 	})
-	daemon.LogContainerEvent(container, "export")
+	daemon.LogContainerEventWithAttributes(container, "export", requestAttributes(ctx))
 	return arch, err
 }
@@ -136,6 +136,10 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 		// Else we're starting or healthy. Stay in that state.
 	}
 
+	if err := d.containersReplica.Save(c); err != nil {
+		logrus.Errorf("Error saving health status of container %s: %v", c.ID, err)
+	}
+
 	if oldStatus != h.Status {
 		d.LogContainerEvent(c, "health_status: "+h.Status)
 	}
@@ -259,6 +263,9 @@ func (d *Daemon) initHealthMonitor(c *container.Container) {
 		h.Status = types.Starting
 		c.State.Health = h
 	}
+	if err := d.containersReplica.Save(c); err != nil {
+		logrus.Errorf("Error saving health status of container %s: %v", c.ID, err)
+	}
 
 	d.updateHealthMonitor(c)
 }
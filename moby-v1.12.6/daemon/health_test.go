@@ -65,8 +65,13 @@ func TestHealthStates(t *testing.T) {
 			},
 		},
 	}
+	store, err := container.NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
 	daemon := &Daemon{
-		EventsService: e,
+		EventsService:     e,
+		containersReplica: store,
 	}
 
 	c.Config.Healthcheck = &containertypes.HealthConfig{
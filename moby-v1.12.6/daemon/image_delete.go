@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 type conflictType int
@@ -60,7 +61,7 @@ const (
 // FIXME: remove ImageDelete's dependency on Daemon, then move to the graph
 // package. This would require that we no longer need the daemon to determine
 // whether images are being used by a stopped or running container.
-func (daemon *Daemon) ImageDelete(imageRef string, force, prune bool) ([]types.ImageDelete, error) {
+func (daemon *Daemon) ImageDelete(ctx context.Context, imageRef string, force, prune bool) ([]types.ImageDelete, error) {
 	records := []types.ImageDelete{}
 
 	imgID, err := daemon.GetImageID(imageRef)
@@ -99,7 +100,7 @@ func (daemon *Daemon) ImageDelete(imageRef string, force, prune bool) ([]types.I
 
 		untaggedRecord := types.ImageDelete{Untagged: parsedRef.String()}
 
-		daemon.LogImageEvent(imgID.String(), imgID.String(), "untag")
+		daemon.LogImageEventWithAttributes(imgID.String(), imgID.String(), "untag", requestAttributes(ctx))
 		records = append(records, untaggedRecord)
 
 		repoRefs = daemon.referenceStore.References(imgID)
@@ -162,13 +163,13 @@ func (daemon *Daemon) ImageDelete(imageRef string, force, prune bool) ([]types.I
 
 				untaggedRecord := types.ImageDelete{Untagged: parsedRef.String()}
 
-				daemon.LogImageEvent(imgID.String(), imgID.String(), "untag")
+				daemon.LogImageEventWithAttributes(imgID.String(), imgID.String(), "untag", requestAttributes(ctx))
 				records = append(records, untaggedRecord)
 			}
 		}
 	}
 
-	return records, daemon.imageDeleteHelper(imgID, &records, force, prune, removedRepositoryRef)
+	return records, daemon.imageDeleteHelper(ctx, imgID, &records, force, prune, removedRepositoryRef)
 }
 
 // isSingleReference returns true when all references are from one repository
@@ -238,7 +239,7 @@ func (daemon *Daemon) removeImageRef(ref reference.Named) (reference.Named, erro
 // on the first encountered error. Removed references are logged to this
 // daemon's event service. An "Untagged" types.ImageDelete is added to the
 // given list of records.
-func (daemon *Daemon) removeAllReferencesToImageID(imgID image.ID, records *[]types.ImageDelete) error {
+func (daemon *Daemon) removeAllReferencesToImageID(ctx context.Context, imgID image.ID, records *[]types.ImageDelete) error {
 	imageRefs := daemon.referenceStore.References(imgID)
 
 	for _, imageRef := range imageRefs {
@@ -249,7 +250,7 @@ func (daemon *Daemon) removeAllReferencesToImageID(imgID image.ID, records *[]ty
 
 		untaggedRecord := types.ImageDelete{Untagged: parsedRef.String()}
 
-		daemon.LogImageEvent(imgID.String(), imgID.String(), "untag")
+		daemon.LogImageEventWithAttributes(imgID.String(), imgID.String(), "untag", requestAttributes(ctx))
 		*records = append(*records, untaggedRecord)
 	}
 
@@ -287,7 +288,7 @@ func (idc *imageDeleteConflict) Error() string {
 // conflict is encountered, it will be returned immediately without deleting
 // the image. If quiet is true, any encountered conflicts will be ignored and
 // the function will return nil immediately without deleting the image.
-func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDelete, force, prune, quiet bool) error {
+func (daemon *Daemon) imageDeleteHelper(ctx context.Context, imgID image.ID, records *[]types.ImageDelete, force, prune, quiet bool) error {
 	// First, determine if this image has any conflicts. Ignore soft conflicts
 	// if force is true.
 	c := conflictHard
@@ -313,7 +314,7 @@ func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDe
 	}
 
 	// Delete all repository tag/digest references to this image.
-	if err := daemon.removeAllReferencesToImageID(imgID, records); err != nil {
+	if err := daemon.removeAllReferencesToImageID(ctx, imgID, records); err != nil {
 		return err
 	}
 
@@ -322,7 +323,7 @@ func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDe
 		return err
 	}
 
-	daemon.LogImageEvent(imgID.String(), imgID.String(), "delete")
+	daemon.LogImageEventWithAttributes(imgID.String(), imgID.String(), "delete", requestAttributes(ctx))
 	*records = append(*records, types.ImageDelete{Deleted: imgID.String()})
 	for _, removedLayer := range removedLayers {
 		*records = append(*records, types.ImageDelete{Deleted: removedLayer.ChainID.String()})
@@ -337,7 +338,7 @@ func (daemon *Daemon) imageDeleteHelper(imgID image.ID, records *[]types.ImageDe
 	// either running or stopped).
 	// Do not force prunings, but do so quietly (stopping on any encountered
 	// conflicts).
-	return daemon.imageDeleteHelper(parent, records, false, true, true)
+	return daemon.imageDeleteHelper(ctx, parent, records, false, true, true)
 }
 
 // checkImageDeleteConflict determines whether there are any conflicts
@@ -4,22 +4,36 @@ import (
 	"io"
 
 	"github.com/docker/docker/image/tarexport"
+	"golang.org/x/net/context"
 )
 
+// imageEventLogger logs image events with the attributes of the API
+// request that caused them.
+type imageEventLogger struct {
+	daemon *Daemon
+	ctx    context.Context
+}
+
+// LogImageEvent generates an event related to an image with the attributes
+// of the request.
+func (l imageEventLogger) LogImageEvent(imageID, refName, action string) {
+	l.daemon.LogImageEventWithAttributes(imageID, refName, action, requestAttributes(l.ctx))
+}
+
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
 // the same tag are exported. names is the set of tags to export, and
 // outStream is the writer which the images are written to.
-func (daemon *Daemon) ExportImage(names []string, outStream io.Writer) error {
-	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, daemon)
+func (daemon *Daemon) ExportImage(ctx context.Context, names []string, outStream io.Writer) error {
+	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, imageEventLogger{daemon, ctx})
 	return imageExporter.Save(names, outStream)
 }
 
 // LoadImage uploads a set of images into the repository. This is the
 // complement of ImageExport.  The input stream is an uncompressed tar
 // ball containing images and metadata.
-func (daemon *Daemon) LoadImage(inTar io.ReadCloser, outStream io.Writer, quiet bool) error {
-	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, daemon)
+func (daemon *Daemon) LoadImage(ctx context.Context, inTar io.ReadCloser, outStream io.Writer, quiet bool) error {
+	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, imageEventLogger{daemon, ctx})
 	return imageExporter.Load(inTar, outStream, quiet)
 }
@@ -92,7 +92,7 @@ func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.
 		AuthConfig:       authConfig,
 		ProgressOutput:   progress.ChanOutput(progressChan),
 		RegistryService:  daemon.RegistryService,
-		ImageEventLogger: daemon.LogImageEvent,
+		ImageEventLogger: imageEventLogger{daemon, ctx}.LogImageEvent,
 		MetadataStore:    daemon.distributionMetadataStore,
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
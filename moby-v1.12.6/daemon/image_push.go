@@ -1,9 +1,11 @@
 package daemon
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/docker/docker/distribution"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
@@ -24,6 +26,11 @@ func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, metaHead
 		}
 	}
 
+	layerCompression, err := pushCompression(daemon.configStore.PushCompression)
+	if err != nil {
+		return err
+	}
+
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
 	progressChan := make(chan progress.Progress, 100)
@@ -42,13 +49,14 @@ func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, metaHead
 		AuthConfig:       authConfig,
 		ProgressOutput:   progress.ChanOutput(progressChan),
 		RegistryService:  daemon.RegistryService,
-		ImageEventLogger: daemon.LogImageEvent,
+		ImageEventLogger: imageEventLogger{daemon, ctx}.LogImageEvent,
 		MetadataStore:    daemon.distributionMetadataStore,
 		LayerStore:       daemon.layerStore,
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
 		TrustKey:         daemon.trustKey,
 		UploadManager:    daemon.uploadManager,
+		LayerCompression: layerCompression,
 	}
 
 	err = distribution.Push(ctx, ref, imagePushConfig)
@@ -56,3 +64,16 @@ func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, metaHead
 	<-writesDone
 	return err
 }
+
+// pushCompression returns the compression for pushed layers configured by
+// the push-compression option.
+func pushCompression(name string) (archive.Compression, error) {
+	switch name {
+	case "", "gzip":
+		return archive.Gzip, nil
+	case "zstd":
+		return archive.Zstd, nil
+	default:
+		return archive.Uncompressed, fmt.Errorf("invalid push compression: %s (must be gzip or zstd)", name)
+	}
+}
@@ -3,11 +3,12 @@ package daemon
 import (
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/reference"
+	"golang.org/x/net/context"
 )
 
 // TagImage creates the tag specified by newTag, pointing to the image named
 // imageName (alternatively, imageName can also be an image ID).
-func (daemon *Daemon) TagImage(imageName, repository, tag string) error {
+func (daemon *Daemon) TagImage(ctx context.Context, imageName, repository, tag string) error {
 	imageID, err := daemon.GetImageID(imageName)
 	if err != nil {
 		return err
@@ -23,15 +24,15 @@ func (daemon *Daemon) TagImage(imageName, repository, tag string) error {
 		}
 	}
 
-	return daemon.TagImageWithReference(imageID, newTag)
+	return daemon.TagImageWithReference(ctx, imageID, newTag)
 }
 
 // TagImageWithReference adds the given reference to the image ID provided.
-func (daemon *Daemon) TagImageWithReference(imageID image.ID, newTag reference.Named) error {
+func (daemon *Daemon) TagImageWithReference(ctx context.Context, imageID image.ID, newTag reference.Named) error {
 	if err := daemon.referenceStore.AddTag(newTag, imageID, true); err != nil {
 		return err
 	}
 
-	daemon.LogImageEvent(imageID.String(), newTag.String(), "tag")
+	daemon.LogImageEventWithAttributes(imageID.String(), newTag.String(), "tag", requestAttributes(ctx))
 	return nil
 }
@@ -19,13 +19,14 @@ import (
 	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 // ImportImage imports an image, getting the archived layer data either from
 // inConfig (if src is "-"), or from a URI specified in src. Progress output is
 // written to outStream. Repository and tag names can optionally be given in
 // the repo and tag arguments, respectively.
-func (daemon *Daemon) ImportImage(src string, repository, tag string, msg string, inConfig io.ReadCloser, outStream io.Writer, changes []string) error {
+func (daemon *Daemon) ImportImage(ctx context.Context, src string, repository, tag string, msg string, inConfig io.ReadCloser, outStream io.Writer, changes []string) error {
 	var (
 		sf     = streamformatter.NewJSONStreamFormatter()
 		rc     io.ReadCloser
@@ -124,12 +125,12 @@ func (daemon *Daemon) ImportImage(src string, repository, tag string, msg string
 
 	// FIXME: connect with commit code and call refstore directly
 	if newRef != nil {
-		if err := daemon.TagImageWithReference(id, newRef); err != nil {
+		if err := daemon.TagImageWithReference(ctx, id, newRef); err != nil {
 			return err
 		}
 	}
 
-	daemon.LogImageEvent(id.String(), id.String(), "import")
+	daemon.LogImageEventWithAttributes(id.String(), id.String(), "import", requestAttributes(ctx))
 	outStream.Write(sf.FormatStatus("", id.String()))
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/network"
@@ -29,14 +30,11 @@ func (daemon *Daemon) ContainerInspect(name string, size bool, version string) (
 // ContainerInspectCurrent returns low-level information about a
 // container in a most recent api version.
 func (daemon *Daemon) ContainerInspectCurrent(name string, size bool) (*types.ContainerJSON, error) {
-	container, err := daemon.GetContainer(name)
+	container, err := daemon.getInspectContainer(name)
 	if err != nil {
 		return nil, err
 	}
 
-	container.Lock()
-	defer container.Unlock()
-
 	base, err := daemon.getInspectData(container, size)
 	if err != nil {
 		return nil, err
@@ -69,14 +67,11 @@ func (daemon *Daemon) ContainerInspectCurrent(name string, size bool) (*types.Co
 
 // containerInspect120 serializes the master version of a container into a json type.
 func (daemon *Daemon) containerInspect120(name string) (*v1p20.ContainerJSON, error) {
-	container, err := daemon.GetContainer(name)
+	container, err := daemon.getInspectContainer(name)
 	if err != nil {
 		return nil, err
 	}
 
-	container.Lock()
-	defer container.Unlock()
-
 	base, err := daemon.getInspectData(container, false)
 	if err != nil {
 		return nil, err
@@ -100,11 +95,23 @@ func (daemon *Daemon) containerInspect120(name string) (*v1p20.ContainerJSON, er
 	}, nil
 }
 
+// getInspectContainer returns the copy of the container kept in the
+// containers replica, which is read without taking the container lock.
+func (daemon *Daemon) getInspectContainer(name string) (*container.Container, error) {
+	c, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+	return daemon.containersReplica.Snapshot().Get(c.ID)
+}
+
 func (daemon *Daemon) getInspectData(container *container.Container, size bool) (*types.ContainerJSONBase, error) {
 	// make a copy to play with
 	hostConfig := *container.HostConfig
 
-	children := daemon.children(container)
+	// links are indexed by the container registered in the daemon, not
+	// by its copy
+	children := daemon.children(daemon.containers.Get(container.ID))
 	hostConfig.Links = nil // do not expose the internal structure
 	for linkAlias, child := range children {
 		hostConfig.Links = append(hostConfig.Links, fmt.Sprintf("%s:%s", child.Name, linkAlias))
@@ -156,7 +163,10 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 		sizeRootFs int64
 	)
 	if size {
-		sizeRw, sizeRootFs = daemon.getSize(container)
+		// mounting records the base filesystem on the container, so
+		// measure a private copy rather than the shared one
+		c := *container
+		sizeRw, sizeRootFs = daemon.getSize(&c)
 		contJSONBase.SizeRw = &sizeRw
 		contJSONBase.SizeRootFs = &sizeRootFs
 	}
@@ -200,8 +210,9 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 }
 
 // VolumeInspect looks up a volume by name. An error is returned if
-// the volume cannot be found.
-func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
+// the volume cannot be found. The size of the volume is only computed if
+// size is set.
+func (daemon *Daemon) VolumeInspect(name string, size bool) (*types.Volume, error) {
 	v, err := daemon.volumes.Get(name)
 	if err != nil {
 		return nil, err
@@ -209,6 +220,9 @@ func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
 	apiV := volumeToAPIType(v)
 	apiV.Mountpoint = v.Path()
 	apiV.Status = v.Status()
+	if err := daemon.setVolumeUsage(apiV, v, size); err != nil {
+		logrus.Warnf("Error getting the usage of volume %s: %v", v.Name(), err)
+	}
 	return apiV, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/pkg/signal"
+	"golang.org/x/net/context"
 )
 
 type errNoSuchProcess struct {
@@ -32,7 +33,7 @@ func isErrNoSuchProcess(err error) bool {
 // If no signal is given (sig 0), then Kill with SIGKILL and wait
 // for the container to exit.
 // If a signal is given, then just send it to the container and return.
-func (daemon *Daemon) ContainerKill(name string, sig uint64) error {
+func (daemon *Daemon) ContainerKill(ctx context.Context, name string, sig uint64) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -44,9 +45,9 @@ func (daemon *Daemon) ContainerKill(name string, sig uint64) error {
 
 	// If no signal is passed, or SIGKILL, perform regular Kill (SIGKILL + wait())
 	if sig == 0 || syscall.Signal(sig) == syscall.SIGKILL {
-		return daemon.Kill(container)
+		return daemon.Kill(ctx, container)
 	}
-	return daemon.killWithSignal(container, int(sig))
+	return daemon.killWithSignal(ctx, container, int(sig))
 }
 
 // killWithSignal sends the container the given signal. This wrapper for the
@@ -54,7 +55,7 @@ func (daemon *Daemon) ContainerKill(name string, sig uint64) error {
 // to send the signal. An error is returned if the container is paused
 // or not running, or if there is a problem returned from the
 // underlying kill command.
-func (daemon *Daemon) killWithSignal(container *container.Container, sig int) error {
+func (daemon *Daemon) killWithSignal(ctx context.Context, container *container.Container, sig int) error {
 	logrus.Debugf("Sending %d to %s", sig, container.ID)
 	container.Lock()
 	defer container.Unlock()
@@ -92,21 +93,20 @@ func (daemon *Daemon) killWithSignal(container *container.Container, sig int) er
 		}
 	}
 
-	attributes := map[string]string{
-		"signal": fmt.Sprintf("%d", sig),
-	}
+	attributes := requestAttributes(ctx)
+	attributes["signal"] = fmt.Sprintf("%d", sig)
 	daemon.LogContainerEventWithAttributes(container, "kill", attributes)
 	return nil
 }
 
 // Kill forcefully terminates a container.
-func (daemon *Daemon) Kill(container *container.Container) error {
+func (daemon *Daemon) Kill(ctx context.Context, container *container.Container) error {
 	if !container.IsRunning() {
 		return errNotRunning{container.ID}
 	}
 
 	// 1. Send SIGKILL
-	if err := daemon.killPossiblyDeadProcess(container, int(syscall.SIGKILL)); err != nil {
+	if err := daemon.killPossiblyDeadProcess(ctx, container, int(syscall.SIGKILL)); err != nil {
 		// While normally we might "return err" here we're not going to
 		// because if we can't stop the container by this point then
 		// its probably because its already stopped. Meaning, between
@@ -142,8 +142,8 @@ func (daemon *Daemon) Kill(container *container.Container) error {
 }
 
 // killPossibleDeadProcess is a wrapper around killSig() suppressing "no such process" error.
-func (daemon *Daemon) killPossiblyDeadProcess(container *container.Container, sig int) error {
-	err := daemon.killWithSignal(container, sig)
+func (daemon *Daemon) killPossiblyDeadProcess(ctx context.Context, container *container.Container, sig int) error {
+	err := daemon.killWithSignal(ctx, container, sig)
 	if err == syscall.ESRCH {
 		e := errNoSuchProcess{container.GetPID(), sig}
 		logrus.Debug(e)
@@ -112,15 +112,28 @@ func (daemon *Daemon) ListContainersForNode(nodeID string) []string {
 	return ids
 }
 
-func (daemon *Daemon) filterByNameIDMatches(ctx *listContext) []*container.Container {
+// filterByNameIDMatches returns the containers of the view to consider for
+// the user's filters. Name and ID filters are looked up directly, while the
+// ancestor, volume and network filters are answered by the indexes of the
+// view, so that only the matching containers get iterated over.
+func (daemon *Daemon) filterByNameIDMatches(view container.View, ctx *listContext) ([]*container.Container, error) {
 	idSearch := false
 	names := ctx.filters.Get("name")
 	ids := ctx.filters.Get("id")
+
+	indexed, err := daemon.filterByIndexes(view, ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(names)+len(ids) == 0 {
-		// if name or ID filters are not in use, return to
-		// standard behavior of walking the entire container
-		// list from the daemon's in-memory store
-		return daemon.List()
+		if indexed == nil {
+			// if no filter can narrow the search, return to
+			// standard behavior of walking the entire container
+			// list of the view
+			return view.All()
+		}
+		return containersFromView(view, indexed), nil
 	}
 
 	// idSearch will determine if we limit name matching to the IDs
@@ -155,9 +168,87 @@ func (daemon *Daemon) filterByNameIDMatches(ctx *listContext) []*container.Conta
 		}
 	}
 
-	cntrs := make([]*container.Container, 0, len(matches))
-	for id := range matches {
-		if c := daemon.containers.Get(id); c != nil {
+	if indexed != nil {
+		for id := range matches {
+			if !indexed[id] {
+				delete(matches, id)
+			}
+		}
+	}
+
+	return containersFromView(view, matches), nil
+}
+
+// filterByIndexes returns the IDs of the containers matching the ancestor,
+// volume and network filters, looked up in the indexes of the view. Values
+// of a filter are alternatives, while different filters must all match. It
+// returns nil if none of these filters is in use, or if the before and since
+// filters need to walk past containers the indexes would leave out.
+func (daemon *Daemon) filterByIndexes(view container.View, ctx *listContext) (map[string]bool, error) {
+	if ctx.beforeFilter != nil || ctx.sinceFilter != nil {
+		return nil, nil
+	}
+
+	var matches map[string]bool
+	intersect := func(ids map[string]bool) {
+		if matches == nil {
+			matches = ids
+			return
+		}
+		for id := range matches {
+			if !ids[id] {
+				delete(matches, id)
+			}
+		}
+	}
+
+	if ctx.ancestorFilter {
+		ids := make(map[string]bool)
+		for imageID := range ctx.images {
+			found, err := view.ByImage(imageID)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range found {
+				ids[id] = true
+			}
+		}
+		intersect(ids)
+	}
+
+	for filter, lookup := range map[string]func(string) ([]string, error){
+		"volume":  view.ByVolume,
+		"network": view.ByNetwork,
+	} {
+		if !ctx.filters.Include(filter) {
+			continue
+		}
+		ids := make(map[string]bool)
+		err := ctx.filters.WalkValues(filter, func(value string) error {
+			found, err := lookup(value)
+			if err != nil {
+				return err
+			}
+			for _, id := range found {
+				ids[id] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		intersect(ids)
+	}
+
+	return matches, nil
+}
+
+// containersFromView returns the containers of the view with the given IDs,
+// newest first.
+func containersFromView(view container.View, ids map[string]bool) []*container.Container {
+	cntrs := make([]*container.Container, 0, len(ids))
+	for id := range ids {
+		if c, err := view.Get(id); err == nil {
 			cntrs = append(cntrs, c)
 		}
 	}
@@ -173,15 +264,24 @@ func (daemon *Daemon) filterByNameIDMatches(ctx *listContext) []*container.Conta
 func (daemon *Daemon) reduceContainers(config *types.ContainerListOptions, reducer containerReducer) ([]*types.Container, error) {
 	containers := []*types.Container{}
 
+	// all the containers are read from a single snapshot of the replica,
+	// without taking their locks, so that a container stuck in a slow
+	// operation doesn't hold back the listing
+	view := daemon.containersReplica.Snapshot()
+
 	ctx, err := daemon.foldFilter(config)
 	if err != nil {
 		return nil, err
 	}
 
-	// fastpath to only look at a subset of containers if specific name
-	// or ID matches were provided by the user--otherwise we potentially
-	// end up locking and querying many more containers than intended
-	containerList := daemon.filterByNameIDMatches(ctx)
+	// fastpath to only look at a subset of containers if specific name,
+	// ID, ancestor, volume or network matches were provided by the
+	// user--otherwise we potentially end up querying many more containers
+	// than intended
+	containerList, err := daemon.filterByNameIDMatches(view, ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, container := range containerList {
 		t, err := daemon.reducePsContainer(container, ctx, reducer)
@@ -200,10 +300,8 @@ func (daemon *Daemon) reduceContainers(config *types.ContainerListOptions, reduc
 }
 
 // reducePsContainer is the basic representation for a container as expected by the ps command.
+// The container is a read-only copy taken from the containers replica.
 func (daemon *Daemon) reducePsContainer(container *container.Container, ctx *listContext, reducer containerReducer) (*types.Container, error) {
-	container.Lock()
-	defer container.Unlock()
-
 	// filter containers to return
 	action := includeContainerInList(container, ctx)
 	switch action {
@@ -521,7 +619,10 @@ func (daemon *Daemon) transformContainer(container *container.Container, ctx *li
 	}
 
 	if ctx.Size {
-		sizeRw, sizeRootFs := daemon.getSize(container)
+		// mounting records the base filesystem on the container, so
+		// measure a private copy rather than the shared one
+		c := *container
+		sizeRw, sizeRootFs := daemon.getSize(&c)
 		newC.SizeRw = sizeRw
 		newC.SizeRootFs = sizeRootFs
 	}
@@ -532,8 +633,9 @@ func (daemon *Daemon) transformContainer(container *container.Container, ctx *li
 }
 
 // Volumes lists known volumes, using the filter to restrict the range
-// of volumes returned.
-func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error) {
+// of volumes returned. The size of the volumes is only computed if size
+// is set.
+func (daemon *Daemon) Volumes(filter string, size bool) ([]*types.Volume, []string, error) {
 	var (
 		volumesOut []*types.Volume
 	)
@@ -564,6 +666,10 @@ func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error)
 		} else {
 			apiV.Mountpoint = v.Path()
 		}
+		apiV.Status = v.Status()
+		if err := daemon.setVolumeUsage(apiV, v, size); err != nil {
+			logrus.Warnf("Error getting the usage of volume %s: %v", v.Name(), err)
+		}
 		volumesOut = append(volumesOut, apiV)
 	}
 	return volumesOut, warnings, nil
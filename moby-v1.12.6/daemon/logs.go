@@ -30,16 +30,6 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 		return fmt.Errorf("You must choose at least one stream")
 	}
 
-	cLog, err := daemon.getLogger(container)
-	if err != nil {
-		return err
-	}
-	logReader, ok := cLog.(logger.LogReader)
-	if !ok {
-		return logger.ErrReadLogsNotSupported
-	}
-
-	follow := config.Follow && container.IsRunning()
 	tailLines, err := strconv.Atoi(config.Tail)
 	if err != nil {
 		tailLines = -1
@@ -58,9 +48,12 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	readConfig := logger.ReadConfig{
 		Since:  since,
 		Tail:   tailLines,
-		Follow: follow,
+		Follow: config.Follow,
+	}
+	logs, closeLogs, err := daemon.readContainerLogs(container, readConfig)
+	if err != nil {
+		return err
 	}
-	logs := logReader.ReadLogs(readConfig)
 
 	wf := ioutils.NewWriteFlusher(config.OutStream)
 	defer wf.Close()
@@ -85,14 +78,7 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 		case msg, ok := <-logs.Msg:
 			if !ok {
 				logrus.Debug("logs: end stream")
-				logs.Close()
-				if cLog != container.LogDriver {
-					// Since the logger isn't cached in the container, which occurs if it is running, it
-					// must get explicitly closed here to avoid leaking it and any file handles it has.
-					if err := cLog.Close(); err != nil {
-						logrus.Errorf("Error closing logger: %v", err)
-					}
-				}
+				closeLogs()
 				return nil
 			}
 			logLine := msg.Line
@@ -112,6 +98,66 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	}
 }
 
+// ContainerLogMessages reads the log messages of a container, calling fn for
+// each message until the logs are exhausted, the context is cancelled or fn
+// returns an error.
+func (daemon *Daemon) ContainerLogMessages(ctx context.Context, containerName string, config logger.ReadConfig, fn func(*logger.Message) error) error {
+	container, err := daemon.GetContainer(containerName)
+	if err != nil {
+		return err
+	}
+
+	logs, closeLogs, err := daemon.readContainerLogs(container, config)
+	if err != nil {
+		return err
+	}
+	defer closeLogs()
+
+	for {
+		select {
+		case err := <-logs.Err:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-logs.Msg:
+			if !ok {
+				return nil
+			}
+			if err := fn(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readContainerLogs starts reading the logs of the container. Following is
+// only honored for running containers. The returned function releases the
+// watcher and the logger once reading is done.
+func (daemon *Daemon) readContainerLogs(container *container.Container, config logger.ReadConfig) (*logger.LogWatcher, func(), error) {
+	cLog, err := daemon.getLogger(container)
+	if err != nil {
+		return nil, nil, err
+	}
+	logReader, ok := cLog.(logger.LogReader)
+	if !ok {
+		return nil, nil, logger.ErrReadLogsNotSupported
+	}
+
+	config.Follow = config.Follow && container.IsRunning()
+	logs := logReader.ReadLogs(config)
+
+	return logs, func() {
+		logs.Close()
+		if cLog != container.LogDriver {
+			// Since the logger isn't cached in the container, which occurs if it is running, it
+			// must get explicitly closed here to avoid leaking it and any file handles it has.
+			if err := cLog.Close(); err != nil {
+				logrus.Errorf("Error closing logger: %v", err)
+			}
+		}
+	}, nil
+}
+
 func (daemon *Daemon) getLogger(container *container.Container) (logger.Logger, error) {
 	if container.LogDriver != nil && container.IsRunning() {
 		return container.LogDriver, nil
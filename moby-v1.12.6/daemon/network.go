@@ -13,6 +13,7 @@ import (
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
 	networktypes "github.com/docker/libnetwork/types"
+	"golang.org/x/net/context"
 )
 
 // NetworkControllerEnabled checks if the networking stack is enabled.
@@ -141,7 +142,7 @@ func (daemon *Daemon) SetupIngress(create clustertypes.NetworkCreateRequest, nod
 			}
 		}
 
-		if _, err := daemon.createNetwork(create.NetworkCreateRequest, create.ID, true); err != nil {
+		if _, err := daemon.createNetwork(context.Background(), create.NetworkCreateRequest, create.ID, true); err != nil {
 			// If it is any other error other than already
 			// exists error log error and return.
 			if _, ok := err.(libnetwork.NetworkNameError); !ok {
@@ -187,20 +188,20 @@ func (daemon *Daemon) SetNetworkBootstrapKeys(keys []*networktypes.EncryptionKey
 
 // CreateManagedNetwork creates an agent network.
 func (daemon *Daemon) CreateManagedNetwork(create clustertypes.NetworkCreateRequest) error {
-	_, err := daemon.createNetwork(create.NetworkCreateRequest, create.ID, true)
+	_, err := daemon.createNetwork(context.Background(), create.NetworkCreateRequest, create.ID, true)
 	return err
 }
 
 // CreateNetwork creates a network with the given name, driver and other optional parameters
-func (daemon *Daemon) CreateNetwork(create types.NetworkCreateRequest) (*types.NetworkCreateResponse, error) {
-	resp, err := daemon.createNetwork(create, "", false)
+func (daemon *Daemon) CreateNetwork(ctx context.Context, create types.NetworkCreateRequest) (*types.NetworkCreateResponse, error) {
+	resp, err := daemon.createNetwork(ctx, create, "", false)
 	if err != nil {
 		return nil, err
 	}
 	return resp, err
 }
 
-func (daemon *Daemon) createNetwork(create types.NetworkCreateRequest, id string, agent bool) (*types.NetworkCreateResponse, error) {
+func (daemon *Daemon) createNetwork(ctx context.Context, create types.NetworkCreateRequest, id string, agent bool) (*types.NetworkCreateResponse, error) {
 	// If there is a pending ingress network creation wait here
 	// since ingress network creation can happen via node download
 	// from manager or task download.
@@ -262,7 +263,7 @@ func (daemon *Daemon) createNetwork(create types.NetworkCreateRequest, id string
 		return nil, err
 	}
 
-	daemon.LogNetworkEvent(n, "create")
+	daemon.LogNetworkEventWithAttributes(n, "create", requestAttributes(ctx))
 	return &types.NetworkCreateResponse{
 		ID:      n.ID(),
 		Warning: warning,
@@ -291,6 +292,53 @@ func getIpamConfig(data []network.IPAMConfig) ([]*libnetwork.IpamConf, []*libnet
 	return ipamV4Cfg, ipamV6Cfg, nil
 }
 
+// UpdateNetwork changes the labels, the attachable flag, the driver options and
+// the address pools of a network, unless it's one of docker's predefined networks.
+func (daemon *Daemon) UpdateNetwork(ctx context.Context, networkID string, update types.NetworkUpdate) error {
+	nw, err := daemon.FindNetwork(networkID)
+	if err != nil {
+		return err
+	}
+
+	if runconfig.IsPreDefinedNetwork(nw.Name()) {
+		err := fmt.Errorf("%s is a pre-defined network and cannot be updated", nw.Name())
+		return errors.NewRequestForbiddenError(err)
+	}
+
+	v4Conf, v6Conf, err := getIpamConfig(update.IPAMConfig)
+	if err != nil {
+		return errors.NewBadRequestError(err)
+	}
+
+	updateOptions := []libnetwork.NetworkUpdateOption{
+		libnetwork.NetworkUpdateDriverOpts(update.Options),
+		libnetwork.NetworkUpdateIpam(v4Conf, v6Conf),
+	}
+	if update.Labels != nil {
+		updateOptions = append(updateOptions, libnetwork.NetworkUpdateLabels(update.Labels))
+	}
+	if update.Attachable != nil {
+		updateOptions = append(updateOptions, libnetwork.NetworkUpdateAttachable(*update.Attachable))
+	}
+	if len(update.AuxAddresses) > 0 || len(update.RemoveAuxAddresses) > 0 {
+		updateOptions = append(updateOptions, libnetwork.NetworkUpdateAuxAddresses(update.AuxAddresses, update.RemoveAuxAddresses))
+	}
+
+	if err := nw.Update(updateOptions...); err != nil {
+		switch err.(type) {
+		case networktypes.ForbiddenError:
+			return errors.NewRequestForbiddenError(err)
+		case networktypes.BadRequestError:
+			return errors.NewBadRequestError(err)
+		case networktypes.NotFoundError:
+			return errors.NewRequestNotFoundError(err)
+		}
+		return err
+	}
+	daemon.LogNetworkEventWithAttributes(nw, "update", requestAttributes(ctx))
+	return nil
+}
+
 // UpdateContainerServiceConfig updates a service configuration.
 func (daemon *Daemon) UpdateContainerServiceConfig(containerName string, serviceConfig *clustertypes.ServiceConfig) error {
 	container, err := daemon.GetContainer(containerName)
@@ -305,17 +353,17 @@ func (daemon *Daemon) UpdateContainerServiceConfig(containerName string, service
 // ConnectContainerToNetwork connects the given container to the given
 // network. If either cannot be found, an err is returned. If the
 // network cannot be set up, an err is returned.
-func (daemon *Daemon) ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error {
+func (daemon *Daemon) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, endpointConfig *network.EndpointSettings) error {
 	container, err := daemon.GetContainer(containerName)
 	if err != nil {
 		return err
 	}
-	return daemon.ConnectToNetwork(container, networkName, endpointConfig)
+	return daemon.ConnectToNetwork(ctx, container, networkName, endpointConfig)
 }
 
 // DisconnectContainerFromNetwork disconnects the given container from
 // the given network. If either cannot be found, an err is returned.
-func (daemon *Daemon) DisconnectContainerFromNetwork(containerName string, network libnetwork.Network, force bool) error {
+func (daemon *Daemon) DisconnectContainerFromNetwork(ctx context.Context, containerName string, network libnetwork.Network, force bool) error {
 	container, err := daemon.GetContainer(containerName)
 	if err != nil {
 		if force {
@@ -323,7 +371,7 @@ func (daemon *Daemon) DisconnectContainerFromNetwork(containerName string, netwo
 		}
 		return err
 	}
-	return daemon.DisconnectFromNetwork(container, network, force)
+	return daemon.DisconnectFromNetwork(ctx, container, network, force)
 }
 
 // GetNetworkDriverList returns the list of plugins drivers
@@ -349,15 +397,15 @@ func (daemon *Daemon) GetNetworkDriverList() map[string]bool {
 
 // DeleteManagedNetwork deletes an agent network.
 func (daemon *Daemon) DeleteManagedNetwork(networkID string) error {
-	return daemon.deleteNetwork(networkID, true)
+	return daemon.deleteNetwork(context.Background(), networkID, true)
 }
 
 // DeleteNetwork destroys a network unless it's one of docker's predefined networks.
-func (daemon *Daemon) DeleteNetwork(networkID string) error {
-	return daemon.deleteNetwork(networkID, false)
+func (daemon *Daemon) DeleteNetwork(ctx context.Context, networkID string) error {
+	return daemon.deleteNetwork(ctx, networkID, false)
 }
 
-func (daemon *Daemon) deleteNetwork(networkID string, dynamic bool) error {
+func (daemon *Daemon) deleteNetwork(ctx context.Context, networkID string, dynamic bool) error {
 	nw, err := daemon.FindNetwork(networkID)
 	if err != nil {
 		return err
@@ -371,7 +419,7 @@ func (daemon *Daemon) deleteNetwork(networkID string, dynamic bool) error {
 	if err := nw.Delete(); err != nil {
 		return err
 	}
-	daemon.LogNetworkEvent(nw, "destroy")
+	daemon.LogNetworkEventWithAttributes(nw, "destroy", requestAttributes(ctx))
 	return nil
 }
 
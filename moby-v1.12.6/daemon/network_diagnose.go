@@ -0,0 +1,398 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errors"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/libnetwork"
+	"github.com/docker/libnetwork/resolvconf"
+	networktypes "github.com/docker/libnetwork/types"
+)
+
+// probeTimeout is how long a connectivity probe waits for an answer.
+const probeTimeout = 3 * time.Second
+
+// DiagnoseNetwork reports the state of a network, or of the networking of a
+// container, as seen from the host and from the network namespaces of the
+// containers. For a container, it can also probe the connectivity to another
+// container or to an address.
+func (daemon *Daemon) DiagnoseNetwork(target string, options types.NetworkDiagnoseOptions) (*types.NetworkDiagnostics, error) {
+	if nw, err := daemon.FindNetwork(target); err == nil {
+		if options.Probe != "" {
+			err := fmt.Errorf("%s is a network, connectivity probes are sent from a container", target)
+			return nil, errors.NewBadRequestError(err)
+		}
+		return daemon.diagnoseNetwork(nw), nil
+	}
+
+	c, err := daemon.GetContainer(target)
+	if err != nil {
+		err := fmt.Errorf("No such network or container: %s", target)
+		return nil, errors.NewRequestNotFoundError(err)
+	}
+	return daemon.diagnoseContainer(c, options)
+}
+
+func (daemon *Daemon) diagnoseNetwork(nw libnetwork.Network) *types.NetworkDiagnostics {
+	nd := daemon.networkDiagnostic(nw)
+	diagnostics := &types.NetworkDiagnostics{
+		Networks: []types.NetworkDiagnostic{nd},
+	}
+
+	keys := append(nd.Subnets, nd.Interface)
+	for _, ep := range nw.Endpoints() {
+		sb := ep.Info().Sandbox()
+		if sb == nil {
+			continue
+		}
+		c, err := daemon.GetContainer(sb.ContainerID())
+		if err != nil {
+			continue
+		}
+		cd, err := daemon.containerNetworkDiagnostic(c, sb)
+		if err != nil {
+			logrus.Warnf("Failed to diagnose the networking of container %s: %v", c.ID, err)
+		}
+		diagnostics.Containers = append(diagnostics.Containers, cd)
+		keys = append(keys, endpointAddresses(cd)...)
+	}
+
+	diagnostics.Firewall = firewallRules(keys)
+	return diagnostics
+}
+
+func (daemon *Daemon) diagnoseContainer(c *container.Container, options types.NetworkDiagnoseOptions) (*types.NetworkDiagnostics, error) {
+	if !c.IsRunning() {
+		return nil, errors.NewRequestConflictError(errNotRunning{c.ID})
+	}
+
+	// Containers sharing the network namespace of another one are
+	// diagnosed through it.
+	nc := c
+	if c.HostConfig.NetworkMode.IsContainer() {
+		var err error
+		if nc, err = daemon.getNetworkedContainer(c.ID, c.HostConfig.NetworkMode.ConnectedContainer()); err != nil {
+			return nil, err
+		}
+	}
+
+	sb := daemon.getNetworkSandbox(nc)
+	if sb == nil {
+		return nil, fmt.Errorf("container %s has no network sandbox", c.ID)
+	}
+
+	cd, err := daemon.containerNetworkDiagnostic(nc, sb)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics := &types.NetworkDiagnostics{
+		Containers: []types.ContainerNetworkDiagnostic{cd},
+	}
+
+	keys := endpointAddresses(cd)
+	for name := range nc.NetworkSettings.Networks {
+		nw, err := daemon.FindNetwork(name)
+		if err != nil {
+			continue
+		}
+		nd := daemon.networkDiagnostic(nw)
+		diagnostics.Networks = append(diagnostics.Networks, nd)
+		keys = append(keys, nd.Subnets...)
+		keys = append(keys, nd.Interface)
+	}
+	diagnostics.Firewall = firewallRules(keys)
+
+	if options.Probe != "" {
+		probe, err := daemon.probeConnectivity(nc, sb, options.Probe, options.ProbePort)
+		if err != nil {
+			return nil, err
+		}
+		diagnostics.Probe = probe
+	}
+
+	return diagnostics, nil
+}
+
+func (daemon *Daemon) networkDiagnostic(nw libnetwork.Network) types.NetworkDiagnostic {
+	nd := types.NetworkDiagnostic{
+		Name:   nw.Name(),
+		ID:     nw.ID(),
+		Driver: nw.Type(),
+	}
+
+	var gateways []net.IP
+	v4Info, v6Info := nw.Info().IpamInfo()
+	for _, info := range append(v4Info, v6Info...) {
+		if info.Pool != nil {
+			nd.Subnets = append(nd.Subnets, info.Pool.String())
+		}
+		if info.Gateway != nil {
+			nd.Gateways = append(nd.Gateways, info.Gateway.IP.String())
+			gateways = append(gateways, info.Gateway.IP)
+		}
+	}
+	nd.Interface = hostInterface(gateways)
+	return nd
+}
+
+// containerNetworkDiagnostic describes the networking of the container
+// attached to the sandbox.
+func (daemon *Daemon) containerNetworkDiagnostic(c *container.Container, sb libnetwork.Sandbox) (types.ContainerNetworkDiagnostic, error) {
+	cd := types.ContainerNetworkDiagnostic{
+		ID:         c.ID,
+		Name:       strings.TrimPrefix(c.Name, "/"),
+		SandboxKey: sb.Key(),
+		Ports:      publishedPorts(c),
+	}
+
+	for _, ep := range sb.Endpoints() {
+		ed := types.EndpointDiagnostic{
+			Network:    ep.Network(),
+			EndpointID: ep.ID(),
+		}
+		info := ep.Info()
+		if iface := info.Iface(); iface != nil {
+			if mac := iface.MacAddress(); mac != nil {
+				ed.MacAddress = mac.String()
+			}
+			if addr := iface.Address(); addr != nil {
+				ed.IPv4Address = addr.String()
+			}
+			if addr := iface.AddressIPv6(); addr != nil {
+				ed.IPv6Address = addr.String()
+			}
+		}
+		if gw := info.Gateway(); gw != nil {
+			ed.Gateway = gw.String()
+		}
+		if gw := info.GatewayIPv6(); gw != nil {
+			ed.IPv6Gateway = gw.String()
+		}
+		if es, ok := c.NetworkSettings.Networks[ep.Network()]; ok {
+			ed.Aliases = es.Aliases
+		}
+		cd.Endpoints = append(cd.Endpoints, ed)
+	}
+
+	if rc, err := resolvconf.GetSpecific(c.ResolvConfPath); err == nil {
+		cd.Nameservers = resolvconf.GetNameservers(rc.Content, networktypes.IP)
+		cd.Search = resolvconf.GetSearchDomains(rc.Content)
+		cd.DNSOptions = resolvconf.GetOptions(rc.Content)
+	}
+	cd.EmbeddedDNS, cd.ExternalDNS = sb.EmbeddedDNS()
+	if cd.EmbeddedDNS {
+		cd.DNSRecords = daemon.dnsRecords(c, sb)
+	}
+	cd.Hosts = readHostsEntries(c.HostsPath)
+
+	if err := namespaceDiagnostic(sb.Key(), &cd); err != nil {
+		return cd, fmt.Errorf("failed to inspect the network namespace %s: %v", sb.Key(), err)
+	}
+	return cd, nil
+}
+
+// dnsRecords returns the names of the containers sharing a network with the
+// container, as resolved by the embedded DNS server of its sandbox.
+func (daemon *Daemon) dnsRecords(c *container.Container, sb libnetwork.Sandbox) []types.DNSRecordDiagnostic {
+	names := make(map[string]bool)
+	for name := range c.NetworkSettings.Networks {
+		nw, err := daemon.FindNetwork(name)
+		if err != nil {
+			continue
+		}
+		for _, ep := range nw.Endpoints() {
+			names[ep.Name()] = true
+			if peerSb := ep.Info().Sandbox(); peerSb != nil {
+				if peer, err := daemon.GetContainer(peerSb.ContainerID()); err == nil {
+					if es, ok := peer.NetworkSettings.Networks[name]; ok {
+						for _, alias := range es.Aliases {
+							names[alias] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var records []types.DNSRecordDiagnostic
+	for _, name := range sortedNames(names) {
+		ips, _ := sb.ResolveName(name, networktypes.IP)
+		if len(ips) == 0 {
+			continue
+		}
+		record := types.DNSRecordDiagnostic{Name: name}
+		for _, ip := range ips {
+			record.Addresses = append(record.Addresses, ip.String())
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// probeConnectivity probes the connectivity from the container to the
+// target, either a container or an address. A container is probed on the
+// address it has in a network it shares with the source container.
+func (daemon *Daemon) probeConnectivity(c *container.Container, sb libnetwork.Sandbox, target string, port int) (*types.NetworkProbe, error) {
+	probe := &types.NetworkProbe{
+		Source:   strings.TrimPrefix(c.Name, "/"),
+		Target:   target,
+		Protocol: "icmp",
+	}
+	if port != 0 {
+		probe.Protocol = "tcp"
+	}
+
+	ip := net.ParseIP(target)
+	if ip == nil {
+		tc, err := daemon.GetContainer(target)
+		if err != nil {
+			err := fmt.Errorf("No such container or invalid address to probe: %s", target)
+			return nil, errors.NewRequestNotFoundError(err)
+		}
+		if ip = probeAddress(c, tc); ip == nil {
+			err := fmt.Errorf("container %s has no address in a network shared with container %s", target, probe.Source)
+			return nil, errors.NewBadRequestError(err)
+		}
+	}
+	probe.Address = ip.String()
+	if port != 0 {
+		probe.Address = net.JoinHostPort(ip.String(), fmt.Sprintf("%d", port))
+	}
+
+	rtt, result, err := probeFromNamespace(sb.Key(), ip, port, probeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	probe.Result = result
+	if rtt > 0 {
+		probe.Reachable = true
+		probe.RTT = rtt.String()
+	}
+	return probe, nil
+}
+
+// probeAddress returns the address of the target container in a network it
+// shares with the container.
+func probeAddress(c, target *container.Container) net.IP {
+	for name, es := range c.NetworkSettings.Networks {
+		tes, ok := target.NetworkSettings.Networks[name]
+		if !ok || es == nil || tes == nil {
+			continue
+		}
+		if ip := net.ParseIP(tes.IPAddress); ip != nil {
+			return ip
+		}
+		if ip := net.ParseIP(tes.GlobalIPv6Address); ip != nil {
+			return ip
+		}
+	}
+	return nil
+}
+
+// publishedPorts returns the ports of the container published on the host.
+func publishedPorts(c *container.Container) []string {
+	var ports []string
+	if c.NetworkSettings == nil {
+		return ports
+	}
+	for port, bindings := range c.NetworkSettings.Ports {
+		if len(bindings) == 0 {
+			ports = append(ports, string(port))
+			continue
+		}
+		for _, b := range bindings {
+			ports = append(ports, fmt.Sprintf("%s -> %s", port, net.JoinHostPort(b.HostIP, b.HostPort)))
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+func readHostsEntries(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, strings.Join(strings.Fields(line), " "))
+	}
+	return entries
+}
+
+// endpointAddresses returns the addresses of the endpoints of the container,
+// without their prefix length.
+func endpointAddresses(cd types.ContainerNetworkDiagnostic) []string {
+	var addrs []string
+	for _, ed := range cd.Endpoints {
+		for _, addr := range []string{ed.IPv4Address, ed.IPv6Address} {
+			if ip, _, err := net.ParseCIDR(addr); err == nil {
+				addrs = append(addrs, ip.String())
+			}
+		}
+	}
+	return addrs
+}
+
+// relevantRules returns the firewall rules mentioning one of the keys, an
+// address, a subnet or an interface name. A key matches a whole argument of
+// the rule, or its address part.
+func relevantRules(rules []string, keys []string) []string {
+	wanted := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k != "" {
+			wanted[k] = true
+		}
+	}
+
+	var relevant []string
+	for _, rule := range rules {
+		for _, field := range strings.Fields(rule) {
+			if wanted[field] || wanted[ruleAddress(field)] {
+				relevant = append(relevant, rule)
+				break
+			}
+		}
+	}
+	return relevant
+}
+
+// ruleAddress strips the prefix length of a host address, or the port of a
+// NAT destination, from the argument of a firewall rule.
+func ruleAddress(field string) string {
+	if ip, ipnet, err := net.ParseCIDR(field); err == nil {
+		if ones, bits := ipnet.Mask.Size(); ones == bits {
+			return ip.String()
+		}
+		return field
+	}
+	if host, _, err := net.SplitHostPort(field); err == nil {
+		return host
+	}
+	return field
+}
+
+func sortedNames(m map[string]bool) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
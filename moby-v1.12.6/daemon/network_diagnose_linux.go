@@ -0,0 +1,293 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/errors"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/libnetwork/iptables"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+const (
+	icmpEchoReply       = 0
+	icmpDestUnreachable = 3
+	icmpEcho            = 8
+)
+
+// namespaceDiagnostic fills the interfaces and routes of the network
+// namespace at the given path.
+func namespaceDiagnostic(key string, cd *types.ContainerNetworkDiagnostic) error {
+	ns, err := netns.GetFromPath(key)
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	nlh, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return err
+	}
+	defer nlh.Delete()
+
+	links, err := nlh.LinkList()
+	if err != nil {
+		return err
+	}
+	names := make(map[int]string, len(links))
+	for _, link := range links {
+		attrs := link.Attrs()
+		names[attrs.Index] = attrs.Name
+
+		id := types.InterfaceDiagnostic{
+			Name: attrs.Name,
+			MTU:  attrs.MTU,
+			Up:   attrs.Flags&net.FlagUp != 0,
+		}
+		if attrs.HardwareAddr != nil {
+			id.MacAddress = attrs.HardwareAddr.String()
+		}
+		addrs, err := nlh.AddrList(link, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			id.Addresses = append(id.Addresses, addr.IPNet.String())
+		}
+		cd.Interfaces = append(cd.Interfaces, id)
+	}
+
+	routes, err := nlh.RouteList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		cd.Routes = append(cd.Routes, formatRoute(r, names))
+	}
+	return nil
+}
+
+// formatRoute formats a route the way "ip route" does.
+func formatRoute(r netlink.Route, names map[int]string) string {
+	dst := "default"
+	if r.Dst != nil {
+		dst = r.Dst.String()
+	}
+	parts := []string{dst}
+	if r.Gw != nil {
+		parts = append(parts, "via", r.Gw.String())
+	}
+	if name, ok := names[r.LinkIndex]; ok {
+		parts = append(parts, "dev", name)
+	}
+	if r.Src != nil {
+		parts = append(parts, "src", r.Src.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// hostInterface returns the name of the host interface holding one of the
+// gateway addresses, the bridge of a bridge network.
+func hostInterface(gateways []net.IP) string {
+	if len(gateways) == 0 {
+		return ""
+	}
+	links, err := netlink.LinkList()
+	if err != nil {
+		return ""
+	}
+	for _, link := range links {
+		addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			for _, gw := range gateways {
+				if addr.IP.Equal(gw) {
+					return link.Attrs().Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// firewallRules returns the rules of the nat and filter tables relevant to
+// the keys.
+func firewallRules(keys []string) []string {
+	var rules []string
+	for _, table := range []string{"nat", "filter"} {
+		out, err := iptables.Raw("-t", table, "-S")
+		if err != nil {
+			logrus.Debugf("Failed to list the rules of the %s table: %v", table, err)
+			continue
+		}
+		for _, rule := range relevantRules(strings.Split(string(out), "\n"), keys) {
+			rules = append(rules, fmt.Sprintf("-t %s %s", table, rule))
+		}
+	}
+	return rules
+}
+
+// probeFromNamespace probes the address from the network namespace at the
+// given path, with a TCP connection when a port is given and an ICMP echo
+// request otherwise. It returns the round-trip time, which is zero when the
+// address could not be reached, and a description of the outcome.
+func probeFromNamespace(key string, ip net.IP, port int, timeout time.Duration) (time.Duration, string, error) {
+	if port == 0 && ip.To4() == nil {
+		err := fmt.Errorf("ICMP probes are limited to IPv4 addresses, probe %s on a TCP port instead", ip)
+		return 0, "", errors.NewBadRequestError(err)
+	}
+
+	runtime.LockOSThread()
+	origns, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return 0, "", err
+	}
+	defer origns.Close()
+
+	ns, err := netns.GetFromPath(key)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return 0, "", err
+	}
+	defer ns.Close()
+
+	if err := netns.Set(ns); err != nil {
+		runtime.UnlockOSThread()
+		return 0, "", err
+	}
+	defer func() {
+		// A thread which could not be moved back to the namespace of the
+		// daemon stays locked, so that it is not reused.
+		if err := netns.Set(origns); err != nil {
+			logrus.Errorf("Failed to restore the network namespace after a probe: %v", err)
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+
+	if port != 0 {
+		return probeTCP(net.JoinHostPort(ip.String(), fmt.Sprintf("%d", port)), timeout)
+	}
+	return probeICMP(ip, timeout)
+}
+
+func probeTCP(addr string, timeout time.Duration) (time.Duration, string, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	rtt := time.Since(start)
+	if err == nil {
+		conn.Close()
+		return rtt, "connected", nil
+	}
+	// A refused connection still proves that the address is reachable.
+	if opErr, ok := err.(*net.OpError); ok {
+		if sysErr, ok := opErr.Err.(*os.SyscallError); ok && sysErr.Err == syscall.ECONNREFUSED {
+			return rtt, "connection refused", nil
+		}
+		if opErr.Timeout() {
+			return 0, "timed out", nil
+		}
+	}
+	return 0, err.Error(), nil
+}
+
+func probeICMP(ip net.IP, timeout time.Duration) (time.Duration, string, error) {
+	conn, err := net.DialTimeout("ip4:icmp", ip.String(), timeout)
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+
+	id := uint16(os.Getpid() & 0xffff)
+	seq := uint16(time.Now().UnixNano() & 0xffff)
+	req := make([]byte, 8, 16)
+	req[0] = icmpEcho
+	binary.BigEndian.PutUint16(req[4:], id)
+	binary.BigEndian.PutUint16(req[6:], seq)
+	req = append(req, "diagnose"...)
+	binary.BigEndian.PutUint16(req[2:], icmpChecksum(req))
+
+	start := time.Now()
+	if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+		return 0, "", err
+	}
+	if _, err := conn.Write(req); err != nil {
+		return 0, err.Error(), nil
+	}
+
+	// ReadFrom strips the IPv4 header of the replies.
+	ipConn := conn.(*net.IPConn)
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := ipConn.ReadFrom(buf)
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				return 0, "timed out", nil
+			}
+			return 0, err.Error(), nil
+		}
+		switch icmpReplyType(buf[:n], id, seq) {
+		case icmpEchoReply:
+			return time.Since(start), "echo reply", nil
+		case icmpDestUnreachable:
+			return 0, "destination unreachable", nil
+		}
+	}
+}
+
+// icmpReplyType returns the type of the ICMP message b if it answers the
+// echo request with the given id and sequence number, -1 otherwise. A
+// destination unreachable message carries the IPv4 header and the first
+// bytes of the datagram it refers to, which must be the echo request.
+func icmpReplyType(b []byte, id, seq uint16) int {
+	if len(b) < 8 {
+		return -1
+	}
+	switch b[0] {
+	case icmpEchoReply:
+		if binary.BigEndian.Uint16(b[4:]) == id && binary.BigEndian.Uint16(b[6:]) == seq {
+			return icmpEchoReply
+		}
+	case icmpDestUnreachable:
+		orig := b[8:]
+		if len(orig) < 20 || orig[0]>>4 != 4 || orig[9] != syscall.IPPROTO_ICMP {
+			return -1
+		}
+		hl := int(orig[0]&0x0f) * 4
+		if hl < 20 || len(orig) < hl+8 {
+			return -1
+		}
+		echo := orig[hl:]
+		if echo[0] == icmpEcho && binary.BigEndian.Uint16(echo[4:]) == id && binary.BigEndian.Uint16(echo[6:]) == seq {
+			return icmpDestUnreachable
+		}
+	}
+	return -1
+}
+
+// icmpChecksum computes the Internet checksum of an ICMP message.
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
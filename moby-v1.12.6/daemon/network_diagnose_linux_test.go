@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+)
+
+func TestFormatRoute(t *testing.T) {
+	_, dst, _ := net.ParseCIDR("172.17.0.0/16")
+	names := map[int]string{1: "lo", 12: "eth0"}
+
+	cases := []struct {
+		route    netlink.Route
+		expected string
+	}{
+		{netlink.Route{}, "default"},
+		{netlink.Route{Gw: net.ParseIP("172.17.0.1"), LinkIndex: 12}, "default via 172.17.0.1 dev eth0"},
+		{netlink.Route{Dst: dst, LinkIndex: 12, Src: net.ParseIP("172.17.0.2")}, "172.17.0.0/16 dev eth0 src 172.17.0.2"},
+		{netlink.Route{Dst: dst, LinkIndex: 3}, "172.17.0.0/16"},
+	}
+
+	for _, c := range cases {
+		if got := formatRoute(c.route, names); got != c.expected {
+			t.Fatalf("Expected %q, got %q", c.expected, got)
+		}
+	}
+}
+
+func TestICMPReplyType(t *testing.T) {
+	icmp := func(typ uint8, id, seq uint16, payload []byte) []byte {
+		b := make([]byte, 8, 8+len(payload))
+		b[0] = typ
+		binary.BigEndian.PutUint16(b[4:], id)
+		binary.BigEndian.PutUint16(b[6:], seq)
+		return append(b, payload...)
+	}
+	// unreachable embeds the IPv4 header and the first bytes of the
+	// datagram that could not be delivered.
+	unreachable := func(proto uint8, orig []byte) []byte {
+		hdr := make([]byte, 20)
+		hdr[0] = 0x45
+		hdr[9] = proto
+		return icmp(icmpDestUnreachable, 0, 0, append(hdr, orig...))
+	}
+
+	cases := []struct {
+		msg      []byte
+		expected int
+	}{
+		{nil, -1},
+		{[]byte{icmpEchoReply, 0, 0}, -1},
+		{icmp(icmpEchoReply, 1, 2, nil), icmpEchoReply},
+		{icmp(icmpEchoReply, 1, 3, nil), -1},
+		{icmp(icmpEchoReply, 4, 2, nil), -1},
+		{icmp(icmpEcho, 1, 2, nil), -1},
+		{unreachable(syscall.IPPROTO_ICMP, icmp(icmpEcho, 1, 2, nil)), icmpDestUnreachable},
+		{unreachable(syscall.IPPROTO_ICMP, icmp(icmpEcho, 1, 3, nil)), -1},
+		{unreachable(syscall.IPPROTO_ICMP, icmp(icmpEchoReply, 1, 2, nil)), -1},
+		{unreachable(syscall.IPPROTO_UDP, icmp(icmpEcho, 1, 2, nil)), -1},
+		{unreachable(syscall.IPPROTO_ICMP, icmp(icmpEcho, 1, 2, nil)[:4]), -1},
+		{icmp(icmpDestUnreachable, 0, 0, nil), -1},
+	}
+
+	for i, c := range cases {
+		if got := icmpReplyType(c.msg, 1, 2); got != c.expected {
+			t.Fatalf("Case %d: expected %d, got %d", i, c.expected, got)
+		}
+	}
+}
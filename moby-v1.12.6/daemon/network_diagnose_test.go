@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/network"
+	networktypes "github.com/docker/engine-api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+func TestRelevantRules(t *testing.T) {
+	rules := []string{
+		"-P FORWARD ACCEPT",
+		"-A FORWARD -o docker0 -j DOCKER",
+		"-A POSTROUTING -s 172.17.0.0/16 ! -o docker0 -j MASQUERADE",
+		"-A DOCKER -d 172.17.0.2/32 ! -i docker0 -o docker0 -p tcp -m tcp --dport 80 -j ACCEPT",
+		"-A DOCKER ! -i docker0 -p tcp -m tcp --dport 8080 -j DNAT --to-destination 172.17.0.2:80",
+		"-A DOCKER ! -i br-1234 -p tcp -m tcp --dport 8081 -j DNAT --to-destination 172.18.0.2:80",
+		"-A DOCKER -d 172.17.0.22/32 -j ACCEPT",
+		"",
+	}
+
+	cases := []struct {
+		keys     []string
+		expected []string
+	}{
+		{nil, nil},
+		{[]string{""}, nil},
+		{[]string{"docker0"}, []string{rules[1], rules[2], rules[3], rules[4]}},
+		{[]string{"172.17.0.0/16"}, []string{rules[2]}},
+		{[]string{"172.17.0.2"}, []string{rules[3], rules[4]}},
+		{[]string{"172.18.0.2", "br-1234"}, []string{rules[5]}},
+	}
+
+	for _, c := range cases {
+		if got := relevantRules(rules, c.keys); !reflect.DeepEqual(got, c.expected) {
+			t.Fatalf("Expected %v for keys %v, got %v", c.expected, c.keys, got)
+		}
+	}
+}
+
+func TestRuleAddress(t *testing.T) {
+	cases := []struct {
+		field    string
+		expected string
+	}{
+		{"172.17.0.2/32", "172.17.0.2"},
+		{"fd00::2/128", "fd00::2"},
+		{"172.17.0.0/16", "172.17.0.0/16"},
+		{"172.17.0.2:80", "172.17.0.2"},
+		{"[fd00::2]:80", "fd00::2"},
+		{"docker0", "docker0"},
+		{"-j", "-j"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		if got := ruleAddress(c.field); got != c.expected {
+			t.Fatalf("Expected %q for %q, got %q", c.expected, c.field, got)
+		}
+	}
+}
+
+func TestProbeAddress(t *testing.T) {
+	withNetworks := func(networks map[string]*networktypes.EndpointSettings) *container.Container {
+		return &container.Container{NetworkSettings: &network.Settings{Networks: networks}}
+	}
+	c := withNetworks(map[string]*networktypes.EndpointSettings{
+		"front": {IPAddress: "172.18.0.2"},
+		"back":  {IPAddress: "172.19.0.2"},
+	})
+
+	cases := []struct {
+		target   *container.Container
+		expected net.IP
+	}{
+		{withNetworks(nil), nil},
+		{withNetworks(map[string]*networktypes.EndpointSettings{
+			"other": {IPAddress: "172.20.0.3"},
+		}), nil},
+		{withNetworks(map[string]*networktypes.EndpointSettings{
+			"other": {IPAddress: "172.20.0.3"},
+			"back":  {IPAddress: "172.19.0.3"},
+		}), net.ParseIP("172.19.0.3")},
+		{withNetworks(map[string]*networktypes.EndpointSettings{
+			"front": {GlobalIPv6Address: "fd00::3"},
+		}), net.ParseIP("fd00::3")},
+		{withNetworks(map[string]*networktypes.EndpointSettings{
+			"front": {},
+		}), nil},
+		{withNetworks(map[string]*networktypes.EndpointSettings{
+			"front": nil,
+		}), nil},
+	}
+
+	for i, tc := range cases {
+		if got := probeAddress(c, tc.target); !got.Equal(tc.expected) {
+			t.Fatalf("Case %d: expected %v, got %v", i, tc.expected, got)
+		}
+	}
+}
+
+func TestPublishedPorts(t *testing.T) {
+	cases := []struct {
+		ports    nat.PortMap
+		expected []string
+	}{
+		{nil, nil},
+		{nat.PortMap{"80/tcp": nil}, []string{"80/tcp"}},
+		{nat.PortMap{
+			"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "8080"}, {HostIP: "::", HostPort: "8080"}},
+			"53/udp":  {{HostIP: "127.0.0.1", HostPort: "5353"}},
+			"443/tcp": nil,
+		}, []string{"443/tcp", "53/udp -> 127.0.0.1:5353", "80/tcp -> 0.0.0.0:8080", "80/tcp -> [::]:8080"}},
+	}
+
+	for _, c := range cases {
+		ctr := &container.Container{NetworkSettings: &network.Settings{Ports: c.ports}}
+		if got := publishedPorts(ctr); !reflect.DeepEqual(got, c.expected) {
+			t.Fatalf("Expected %v for %v, got %v", c.expected, c.ports, got)
+		}
+	}
+
+	if got := publishedPorts(&container.Container{}); got != nil {
+		t.Fatalf("Expected no ports without network settings, got %v", got)
+	}
+}
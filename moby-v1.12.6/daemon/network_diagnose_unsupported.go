@@ -0,0 +1,27 @@
+// +build !linux
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/docker/engine-api/types"
+)
+
+func namespaceDiagnostic(key string, cd *types.ContainerNetworkDiagnostic) error {
+	return nil
+}
+
+func hostInterface(gateways []net.IP) string {
+	return ""
+}
+
+func firewallRules(keys []string) []string {
+	return nil
+}
+
+func probeFromNamespace(key string, ip net.IP, port int, timeout time.Duration) (time.Duration, string, error) {
+	return 0, "", fmt.Errorf("connectivity probes are not supported on this platform")
+}
@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"fmt"
+	"math"
+
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+const (
+	// minNetworkBurst is the smallest burst given to a rate limit, enough
+	// to let a few full-sized frames through at once.
+	minNetworkBurst = 32 * 1024
+	// maxNetworkRate is the highest rate the kernel accepts for a token
+	// bucket filter, in bytes per second.
+	maxNetworkRate = math.MaxUint32
+)
+
+// verifyNetworkLimits checks the network rate limits of the container. A
+// rate of -1 removes the limit when the container is updated.
+func verifyNetworkLimits(hostConfig *containertypes.HostConfig, update bool) error {
+	resources := hostConfig.Resources
+	limits := []struct {
+		direction   string
+		rate, burst int64
+	}{
+		{"ingress", resources.NetworkIngressRate, resources.NetworkIngressBurst},
+		{"egress", resources.NetworkEgressRate, resources.NetworkEgressBurst},
+	}
+
+	limited := false
+	for _, l := range limits {
+		if l.rate < -1 || l.rate > maxNetworkRate {
+			return fmt.Errorf("Invalid network %s rate %d: must be between 1 and %d bytes per second, or -1 for unlimited", l.direction, l.rate, int64(maxNetworkRate))
+		}
+		if l.burst < 0 || l.burst > math.MaxUint32 {
+			return fmt.Errorf("Invalid network %s burst %d: must be between 1 and %d bytes", l.direction, l.burst, int64(math.MaxUint32))
+		}
+		// A running container keeps its rate when only the burst is updated.
+		if !update && l.burst != 0 && l.rate <= 0 {
+			return fmt.Errorf("Network %s burst can only be set with a network %s rate", l.direction, l.direction)
+		}
+		if l.rate > 0 || l.burst > 0 {
+			limited = true
+		}
+	}
+
+	if limited && (hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsContainer()) {
+		return fmt.Errorf("Network rate limits cannot be set on a container sharing the network stack of the host or of another container")
+	}
+	return nil
+}
+
+// networkBurst returns the burst of a rate limit, defaulting to the traffic
+// of a tenth of a second at the given rate.
+func networkBurst(rate, burst int64) int64 {
+	if burst > 0 {
+		return burst
+	}
+	if burst = rate / 10; burst < minNetworkBurst {
+		burst = minNetworkBurst
+	}
+	return burst
+}
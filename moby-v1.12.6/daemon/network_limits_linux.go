@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"syscall"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/libnetwork"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// networkLimitLatency is how long, in seconds, a packet can wait in the
+// queue of a rate limit before being dropped.
+const networkLimitLatency = 0.05
+
+// applyNetworkLimits sets the network rate limits of a container on its
+// endpoints of bridge networks. Both limits are set on the host side of the
+// veth pair of the endpoint, out of reach of the container: the traffic it
+// receives is shaped as the host transmits it, and the traffic it sends is
+// policed as the host receives it.
+func (daemon *Daemon) applyNetworkLimits(c *container.Container) error {
+	resources := c.HostConfig.Resources
+	if daemon.netController == nil || (resources.NetworkIngressRate == 0 && resources.NetworkEgressRate == 0) {
+		return nil
+	}
+	sb := daemon.getNetworkSandbox(c)
+	if sb == nil || sb.Key() == "" {
+		return nil
+	}
+
+	var endpoints []libnetwork.Endpoint
+	for _, ep := range sb.Endpoints() {
+		n, err := daemon.FindNetwork(ep.Network())
+		if err != nil || n.Type() != "bridge" {
+			continue
+		}
+		if ep.Info() != nil && ep.Info().Iface() != nil && ep.Info().Iface().MacAddress() != nil {
+			endpoints = append(endpoints, ep)
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	ns, err := netns.GetFromPath(sb.Key())
+	if err != nil {
+		return err
+	}
+	defer ns.Close()
+
+	nlh, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return err
+	}
+	defer nlh.Delete()
+
+	hostNlh, err := netlink.NewHandle()
+	if err != nil {
+		return err
+	}
+	defer hostNlh.Delete()
+
+	links, err := nlh.LinkList()
+	if err != nil {
+		return err
+	}
+
+	for _, ep := range endpoints {
+		mac := ep.Info().Iface().MacAddress()
+		var link netlink.Link
+		for _, l := range links {
+			if l.Type() == "veth" && bytes.Equal(l.Attrs().HardwareAddr, mac) {
+				link = l
+				break
+			}
+		}
+		if link == nil {
+			return fmt.Errorf("could not find the interface of endpoint %s in the sandbox of container %s", ep.Name(), c.ID)
+		}
+
+		peer, err := hostNlh.LinkByIndex(link.Attrs().ParentIndex)
+		if err != nil || peer.Type() != "veth" {
+			return fmt.Errorf("could not find the host interface of endpoint %s", ep.Name())
+		}
+		if err := setRateLimit(hostNlh, peer, resources.NetworkIngressRate, resources.NetworkIngressBurst); err != nil {
+			return fmt.Errorf("failed to set the ingress rate limit on endpoint %s: %v", ep.Name(), err)
+		}
+		if err := setPolicing(hostNlh, peer, resources.NetworkEgressRate, resources.NetworkEgressBurst); err != nil {
+			return fmt.Errorf("failed to set the egress rate limit on endpoint %s: %v", ep.Name(), err)
+		}
+	}
+	return nil
+}
+
+// setPolicing replaces the ingress queueing discipline of the link with one
+// dropping the received traffic exceeding the rate, or removes the ingress
+// queueing discipline set earlier if the rate is not positive.
+func setPolicing(nlh *netlink.Handle, link netlink.Link, rate, burst int64) error {
+	qdiscs, err := nlh.QdiscList(link)
+	if err != nil {
+		return err
+	}
+	for _, q := range qdiscs {
+		if _, ok := q.(*netlink.Ingress); ok {
+			if err := nlh.QdiscDel(q); err != nil {
+				return err
+			}
+		}
+	}
+	if rate <= 0 {
+		return nil
+	}
+
+	ingress := &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+	if err := nlh.QdiscAdd(ingress); err != nil {
+		return err
+	}
+	err = nlh.FilterAdd(&netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    ingress.Handle,
+			Priority:  1,
+			Protocol:  syscall.ETH_P_ALL,
+		},
+		Actions: []netlink.Action{&netlink.PoliceAction{
+			Rate:         uint32(rate),
+			Burst:        uint32(networkBurst(rate, burst)),
+			ExceedAction: netlink.TC_POLICE_SHOT,
+		}},
+	})
+	if err != nil {
+		nlh.QdiscDel(ingress)
+	}
+	return err
+}
+
+// setRateLimit replaces the root queueing discipline of the link with a
+// token bucket filter shaping its transmitted traffic to the rate, or
+// removes the token bucket filter set earlier if the rate is not positive.
+func setRateLimit(nlh *netlink.Handle, link netlink.Link, rate, burst int64) error {
+	if rate <= 0 {
+		qdiscs, err := nlh.QdiscList(link)
+		if err != nil {
+			return err
+		}
+		for _, q := range qdiscs {
+			if _, ok := q.(*netlink.Tbf); ok && q.Attrs().Parent == netlink.HANDLE_ROOT {
+				return nlh.QdiscDel(q)
+			}
+		}
+		return nil
+	}
+
+	burst = networkBurst(rate, burst)
+	limit := burst + int64(float64(rate)*networkLimitLatency)
+	if limit > maxNetworkRate {
+		limit = maxNetworkRate
+	}
+	return nlh.QdiscReplace(&netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   uint64(rate),
+		Limit:  uint32(limit),
+		Buffer: uint32(netlink.Xmittime(uint64(rate), uint32(burst))),
+	})
+}
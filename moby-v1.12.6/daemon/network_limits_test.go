@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+func TestVerifyNetworkLimits(t *testing.T) {
+	cases := []struct {
+		resources   containertypes.Resources
+		networkMode string
+		update      bool
+		valid       bool
+	}{
+		{containertypes.Resources{}, "host", false, true},
+		{containertypes.Resources{NetworkIngressRate: 1024, NetworkIngressBurst: 4096}, "bridge", false, true},
+		{containertypes.Resources{NetworkEgressRate: -1}, "", true, true},
+		{containertypes.Resources{NetworkEgressRate: -2}, "", true, false},
+		{containertypes.Resources{NetworkEgressRate: 1 << 32}, "bridge", false, false},
+		{containertypes.Resources{NetworkEgressBurst: -1}, "bridge", false, false},
+		{containertypes.Resources{NetworkEgressBurst: 4096}, "bridge", false, false},
+		{containertypes.Resources{NetworkEgressBurst: 4096}, "", true, true},
+		{containertypes.Resources{NetworkIngressRate: 1024}, "host", false, false},
+		{containertypes.Resources{NetworkIngressRate: 1024}, "container:web", false, false},
+	}
+
+	for i, c := range cases {
+		hostConfig := &containertypes.HostConfig{
+			NetworkMode: containertypes.NetworkMode(c.networkMode),
+			Resources:   c.resources,
+		}
+		err := verifyNetworkLimits(hostConfig, c.update)
+		if c.valid && err != nil {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestNetworkBurst(t *testing.T) {
+	cases := []struct {
+		rate, burst, expected int64
+	}{
+		{1024, 0, minNetworkBurst},
+		{10 * 1024 * 1024, 0, 1024 * 1024},
+		{10 * 1024 * 1024, 4096, 4096},
+	}
+	for _, c := range cases {
+		if got := networkBurst(c.rate, c.burst); got != c.expected {
+			t.Fatalf("Expected a burst of %d for a rate of %d and a burst of %d, got %d", c.expected, c.rate, c.burst, got)
+		}
+	}
+}
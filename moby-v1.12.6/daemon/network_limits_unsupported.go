@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon
+
+import "github.com/docker/docker/container"
+
+func (daemon *Daemon) applyNetworkLimits(c *container.Container) error {
+	return nil
+}
@@ -244,7 +244,7 @@ func setNamespaces(daemon *Daemon, s *specs.Spec, c *container.Container) error
 	userNS := false
 	// user
 	if c.HostConfig.UsernsMode.IsPrivate() {
-		uidMap, gidMap := daemon.GetUIDGIDMaps()
+		uidMap, gidMap := daemon.containerIDMaps(c)
 		if uidMap != nil {
 			userNS = true
 			ns := specs.Namespace{Type: "user"}
@@ -480,10 +480,9 @@ func setMounts(daemon *Daemon, s *specs.Spec, c *container.Container, mounts []c
 		}
 
 		if m.Source == "tmpfs" {
-			data := c.HostConfig.Tmpfs[m.Destination]
 			options := []string{"noexec", "nosuid", "nodev", volume.DefaultPropagationMode}
-			if data != "" {
-				options = append(options, strings.Split(data, ",")...)
+			if m.Data != "" {
+				options = append(options, strings.Split(m.Data, ",")...)
 			}
 
 			merged, err := mount.MergeTmpfsOptions(options)
@@ -564,7 +563,7 @@ func setMounts(daemon *Daemon, s *specs.Spec, c *container.Container, mounts []c
 
 	// TODO: until a kernel/mount solution exists for handling remount in a user namespace,
 	// we must clear the readonly flag for the cgroups mount (@mrunalp concurs)
-	if uidMap, _ := daemon.GetUIDGIDMaps(); uidMap != nil || c.HostConfig.Privileged {
+	if uidMap, _ := daemon.containerIDMaps(c); uidMap != nil || c.HostConfig.Privileged {
 		for i, m := range s.Mounts {
 			if m.Type == "cgroup" {
 				clearReadOnly(&s.Mounts[i])
@@ -584,7 +583,12 @@ func (daemon *Daemon) populateCommonSpec(s *specs.Spec, c *container.Container)
 		Path:     c.BaseFS,
 		Readonly: c.HostConfig.ReadonlyRootfs,
 	}
-	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	if len(c.UIDMaps) > 0 {
+		if err := daemon.setupTraversablePaths(c); err != nil {
+			return err
+		}
+	}
+	rootUID, rootGID := daemon.containerRemappedUIDGID(c)
 	if err := c.SetupWorkingDirectory(rootUID, rootGID); err != nil {
 		return err
 	}
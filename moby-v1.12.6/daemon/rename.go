@@ -6,12 +6,13 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/libnetwork"
+	"golang.org/x/net/context"
 )
 
 // ContainerRename changes the name of a container, using the oldName
 // to find the container. An error is returned if newName is already
 // reserved.
-func (daemon *Daemon) ContainerRename(oldName, newName string) error {
+func (daemon *Daemon) ContainerRename(ctx context.Context, oldName, newName string) error {
 	var (
 		sid string
 		sb  libnetwork.Sandbox
@@ -57,13 +58,12 @@ func (daemon *Daemon) ContainerRename(oldName, newName string) error {
 	}()
 
 	daemon.releaseName(oldName)
-	if err = container.ToDisk(); err != nil {
+	if err = container.CheckpointTo(daemon.containersReplica); err != nil {
 		return err
 	}
 
-	attributes := map[string]string{
-		"oldName": oldName,
-	}
+	attributes := requestAttributes(ctx)
+	attributes["oldName"] = oldName
 
 	if !container.Running {
 		daemon.LogContainerEventWithAttributes(container, "rename", attributes)
@@ -74,7 +74,7 @@ func (daemon *Daemon) ContainerRename(oldName, newName string) error {
 		if err != nil {
 			container.Name = oldName
 			container.NetworkSettings.IsAnonymousEndpoint = oldIsAnonymousEndpoint
-			if e := container.ToDisk(); e != nil {
+			if e := container.CheckpointTo(daemon.containersReplica); e != nil {
 				logrus.Errorf("%s: Failed in writing to Disk on rename failure: %v", container.ID, e)
 			}
 		}
@@ -4,11 +4,12 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/libcontainerd"
+	"golang.org/x/net/context"
 )
 
 // ContainerResize changes the size of the TTY of the process running
 // in the container with the given name to the given height and width.
-func (daemon *Daemon) ContainerResize(name string, height, width int) error {
+func (daemon *Daemon) ContainerResize(ctx context.Context, name string, height, width int) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -19,10 +20,9 @@ func (daemon *Daemon) ContainerResize(name string, height, width int) error {
 	}
 
 	if err = daemon.containerd.Resize(container.ID, libcontainerd.InitFriendlyName, width, height); err == nil {
-		attributes := map[string]string{
-			"height": fmt.Sprintf("%d", height),
-			"width":  fmt.Sprintf("%d", width),
-		}
+		attributes := requestAttributes(ctx)
+		attributes["height"] = fmt.Sprintf("%d", height)
+		attributes["width"] = fmt.Sprintf("%d", width)
 		daemon.LogContainerEventWithAttributes(container, "resize", attributes)
 	}
 	return err
@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/docker/docker/errors"
+)
+
+// ContainerSeccompProfile returns the seccomp profile recorded for the
+// container run with "--security-opt seccomp=record".
+func (daemon *Daemon) ContainerSeccompProfile(name string) ([]byte, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := container.SeccompProfilePath()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NewRequestNotFoundError(fmt.Errorf("No seccomp profile was recorded for container %s", name))
+		}
+		return nil, err
+	}
+	return profile, nil
+}
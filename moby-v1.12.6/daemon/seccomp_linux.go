@@ -13,6 +13,10 @@ import (
 
 var supportsSeccomp = true
 
+// seccompRecorder records the syscalls of the containers run with
+// "--security-opt seccomp=record".
+var seccompRecorder = seccomp.NewRecorder()
+
 func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	var profile *specs.Seccomp
 	var err error
@@ -31,7 +35,12 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	if c.SeccompProfile == "unconfined" {
 		return nil
 	}
-	if c.SeccompProfile != "" {
+	if c.SeccompProfile == seccompRecordProfile {
+		if err := seccompRecorder.Add(c.ID); err != nil {
+			return err
+		}
+		profile = seccomp.RecordProfile()
+	} else if c.SeccompProfile != "" {
 		profile, err = seccomp.LoadProfile(c.SeccompProfile)
 		if err != nil {
 			return err
@@ -46,3 +55,30 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	rs.Linux.Seccomp = profile
 	return nil
 }
+
+// checkSeccompRecordSupport returns an error if the containers run with
+// runtime cannot have their syscalls recorded.
+func checkSeccompRecordSupport(runtime string) error {
+	return seccomp.CheckRecordSupport(runtime)
+}
+
+// stopSeccompRecording writes the profile allowing the syscalls recorded
+// for a container run with "--security-opt seccomp=record" to the container
+// directory, adding them to the ones recorded by its previous runs.
+func stopSeccompRecording(c *container.Container) {
+	if c.SeccompProfile != seccompRecordProfile {
+		return
+	}
+	profile := seccompRecorder.Remove(c.ID)
+	if profile == nil {
+		return
+	}
+	path, err := c.SeccompProfilePath()
+	if err != nil {
+		logrus.Errorf("Error writing the recorded seccomp profile of container %s: %v", c.ID, err)
+		return
+	}
+	if err := seccomp.WriteProfile(path, profile); err != nil {
+		logrus.Errorf("Error writing the recorded seccomp profile of container %s: %v", c.ID, err)
+	}
+}
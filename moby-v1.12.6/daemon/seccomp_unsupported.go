@@ -2,4 +2,17 @@
 
 package daemon
 
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+)
+
 var supportsSeccomp = false
+
+func checkSeccompRecordSupport(runtime string) error {
+	return fmt.Errorf("seccomp profiles are not supported on this platform")
+}
+
+func stopSeccompRecording(c *container.Container) {
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"syscall"
 
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"github.com/Sirupsen/logrus"
@@ -18,7 +19,7 @@ import (
 )
 
 // ContainerStart starts a container.
-func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig) error {
+func (daemon *Daemon) ContainerStart(ctx context.Context, name string, hostConfig *containertypes.HostConfig) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -54,7 +55,7 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 				// if user has change the network mode on starting, clean up the
 				// old networks. It is a deprecated feature and has been removed in Docker 1.12
 				container.NetworkSettings.Networks = nil
-				if err := container.ToDisk(); err != nil {
+				if err := daemon.checkpointAndSave(container); err != nil {
 					return err
 				}
 			}
@@ -77,19 +78,19 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 		return err
 	}
 
-	return daemon.containerStart(container)
+	return daemon.containerStart(ctx, container)
 }
 
 // Start starts a container
 func (daemon *Daemon) Start(container *container.Container) error {
-	return daemon.containerStart(container)
+	return daemon.containerStart(context.Background(), container)
 }
 
 // containerStart prepares the container to run by setting up everything the
 // container needs, such as storage and networking, as well as links
 // between containers. The container is left waiting for a signal to
 // begin running.
-func (daemon *Daemon) containerStart(container *container.Container) (err error) {
+func (daemon *Daemon) containerStart(ctx context.Context, container *container.Container) (err error) {
 	container.Lock()
 	defer container.Unlock()
 
@@ -110,7 +111,7 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 			if container.ExitCode() == 0 {
 				container.SetExitCode(128)
 			}
-			container.ToDisk()
+			container.CheckpointTo(daemon.containersReplica)
 			daemon.Cleanup(container)
 		}
 	}()
@@ -123,10 +124,14 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 	// backwards API compatibility.
 	container.HostConfig = runconfig.SetDefaultNetModeIfBlank(container.HostConfig)
 
-	if err := daemon.initializeNetworking(container); err != nil {
+	if err := daemon.initializeNetworking(ctx, container); err != nil {
 		return err
 	}
 
+	if err := daemon.applyNetworkLimits(container); err != nil {
+		return fmt.Errorf("Failed to apply the network rate limits: %v", err)
+	}
+
 	spec, err := daemon.createSpec(container)
 	if err != nil {
 		return err
@@ -141,6 +146,9 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		createOptions = append(createOptions, *copts...)
 	}
 
+	daemon.setStateEventAttributes(container.ID, requestAttributes(ctx))
+	defer daemon.setStateEventAttributes(container.ID, nil)
+
 	if err := daemon.containerd.Create(container.ID, *spec, container.InitializeStdio, createOptions...); err != nil {
 		errDesc := grpc.ErrorDesc(err)
 		logrus.Errorf("Create container failed with error: %s", errDesc)
@@ -190,5 +198,7 @@ func (daemon *Daemon) Cleanup(container *container.Container) {
 			logrus.Warnf("%s cleanup: Failed to umount volumes: %v", container.ID, err)
 		}
 	}
+	stopSeccompRecording(container)
+	releaseTraversablePaths(container)
 	container.CancelAttachContext()
 }
@@ -13,7 +13,7 @@ func (daemon *Daemon) getLibcontainerdCreateOptions(container *container.Contain
 	// Ensure a runtime has been assigned to this container
 	if container.HostConfig.Runtime == "" {
 		container.HostConfig.Runtime = stockRuntimeName
-		container.ToDisk()
+		container.CheckpointTo(daemon.containersReplica)
 	}
 
 	rt := daemon.configStore.GetRuntime(container.HostConfig.Runtime)
@@ -5,13 +5,14 @@ import (
 	"strconv"
 
 	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
 )
 
 // ContainerTop is a minimal implementation on Windows currently.
 // TODO Windows: This needs more work, but needs platform API support.
 // All we can currently return (particularly in the case of Hyper-V containers)
 // is a PID and the command.
-func (daemon *Daemon) ContainerTop(containerID string, psArgs string) (*types.ContainerProcessList, error) {
+func (daemon *Daemon) ContainerTop(ctx context.Context, containerID string, psArgs string) (*types.ContainerProcessList, error) {
 
 	// It's really not an equivalent to linux 'ps' on Windows
 	if psArgs != "" {
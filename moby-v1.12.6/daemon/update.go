@@ -4,10 +4,11 @@ import (
 	"fmt"
 
 	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 // ContainerUpdate updates configuration of the container
-func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostConfig) ([]string, error) {
+func (daemon *Daemon) ContainerUpdate(ctx context.Context, name string, hostConfig *container.HostConfig) ([]string, error) {
 	var warnings []string
 
 	warnings, err := daemon.verifyContainerSettings(hostConfig, nil, true)
@@ -15,7 +16,7 @@ func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostCon
 		return warnings, err
 	}
 
-	if err := daemon.update(name, hostConfig); err != nil {
+	if err := daemon.update(ctx, name, hostConfig); err != nil {
 		return warnings, err
 	}
 
@@ -36,7 +37,7 @@ func (daemon *Daemon) ContainerUpdateCmdOnBuild(cID string, cmd []string) error
 	return nil
 }
 
-func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) error {
+func (daemon *Daemon) update(ctx context.Context, name string, hostConfig *container.HostConfig) error {
 	if hostConfig == nil {
 		return nil
 	}
@@ -52,7 +53,7 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 		if restoreConfig {
 			container.Lock()
 			container.HostConfig = &backupHostConfig
-			container.ToDisk()
+			container.CheckpointTo(daemon.containersReplica)
 			container.Unlock()
 		}
 	}()
@@ -73,6 +74,14 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 	// if Restart Policy changed, we need to update container monitor
 	container.UpdateMonitor(hostConfig.RestartPolicy)
 
+	container.Lock()
+	err = daemon.containersReplica.Save(container)
+	container.Unlock()
+	if err != nil {
+		restoreConfig = true
+		return errCannotUpdate(container.ID, err)
+	}
+
 	// If container is not running, update hostConfig struct is enough,
 	// resources will be updated when the container is started again.
 	// If container is running (including paused), we need to update configs
@@ -82,9 +91,13 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 			restoreConfig = true
 			return errCannotUpdate(container.ID, err)
 		}
+		if err := daemon.applyNetworkLimits(container); err != nil {
+			restoreConfig = true
+			return errCannotUpdate(container.ID, err)
+		}
 	}
 
-	daemon.LogContainerEvent(container, "update")
+	daemon.LogContainerEventWithAttributes(container, "update", requestAttributes(ctx))
 
 	return nil
 }
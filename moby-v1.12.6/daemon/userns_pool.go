@@ -0,0 +1,168 @@
+package daemon
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/docker/docker/pkg/idtools"
+)
+
+// defaultUsernsPoolSize is the default number of IDs of the ranges
+// allocated from the user namespace pool.
+const defaultUsernsPoolSize = 65536
+
+// idRangePool allocates ranges of subordinate IDs to containers using their
+// own user namespace mappings. Containers using the same named range share
+// it, other containers each get a range of their own.
+type idRangePool struct {
+	mu sync.Mutex
+	// uidBlocks and gidBlocks are the first host IDs of the ranges of the
+	// pool. Range i maps to the IDs from uidBlocks[i] and gidBlocks[i].
+	uidBlocks []int
+	gidBlocks []int
+	size      int
+
+	// ranges holds the allocated ranges by index, and keys the index of
+	// the range of each key.
+	ranges     map[int]*idRange
+	keys       map[string]int
+	containers map[string]string
+}
+
+// idRange is an allocated range of the pool.
+type idRange struct {
+	key        string
+	containers map[string]struct{}
+}
+
+// newIDRangePool creates a pool dividing the given subordinate ID mappings
+// into ranges of size IDs.
+func newIDRangePool(uidMaps, gidMaps []idtools.IDMap, size int) (*idRangePool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid user namespace range size %d", size)
+	}
+	p := &idRangePool{
+		uidBlocks:  splitIDMaps(uidMaps, size),
+		gidBlocks:  splitIDMaps(gidMaps, size),
+		size:       size,
+		ranges:     make(map[int]*idRange),
+		keys:       make(map[string]int),
+		containers: make(map[string]string),
+	}
+	if p.capacity() == 0 {
+		return nil, fmt.Errorf("the subordinate ID ranges of the user namespace pool have less than %d IDs", size)
+	}
+	return p, nil
+}
+
+// splitIDMaps returns the first host IDs of the ranges of size IDs which
+// fit in idMaps.
+func splitIDMaps(idMaps []idtools.IDMap, size int) []int {
+	var blocks []int
+	for _, m := range idMaps {
+		for start := m.HostID; start+size <= m.HostID+m.Size; start += size {
+			blocks = append(blocks, start)
+		}
+	}
+	return blocks
+}
+
+func (p *idRangePool) capacity() int {
+	if len(p.uidBlocks) < len(p.gidBlocks) {
+		return len(p.uidBlocks)
+	}
+	return len(p.gidBlocks)
+}
+
+func (p *idRangePool) mappings(i int) ([]idtools.IDMap, []idtools.IDMap) {
+	return []idtools.IDMap{{ContainerID: 0, HostID: p.uidBlocks[i], Size: p.size}},
+		[]idtools.IDMap{{ContainerID: 0, HostID: p.gidBlocks[i], Size: p.size}}
+}
+
+// rangeKey returns the key of the range of a container, which is the name
+// of the range if it is shared.
+func rangeKey(containerID, name string) string {
+	if name != "" {
+		return "name:" + name
+	}
+	return "container:" + containerID
+}
+
+// allocate returns the mappings of a range for the container, shared with
+// the other containers using the range name if it is not empty.
+func (p *idRangePool) allocate(containerID, name string) ([]idtools.IDMap, []idtools.IDMap, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := rangeKey(containerID, name)
+	i, ok := p.keys[key]
+	if !ok {
+		for i = 0; i < p.capacity(); i++ {
+			if _, used := p.ranges[i]; !used {
+				break
+			}
+		}
+		if i == p.capacity() {
+			return nil, nil, fmt.Errorf("all the %d ranges of the user namespace pool are in use", p.capacity())
+		}
+		p.ranges[i] = &idRange{key: key, containers: make(map[string]struct{})}
+		p.keys[key] = i
+	}
+
+	p.ranges[i].containers[containerID] = struct{}{}
+	p.containers[containerID] = key
+	uidMaps, gidMaps := p.mappings(i)
+	return uidMaps, gidMaps, nil
+}
+
+// reserve marks the range with the given mappings as used by the container,
+// when restoring the containers of the daemon.
+func (p *idRangePool) reserve(containerID, name string, uidMaps []idtools.IDMap) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(uidMaps) != 1 || uidMaps[0].Size != p.size {
+		return fmt.Errorf("the user namespace mappings of container %s do not match the ranges of the pool", containerID)
+	}
+	i := -1
+	for j, start := range p.uidBlocks {
+		if start == uidMaps[0].HostID {
+			i = j
+			break
+		}
+	}
+	if i < 0 || i >= p.capacity() {
+		return fmt.Errorf("the user namespace range of container %s is not in the pool", containerID)
+	}
+
+	key := rangeKey(containerID, name)
+	if r, ok := p.ranges[i]; ok && r.key != key {
+		return fmt.Errorf("the user namespace range of container %s is already in use", containerID)
+	}
+	if _, ok := p.ranges[i]; !ok {
+		p.ranges[i] = &idRange{key: key, containers: make(map[string]struct{})}
+		p.keys[key] = i
+	}
+	p.ranges[i].containers[containerID] = struct{}{}
+	p.containers[containerID] = key
+	return nil
+}
+
+// release frees the range of the container once no other container uses it.
+func (p *idRangePool) release(containerID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.containers[containerID]
+	if !ok {
+		return
+	}
+	delete(p.containers, containerID)
+	i := p.keys[key]
+	r := p.ranges[i]
+	delete(r.containers, containerID)
+	if len(r.containers) == 0 {
+		delete(p.ranges, i)
+		delete(p.keys, key)
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+)
+
+func TestIDRangePool(t *testing.T) {
+	idMaps := []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 200000}}
+	if _, err := newIDRangePool(idMaps, idMaps, 300000); err == nil {
+		t.Fatal("expected an error for a pool smaller than a range")
+	}
+
+	p, err := newIDRangePool(idMaps, idMaps, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.capacity() != 3 {
+		t.Fatalf("expected 3 ranges, got %d", p.capacity())
+	}
+
+	uid1, gid1, err := p.allocate("c1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid1[0].HostID != 100000 || gid1[0].HostID != 100000 || uid1[0].Size != 65536 {
+		t.Fatalf("unexpected mappings %v %v", uid1, gid1)
+	}
+	uid2, _, err := p.allocate("c2", "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid3, _, err := p.allocate("c3", "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid2[0].HostID == uid1[0].HostID || uid2[0].HostID != uid3[0].HostID {
+		t.Fatalf("expected c2 and c3 to share a range distinct from c1, got %v %v %v", uid1, uid2, uid3)
+	}
+	if _, _, err := p.allocate("c4", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.allocate("c5", ""); err == nil {
+		t.Fatal("expected an error once all ranges are in use")
+	}
+
+	p.release("c2")
+	if _, _, err := p.allocate("c5", ""); err == nil {
+		t.Fatal("expected the shared range to be kept while c3 uses it")
+	}
+	p.release("c3")
+	uid5, _, err := p.allocate("c5", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid5[0].HostID != uid2[0].HostID {
+		t.Fatalf("expected c5 to reuse the released range, got %v", uid5)
+	}
+
+	q, err := newIDRangePool(idMaps, idMaps, 65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.reserve("c5", "", uid5); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.reserve("c6", "", uid5); err == nil {
+		t.Fatal("expected an error reserving a range in use")
+	}
+	if err := q.reserve("c6", "", []idtools.IDMap{{ContainerID: 0, HostID: 1, Size: 65536}}); err == nil {
+		t.Fatal("expected an error reserving a range outside of the pool")
+	}
+}
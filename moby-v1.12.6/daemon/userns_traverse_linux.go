@@ -0,0 +1,347 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/system"
+)
+
+// POSIX ACL extended attribute format, see acl(5) and
+// include/uapi/linux/posix_acl_xattr.h.
+const (
+	aclXattr       = "system.posix_acl_access"
+	aclVersion     = 2
+	aclUserObj     = 0x01
+	aclUser        = 0x02
+	aclGroupObj    = 0x04
+	aclGroup       = 0x08
+	aclMask        = 0x10
+	aclOther       = 0x20
+	aclUndefinedID = 0xffffffff
+	aclExecute     = 0x01
+)
+
+// traversalsFile is the file of the container root recording the rights
+// given to the root user of the container, so that they can be revoked after
+// a restart of the daemon.
+const traversalsFile = "traversals.json"
+
+type aclEntry struct {
+	tag  uint16
+	perm uint16
+	id   uint32
+}
+
+// traversal is the right of a host user to traverse a directory.
+type traversal struct {
+	Dir string
+	UID int
+}
+
+// traversalGrants keeps track of the directories of the daemon root the
+// root user of each container using a range of the user namespace pool may
+// traverse. Containers sharing a range share the same user, so an ACL entry
+// is only removed once no container needs it.
+type traversalGrants struct {
+	mu         sync.Mutex
+	containers map[string][]traversal
+	counts     map[traversal]int
+}
+
+var traversals = &traversalGrants{
+	containers: make(map[string][]traversal),
+	counts:     make(map[traversal]int),
+}
+
+// setupTraversablePaths allows the root user of a container using a range
+// of the user namespace pool to traverse the directories of the daemon root
+// holding its root filesystem and metadata, which are owned by the real
+// root. The right is given to that user only, with an ACL entry, and is
+// revoked when the container stops.
+func (daemon *Daemon) setupTraversablePaths(c *container.Container) error {
+	uid, _ := daemon.containerRemappedUIDGID(c)
+
+	traversals.mu.Lock()
+	defer traversals.mu.Unlock()
+	if _, ok := traversals.containers[c.ID]; ok {
+		return nil
+	}
+
+	var granted []traversal
+	for _, p := range []string{c.BaseFS, c.Root} {
+		for dir := filepath.Dir(p); strings.HasPrefix(dir, daemon.root); dir = filepath.Dir(dir) {
+			t := traversal{Dir: dir, UID: uid}
+			if traversals.counts[t] == 0 {
+				if err := addTraverseACL(dir, uid); err != nil {
+					traversals.revoke(granted)
+					return fmt.Errorf("cannot allow the root user of container %s to traverse %s: %v", c.ID, dir, err)
+				}
+			}
+			traversals.counts[t]++
+			granted = append(granted, t)
+			if dir == daemon.root {
+				break
+			}
+		}
+	}
+	if err := writeTraversals(c, granted); err != nil {
+		traversals.revoke(granted)
+		return fmt.Errorf("cannot record the rights of the root user of container %s: %v", c.ID, err)
+	}
+	traversals.containers[c.ID] = granted
+	return nil
+}
+
+// restoreTraversablePaths rebuilds the rights given to the root users of
+// the containers when the daemon restores them, from the files recording
+// them. The rights of running containers are kept, to be revoked when they
+// stop, while the ACL entries of containers which stopped when the daemon
+// was down are removed. It must be called before any container is started or
+// cleaned up.
+func (daemon *Daemon) restoreTraversablePaths(containers map[string]*container.Container) {
+	traversals.mu.Lock()
+	defer traversals.mu.Unlock()
+
+	stopped := make(map[*container.Container][]traversal)
+	for _, c := range containers {
+		granted, err := readTraversals(c)
+		if err != nil {
+			logrus.Warnf("Failed to read the rights of the root user of container %s: %v", c.ID, err)
+			continue
+		}
+		if len(granted) == 0 {
+			continue
+		}
+		if !c.IsRunning() && !c.IsPaused() {
+			stopped[c] = granted
+			continue
+		}
+		for _, t := range granted {
+			traversals.counts[t]++
+		}
+		traversals.containers[c.ID] = granted
+	}
+
+	for c, granted := range stopped {
+		for _, t := range granted {
+			// Count the right once, so that revoke removes the ACL entry
+			// unless a running container needs it.
+			traversals.counts[t]++
+		}
+		traversals.revoke(granted)
+		if err := os.Remove(filepath.Join(c.Root, traversalsFile)); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("Failed to remove the rights of the root user of container %s: %v", c.ID, err)
+		}
+	}
+}
+
+// releaseTraversablePaths revokes the rights given to the root user of a
+// container by setupTraversablePaths.
+func releaseTraversablePaths(c *container.Container) {
+	traversals.mu.Lock()
+	defer traversals.mu.Unlock()
+	granted, ok := traversals.containers[c.ID]
+	if !ok {
+		return
+	}
+	delete(traversals.containers, c.ID)
+	traversals.revoke(granted)
+	if err := os.Remove(filepath.Join(c.Root, traversalsFile)); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove the rights of the root user of container %s: %v", c.ID, err)
+	}
+}
+
+// writeTraversals records the rights given to the root user of a container
+// in its root.
+func writeTraversals(c *container.Container, granted []traversal) error {
+	data, err := json.Marshal(granted)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(c.Root, traversalsFile), data, 0600)
+}
+
+// readTraversals returns the rights recorded by writeTraversals, if any.
+func readTraversals(c *container.Container) ([]traversal, error) {
+	data, err := ioutil.ReadFile(filepath.Join(c.Root, traversalsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var granted []traversal
+	if err := json.Unmarshal(data, &granted); err != nil {
+		return nil, err
+	}
+	return granted, nil
+}
+
+// revoke releases the given rights, removing the ACL entries which are no
+// longer used. It must be called with t.mu held.
+func (t *traversalGrants) revoke(granted []traversal) {
+	for _, g := range granted {
+		t.counts[g]--
+		if t.counts[g] > 0 {
+			continue
+		}
+		delete(t.counts, g)
+		if err := removeTraverseACL(g.Dir, g.UID); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("Failed to remove the ACL entry of user %d on %s: %v", g.UID, g.Dir, err)
+		}
+	}
+}
+
+// addTraverseACL adds an ACL entry allowing uid to traverse dir. Nothing is
+// changed if any user may already traverse dir.
+func addTraverseACL(dir string, uid int) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if fi.Mode().Perm()&0001 != 0 {
+		return nil
+	}
+	entries, err := readACL(dir, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, e := range entries {
+		if e.tag == aclUser && e.id == uint32(uid) {
+			entries[i].perm |= aclExecute
+			found = true
+		}
+	}
+	if !found {
+		entries = append(entries, aclEntry{tag: aclUser, perm: aclExecute, id: uint32(uid)})
+	}
+
+	// The mask limits the rights of named users: it must include execute.
+	masked := false
+	for i, e := range entries {
+		if e.tag == aclMask {
+			entries[i].perm |= aclExecute
+			masked = true
+		}
+	}
+	if !masked {
+		var groupPerm uint16
+		for _, e := range entries {
+			if e.tag == aclGroupObj {
+				groupPerm = e.perm
+			}
+		}
+		entries = append(entries, aclEntry{tag: aclMask, perm: groupPerm | aclExecute, id: aclUndefinedID})
+	}
+	return writeACL(dir, entries)
+}
+
+// removeTraverseACL removes the ACL entry of uid on dir, if any.
+func removeTraverseACL(dir string, uid int) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := readACL(dir, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	var kept []aclEntry
+	named := false
+	for _, e := range entries {
+		if e.tag == aclUser && e.id == uint32(uid) {
+			continue
+		}
+		if e.tag == aclUser || e.tag == aclGroup {
+			named = true
+		}
+		kept = append(kept, e)
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	if !named {
+		// Without named entries, the ACL is equivalent to the mode bits
+		// once the mask is removed, and the kernel drops it.
+		entries = kept[:0]
+		for _, e := range kept {
+			if e.tag != aclMask {
+				entries = append(entries, e)
+			}
+		}
+		kept = entries
+	}
+	return writeACL(dir, kept)
+}
+
+// readACL returns the access ACL of dir, or the entries equivalent to its
+// mode bits if it has none.
+func readACL(dir string, mode os.FileMode) ([]aclEntry, error) {
+	data, err := system.Lgetxattr(dir, aclXattr)
+	if err != nil {
+		if err == syscall.EOPNOTSUPP {
+			return nil, fmt.Errorf("the filesystem does not support POSIX ACLs")
+		}
+		return nil, err
+	}
+	if data == nil {
+		return []aclEntry{
+			{tag: aclUserObj, perm: uint16(mode>>6) & 7, id: aclUndefinedID},
+			{tag: aclGroupObj, perm: uint16(mode>>3) & 7, id: aclUndefinedID},
+			{tag: aclOther, perm: uint16(mode) & 7, id: aclUndefinedID},
+		}, nil
+	}
+	if len(data) < 4 || (len(data)-4)%8 != 0 || binary.LittleEndian.Uint32(data) != aclVersion {
+		return nil, fmt.Errorf("invalid ACL on %s", dir)
+	}
+	var entries []aclEntry
+	for b := data[4:]; len(b) > 0; b = b[8:] {
+		entries = append(entries, aclEntry{
+			tag:  binary.LittleEndian.Uint16(b),
+			perm: binary.LittleEndian.Uint16(b[2:]),
+			id:   binary.LittleEndian.Uint32(b[4:]),
+		})
+	}
+	return entries, nil
+}
+
+// writeACL sets the access ACL of dir, sorting the entries as the kernel
+// expects them.
+func writeACL(dir string, entries []aclEntry) error {
+	sort.Sort(byTagAndID(entries))
+	data := make([]byte, 4+8*len(entries))
+	binary.LittleEndian.PutUint32(data, aclVersion)
+	for i, e := range entries {
+		b := data[4+8*i:]
+		binary.LittleEndian.PutUint16(b, e.tag)
+		binary.LittleEndian.PutUint16(b[2:], e.perm)
+		binary.LittleEndian.PutUint32(b[4:], e.id)
+	}
+	return system.Lsetxattr(dir, aclXattr, data, 0)
+}
+
+type byTagAndID []aclEntry
+
+func (e byTagAndID) Len() int      { return len(e) }
+func (e byTagAndID) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byTagAndID) Less(i, j int) bool {
+	if e[i].tag != e[j].tag {
+		return e[i].tag < e[j].tag
+	}
+	return e[i].id < e[j].id
+}
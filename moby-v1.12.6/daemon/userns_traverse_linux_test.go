@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/system"
+)
+
+func TestTraverseACL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traverse-acl-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := addTraverseACL(dir, 100000); err != nil {
+		t.Skipf("ACLs are not supported: %v", err)
+	}
+	if err := addTraverseACL(dir, 200000); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := readACL(dir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []aclEntry{
+		{tag: aclUserObj, perm: 7, id: aclUndefinedID},
+		{tag: aclUser, perm: aclExecute, id: 100000},
+		{tag: aclUser, perm: aclExecute, id: 200000},
+		{tag: aclGroupObj, perm: 0, id: aclUndefinedID},
+		{tag: aclMask, perm: aclExecute, id: aclUndefinedID},
+		{tag: aclOther, perm: 0, id: aclUndefinedID},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected ACL %v, got %v", expected, entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Fatalf("expected ACL %v, got %v", expected, entries)
+		}
+	}
+
+	if err := removeTraverseACL(dir, 100000); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeTraverseACL(dir, 200000); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := system.Lgetxattr(dir, aclXattr); err != nil || data != nil {
+		t.Fatalf("expected the ACL to be removed, got %v (%v)", data, err)
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0700 {
+		t.Fatalf("expected mode 0700 to be restored, got %o", fi.Mode().Perm())
+	}
+}
+
+func hasTraverseACL(t *testing.T, dir string, uid int) bool {
+	entries, err := readACL(dir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.tag == aclUser && e.id == uint32(uid) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRestoreTraversablePaths(t *testing.T) {
+	root, err := ioutil.TempDir("", "traverse-restore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	shared, running, stopped := filepath.Join(root, "containers"), filepath.Join(root, "running"), filepath.Join(root, "stopped")
+	containers := make(map[string]*container.Container)
+	for _, id := range []string{"running", "stopped"} {
+		c := &container.Container{CommonContainer: container.CommonContainer{
+			ID:    id,
+			Root:  filepath.Join(shared, id),
+			State: container.NewState(),
+		}}
+		if err := os.MkdirAll(c.Root, 0700); err != nil {
+			t.Fatal(err)
+		}
+		containers[id] = c
+	}
+	containers["running"].Running = true
+	for _, dir := range []string{shared, running, stopped} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := addTraverseACL(dir, 100000); err != nil {
+			t.Skipf("ACLs are not supported: %v", err)
+		}
+	}
+	if err := writeTraversals(containers["running"], []traversal{{Dir: shared, UID: 100000}, {Dir: running, UID: 100000}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTraversals(containers["stopped"], []traversal{{Dir: shared, UID: 100000}, {Dir: stopped, UID: 100000}}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		delete(traversals.containers, "running")
+		delete(traversals.counts, traversal{Dir: shared, UID: 100000})
+		delete(traversals.counts, traversal{Dir: running, UID: 100000})
+	}()
+
+	daemon := &Daemon{root: root}
+	daemon.restoreTraversablePaths(containers)
+
+	if !hasTraverseACL(t, shared, 100000) || !hasTraverseACL(t, running, 100000) {
+		t.Fatal("expected the rights of the running container to be kept")
+	}
+	if hasTraverseACL(t, stopped, 100000) {
+		t.Fatal("expected the rights of the stopped container to be revoked")
+	}
+	if _, err := os.Stat(filepath.Join(containers["stopped"].Root, traversalsFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected the rights of the stopped container to be forgotten, got %v", err)
+	}
+
+	releaseTraversablePaths(containers["running"])
+	if hasTraverseACL(t, shared, 100000) || hasTraverseACL(t, running, 100000) {
+		t.Fatal("expected the rights of the running container to be revoked when it stops")
+	}
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package daemon
+
+import "github.com/docker/docker/container"
+
+func (daemon *Daemon) restoreTraversablePaths(containers map[string]*container.Container) {
+}
+
+func releaseTraversablePaths(c *container.Container) {
+}
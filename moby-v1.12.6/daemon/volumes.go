@@ -6,11 +6,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/volume"
+	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 var (
@@ -37,6 +41,43 @@ func volumeToAPIType(v volume.Volume) *types.Volume {
 	return tv
 }
 
+// setVolumeUsage sets the creation time of the volume and its usage data,
+// including its size if size is set. Only the references to the volume
+// held by containers are reported.
+func (daemon *Daemon) setVolumeUsage(apiV *types.Volume, v volume.Volume, size bool) error {
+	u, err := daemon.volumes.Usage(v, size)
+	if err != nil {
+		return err
+	}
+
+	if !u.CreatedAt.IsZero() {
+		apiV.CreatedAt = u.CreatedAt.Format(time.RFC3339Nano)
+	}
+	apiV.UsageData = &types.VolumeUsageData{
+		Size:       u.Size,
+		Containers: []string{},
+	}
+	if !u.LastUsedAt.IsZero() {
+		apiV.UsageData.LastUsedAt = u.LastUsedAt.Format(time.RFC3339Nano)
+	}
+	for _, ref := range u.Refs {
+		if daemon.containers.Get(ref) != nil {
+			apiV.UsageData.Containers = append(apiV.UsageData.Containers, ref)
+		}
+	}
+	return nil
+}
+
+// VolumeDriverScope returns the scope of the volume driver with the given
+// name, `local` or `global`.
+func (daemon *Daemon) VolumeDriverScope(name string) (string, error) {
+	vd, err := volumedrivers.GetDriver(name)
+	if err != nil {
+		return "", err
+	}
+	return vd.Scope(), nil
+}
+
 // Len returns the number of mounts. Used in sorting.
 func (m mounts) Len() int {
 	return len(m)
@@ -65,7 +106,8 @@ func (m mounts) parts(i int) int {
 // 1. Select the previously configured mount points for the containers, if any.
 // 2. Select the volumes mounted from another containers. Overrides previously configured mount point destination.
 // 3. Select the bind mounts set by the client. Overrides previously configured mount point destinations.
-// 4. Cleanup old volumes that are about to be reassigned.
+// 4. Select the mounts set in the Mounts field by the client. Overrides previously configured mount point destinations.
+// 5. Cleanup old volumes that are about to be reassigned.
 func (daemon *Daemon) registerMountPoints(container *container.Container, hostConfig *containertypes.HostConfig) (retErr error) {
 	binds := map[string]bool{}
 	mountPoints := map[string]*volume.MountPoint{}
@@ -154,9 +196,68 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 		mountPoints[bind.Destination] = bind
 	}
 
+	// 4. Read the mounts of the Mounts field
+	for _, cfg := range hostConfig.Mounts {
+		if err := volume.ValidateMountConfig(&cfg); err != nil {
+			return err
+		}
+		destination := filepath.Clean(cfg.Target)
+		_, tmpfsExists := hostConfig.Tmpfs[destination]
+		if binds[destination] || tmpfsExists {
+			return fmt.Errorf("Duplicate mount point '%s'", destination)
+		}
+		binds[destination] = true
+
+		if cfg.Type == mounttypes.TypeTmpfs {
+			// tmpfs mounts are not mount points, see Container.TmpfsMounts
+			if mp, exists := mountPoints[destination]; exists {
+				delete(mountPoints, destination)
+				if mp.Volume != nil && !volumeMounted(mountPoints, mp.Volume.Name()) {
+					daemon.volumes.Dereference(mp.Volume, container.ID)
+				}
+			}
+			continue
+		}
+
+		mp, err := volume.ParseMountConfig(cfg)
+		if err != nil {
+			return err
+		}
+		if mp.Type() != "bind" {
+			name := mp.Name
+			if name == "" {
+				name = stringid.GenerateNonCryptoID()
+			}
+			var driverOpts, labels map[string]string
+			if cfg.VolumeOptions != nil {
+				labels = cfg.VolumeOptions.Labels
+				if cfg.VolumeOptions.DriverConfig != nil {
+					driverOpts = cfg.VolumeOptions.DriverConfig.Options
+				}
+			}
+			driver := mp.Driver
+			if driver == "" {
+				driver = hostConfig.VolumeDriver
+			}
+			v, err := daemon.volumes.CreateWithRef(name, driver, container.ID, driverOpts, labels)
+			if err != nil {
+				return err
+			}
+			mp.Volume = v
+			mp.Name = v.Name()
+			mp.Driver = v.DriverName()
+			mp.Source = v.Path()
+			mp.Named = cfg.Source != ""
+			if mp.Driver == volume.DefaultDriverName {
+				setBindModeIfNull(mp)
+			}
+		}
+		mountPoints[destination] = mp
+	}
+
 	container.Lock()
 
-	// 4. Cleanup old volumes that are about to be reassigned.
+	// 5. Cleanup old volumes that are about to be reassigned.
 	for _, m := range mountPoints {
 		if m.BackwardsCompatible() {
 			if mp, exists := container.MountPoints[m.Destination]; exists && mp.Volume != nil {
@@ -171,6 +272,17 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 	return nil
 }
 
+// volumeMounted returns true if one of mountPoints mounts the volume name.
+// Dereference drops all the references of a container on a volume at once.
+func volumeMounted(mountPoints map[string]*volume.MountPoint, name string) bool {
+	for _, m := range mountPoints {
+		if m.Volume != nil && m.Volume.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // lazyInitializeVolume initializes a mountpoint's volume if needed.
 // This happens after a daemon restart.
 func (daemon *Daemon) lazyInitializeVolume(containerID string, m *volume.MountPoint) error {
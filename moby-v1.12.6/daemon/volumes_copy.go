@@ -0,0 +1,171 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	volumestore "github.com/docker/docker/volume/store"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// VolumeClone creates a volume with the given name as a copy of the content
+// of another volume. The driver of the volume copies it on its own if it
+// supports snapshots, otherwise the files of the volume are copied into a
+// new volume of the same driver.
+func (daemon *Daemon) VolumeClone(ctx context.Context, name, target string, opts, labels map[string]string) (*types.Volume, error) {
+	if target == "" {
+		target = stringid.GenerateNonCryptoID()
+	}
+
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the volume from being removed while it is copied.
+	ref := stringid.GenerateNonCryptoID()
+	src, err := daemon.volumes.GetWithRef(v.Name(), v.DriverName(), ref)
+	if err != nil {
+		return nil, err
+	}
+	defer daemon.volumes.Dereference(src, ref)
+
+	clone, err := daemon.volumes.Snapshot(src, target, opts, labels)
+	if volumestore.IsNotSupported(err) {
+		clone, err = daemon.copyVolume(src, target, ref, opts, labels)
+	}
+	if err != nil {
+		if volumestore.IsNameConflict(err) {
+			return nil, errVolumeNameConflict(target)
+		}
+		return nil, fmt.Errorf("Error cloning volume %s: %v", name, err)
+	}
+
+	attributes := requestAttributes(ctx)
+	attributes["driver"] = clone.DriverName()
+	daemon.LogVolumeEvent(clone.Name(), "create", attributes)
+	apiV := volumeToAPIType(clone)
+	apiV.Mountpoint = clone.Path()
+	return apiV, nil
+}
+
+// copyVolume creates a volume of the driver of the source volume, and copies
+// the files of the source volume into it. It fails if a volume with the name
+// already exists, and the new volume is removed if the copy fails.
+func (daemon *Daemon) copyVolume(src volume.Volume, name, ref string, opts, labels map[string]string) (volume.Volume, error) {
+	v, err := daemon.volumes.CreateNewWithRef(name, src.DriverName(), ref, opts, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	err = func() error {
+		srcPath, err := src.Mount(ref)
+		if err != nil {
+			return err
+		}
+		defer unmountVolume(src, ref)
+
+		dstPath, err := v.Mount(ref)
+		if err != nil {
+			return err
+		}
+		defer unmountVolume(v, ref)
+
+		return chrootarchive.CopyWithTar(srcPath, dstPath)
+	}()
+
+	daemon.volumes.Dereference(v, ref)
+	if err != nil {
+		if rmErr := daemon.volumes.Remove(v); rmErr != nil {
+			logrus.Errorf("Error removing volume %s after a failed copy: %v", name, rmErr)
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// VolumeExport writes the content of a volume to the writer, as a tar
+// archive.
+func (daemon *Daemon) VolumeExport(name string, out io.Writer) error {
+	v, path, ref, err := daemon.mountVolume(name)
+	if err != nil {
+		return err
+	}
+	defer daemon.releaseVolume(v, ref)
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	data, err := archive.TarWithOptions(path, &archive.TarOptions{
+		Compression: archive.Uncompressed,
+		UIDMaps:     uidMaps,
+		GIDMaps:     gidMaps,
+	})
+	if err != nil {
+		return fmt.Errorf("Error exporting volume %s: %v", name, err)
+	}
+	defer data.Close()
+
+	if _, err := io.Copy(out, data); err != nil {
+		return fmt.Errorf("Error exporting volume %s: %v", name, err)
+	}
+	return nil
+}
+
+// VolumeImport extracts a tar archive, which may be compressed, into a
+// volume. Files of the volume which are also in the archive are replaced.
+func (daemon *Daemon) VolumeImport(name string, in io.Reader) error {
+	v, path, ref, err := daemon.mountVolume(name)
+	if err != nil {
+		return err
+	}
+	defer daemon.releaseVolume(v, ref)
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	if err := chrootarchive.Untar(in, path, &archive.TarOptions{
+		UIDMaps: uidMaps,
+		GIDMaps: gidMaps,
+	}); err != nil {
+		return fmt.Errorf("Error importing into volume %s: %v", name, err)
+	}
+	return nil
+}
+
+// mountVolume mounts the volume with the given name, under a reference
+// keeping it from being removed until releaseVolume is called.
+func (daemon *Daemon) mountVolume(name string) (volume.Volume, string, string, error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, "", "", err
+	}
+	ref := stringid.GenerateNonCryptoID()
+	if v, err = daemon.volumes.GetWithRef(v.Name(), v.DriverName(), ref); err != nil {
+		return nil, "", "", err
+	}
+
+	path, err := v.Mount(ref)
+	if err != nil {
+		daemon.volumes.Dereference(v, ref)
+		return nil, "", "", err
+	}
+	return v, path, ref, nil
+}
+
+func (daemon *Daemon) releaseVolume(v volume.Volume, ref string) {
+	unmountVolume(v, ref)
+	daemon.volumes.Dereference(v, ref)
+}
+
+func unmountVolume(v volume.Volume, ref string) {
+	if err := v.Unmount(ref); err != nil {
+		logrus.Warnf("error while unmounting volume %s: %v", v.Name(), err)
+	}
+}
+
+func errVolumeNameConflict(name string) error {
+	return errors.NewRequestConflictError(fmt.Errorf("A volume named %s already exists. Choose a different volume name.", name))
+}
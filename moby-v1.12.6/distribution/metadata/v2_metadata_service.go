@@ -17,6 +17,10 @@ type V2MetadataService struct {
 type V2Metadata struct {
 	Digest           digest.Digest
 	SourceRepository string
+	// MediaType is the media type of the blob. It is empty for metadata
+	// recorded before the media type was tracked, and for blobs pulled
+	// through a schema1 manifest, which are gzip-compressed.
+	MediaType string `json:",omitempty"`
 }
 
 // maxMetadata is the number of metadata entries to keep per layer DiffID.
@@ -73,7 +73,9 @@ func (p *v2Puller) Pull(ctx context.Context, ref reference.Named) (err error) {
 		if _, ok := err.(fallbackError); ok {
 			return err
 		}
-		if continueOnError(err) {
+		// A mirror serving stale, incomplete or corrupted content must
+		// not prevent the pull from the registry it mirrors.
+		if p.endpoint.Mirror || continueOnError(err) {
 			logrus.Errorf("Error trying v2 registry: %v", err)
 			return fallbackError{
 				err:         err,
@@ -139,6 +141,9 @@ type v2LayerDescriptor struct {
 	tmpFile           *os.File
 	verifier          digest.Verifier
 	src               distribution.Descriptor
+	partials          *xfer.PartialDownloadStore
+	// partial is set when tmpFile is held from partials.
+	partial *xfer.PartialDownload
 }
 
 func (ld *v2LayerDescriptor) Key() string {
@@ -162,7 +167,7 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	)
 
 	if ld.tmpFile == nil {
-		ld.tmpFile, err = createDownloadFile()
+		ld.tmpFile, offset, err = ld.openDownloadFile()
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
@@ -172,10 +177,8 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 			logrus.Debugf("error seeking to end of download file: %v", err)
 			offset = 0
 
-			ld.tmpFile.Close()
-			if err := os.Remove(ld.tmpFile.Name()); err != nil {
-				logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-			}
+			ld.removeDownloadFile()
+			ld.verifier = nil
 			ld.tmpFile, err = createDownloadFile()
 			if err != nil {
 				return nil, 0, xfer.DoNotRetry{Err: err}
@@ -239,15 +242,19 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		}
 	}
 
-	_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
-	if err != nil {
-		if err == transport.ErrWrongCodeForByteRange {
-			if err := ld.truncateDownloadFile(); err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
+	// An earlier pull may have fetched the whole blob before it was
+	// interrupted, in which case there is nothing left to read.
+	if size == 0 || offset < size {
+		_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
+		if err != nil {
+			if err == transport.ErrWrongCodeForByteRange {
+				if err := ld.truncateDownloadFile(); err != nil {
+					return nil, 0, xfer.DoNotRetry{Err: err}
+				}
+				return nil, 0, err
 			}
-			return nil, 0, err
+			return nil, 0, retryOnError(err)
 		}
-		return nil, 0, retryOnError(err)
 	}
 
 	progress.Update(progressOutput, ld.ID(), "Verifying Checksum")
@@ -265,6 +272,8 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 			return nil, 0, err
 		}
+		// Don't keep corrupted data for a later pull to resume from.
+		ld.truncateDownloadFile()
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
 
@@ -274,20 +283,22 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 	_, err = tmpFile.Seek(0, os.SEEK_SET)
 	if err != nil {
-		tmpFile.Close()
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		ld.tmpFile = nil
+		ld.removeDownloadFile()
 		ld.verifier = nil
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
 
 	// hand off the temporary file to the download manager, so it will only
 	// be closed once
+	partial := ld.partial
 	ld.tmpFile = nil
+	ld.partial = nil
 
 	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
+		if partial != nil {
+			// The download is complete, there is nothing left to resume.
+			return partial.Discard()
+		}
 		tmpFile.Close()
 		err := os.RemoveAll(tmpFile.Name())
 		if err != nil {
@@ -298,12 +309,70 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 }
 
 func (ld *v2LayerDescriptor) Close() {
-	if ld.tmpFile != nil {
+	if ld.tmpFile == nil {
+		return
+	}
+	if ld.partial != nil {
+		// Keep what was downloaded so far for the next pull of this
+		// blob.
+		if err := ld.partial.Release(); err != nil {
+			logrus.Errorf("Failed to close partial download %s: %v", ld.tmpFile.Name(), err)
+		}
+		ld.tmpFile = nil
+		ld.partial = nil
+		return
+	}
+	ld.tmpFile.Close()
+	if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
+		logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
+	}
+}
+
+// openDownloadFile returns the file to download the blob to, and the number
+// of bytes it already holds. If the puller keeps partial downloads, the data
+// of an earlier, interrupted pull of the same blob is reused. That data is
+// fed to the digest verifier, so only the remaining bytes are fetched from
+// the registry.
+func (ld *v2LayerDescriptor) openDownloadFile() (*os.File, int64, error) {
+	if ld.partials != nil {
+		partial, err := ld.partials.Acquire(ld.digest)
+		if err == nil {
+			ld.verifier, err = digest.NewDigestVerifier(ld.digest)
+			if err != nil {
+				partial.Release()
+				return nil, 0, err
+			}
+			offset, err := io.Copy(ld.verifier, partial)
+			if err == nil {
+				if offset != 0 {
+					logrus.Debugf("attempting to resume download of %q from %d bytes of an earlier pull", ld.digest, offset)
+				}
+				ld.partial = partial
+				return partial.File, offset, nil
+			}
+			ld.verifier = nil
+			partial.Discard()
+		}
+		logrus.Debugf("not keeping partial download of %q: %v", ld.digest, err)
+	}
+	tmpFile, err := createDownloadFile()
+	return tmpFile, 0, err
+}
+
+// removeDownloadFile closes and removes the download file.
+func (ld *v2LayerDescriptor) removeDownloadFile() {
+	if ld.partial != nil {
+		if err := ld.partial.Discard(); err != nil {
+			logrus.Errorf("Failed to remove partial download: %s", ld.tmpFile.Name())
+		}
+		ld.partial = nil
+	} else {
 		ld.tmpFile.Close()
-		if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
+		if err := os.Remove(ld.tmpFile.Name()); err != nil {
 			logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
 		}
 	}
+	ld.tmpFile = nil
 }
 
 func (ld *v2LayerDescriptor) truncateDownloadFile() error {
@@ -325,7 +394,7 @@ func (ld *v2LayerDescriptor) truncateDownloadFile() error {
 
 func (ld *v2LayerDescriptor) Registered(diffID layer.DiffID) {
 	// Cache mapping from this layer's DiffID to the blobsum
-	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.FullName()})
+	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.FullName(), MediaType: ld.src.MediaType})
 }
 
 func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdated bool, err error) {
@@ -476,6 +545,7 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 			repoInfo:          p.repoInfo,
 			repo:              p.repo,
 			V2MetadataService: p.V2MetadataService,
+			partials:          p.config.DownloadManager.PartialDownloadStore(),
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -527,6 +597,7 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 			repoInfo:          p.repoInfo,
 			V2MetadataService: p.V2MetadataService,
 			src:               d,
+			partials:          p.config.DownloadManager.PartialDownloadStore(),
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
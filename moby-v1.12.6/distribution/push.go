@@ -2,7 +2,6 @@ package distribution
 
 import (
 	"bufio"
-	"compress/gzip"
 	"fmt"
 	"io"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -48,6 +48,9 @@ type ImagePushConfig struct {
 	TrustKey libtrust.PrivateKey
 	// UploadManager dispatches uploads.
 	UploadManager *xfer.LayerUploadManager
+	// LayerCompression is the compression used for layers pushed to a v2
+	// registry. It must be archive.Gzip or archive.Zstd.
+	LayerCompression archive.Compression
 }
 
 // Pusher is an interface that abstracts pushing for different API versions.
@@ -61,6 +64,11 @@ type Pusher interface {
 
 const compressionBufSize = 32768
 
+// MediaTypeLayerZstd is the media type used for zstd-compressed layer blobs
+// in schema2 manifests. It is the OCI media type, as schema2 defines none
+// for zstd.
+const MediaTypeLayerZstd = "application/vnd.oci.image.layer.v1.tar+zstd"
+
 // NewPusher creates a new Pusher interface that will push to either a v1 or v2
 // registry. The endpoint argument contains a Version field that determines
 // whether a v1 or v2 pusher will be created. The other parameters are passed
@@ -178,9 +186,9 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 	return lastErr
 }
 
-// compress returns an io.ReadCloser which will supply a compressed version of
-// the provided Reader. The caller must close the ReadCloser after reading the
-// compressed data.
+// compress returns an io.ReadCloser which will supply a version of the
+// provided Reader compressed with the given compression. The caller must close
+// the ReadCloser after reading the compressed data.
 //
 // Note that this function returns a reader instead of taking a writer as an
 // argument so that it can be used with httpBlobWriter's ReadFrom method.
@@ -191,19 +199,30 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 // is finished. This allows the caller to make sure the goroutine finishes
 // before it releases any resources connected with the reader that was
 // passed in.
-func compress(in io.Reader) (io.ReadCloser, chan struct{}) {
+func compress(in io.Reader, compression archive.Compression) (io.ReadCloser, chan struct{}) {
 	compressionDone := make(chan struct{})
 
 	pipeReader, pipeWriter := io.Pipe()
 	// Use a bufio.Writer to avoid excessive chunking in HTTP request.
 	bufWriter := bufio.NewWriterSize(pipeWriter, compressionBufSize)
-	compressor := gzip.NewWriter(bufWriter)
 
 	go func() {
-		_, err := io.Copy(compressor, in)
-		if err == nil {
-			err = compressor.Close()
+		compressor, err := archive.CompressStream(bufWriter, compression)
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+			close(compressionDone)
+			return
+		}
+		if _, err := io.Copy(compressor, in); err != nil {
+			// Fail the writes of the compressor before closing it, so
+			// that a compressor running as a separate process exits
+			// rather than blocking on its output.
+			pipeWriter.CloseWithError(err)
+			compressor.Close()
+			close(compressionDone)
+			return
 		}
+		err = compressor.Close()
 		if err == nil {
 			err = bufWriter.Flush()
 		}
@@ -17,6 +17,7 @@ import (
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/stringid"
@@ -134,12 +135,18 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, ima
 
 	var descriptors []xfer.UploadDescriptor
 
+	layerCompression := p.config.LayerCompression
+	if layerCompression == archive.Uncompressed {
+		layerCompression = archive.Gzip
+	}
+
 	descriptorTemplate := v2PushDescriptor{
 		v2MetadataService: p.v2MetadataService,
 		repoInfo:          p.repoInfo,
 		ref:               p.ref,
 		repo:              p.repo,
 		pushState:         &p.pushState,
+		layerCompression:  layerCompression,
 	}
 
 	// Loop bounds condition is to avoid pushing the base layer on Windows.
@@ -232,6 +239,7 @@ type v2PushDescriptor struct {
 	repo              distribution.Repository
 	pushState         *pushState
 	remoteDescriptor  distribution.Descriptor
+	layerCompression  archive.Compression
 }
 
 func (pd *v2PushDescriptor) Key() string {
@@ -334,7 +342,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 		case distribution.ErrBlobMounted:
 			progress.Updatef(progressOutput, pd.ID(), "Mounted from %s", err.From.Name())
 
-			err.Descriptor.MediaType = schema2.MediaTypeLayer
+			err.Descriptor.MediaType = layerMediaType(mountFrom)
 
 			pd.pushState.Lock()
 			pd.pushState.confirmedV2 = true
@@ -342,7 +350,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			pd.pushState.Unlock()
 
 			// Cache mapping from this layer's DiffID to the blobsum
-			if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: mountFrom.Digest, SourceRepository: pd.repoInfo.FullName()}); err != nil {
+			if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: mountFrom.Digest, SourceRepository: pd.repoInfo.FullName(), MediaType: mountFrom.MediaType}); err != nil {
 				return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 			}
 			return err.Descriptor, nil
@@ -374,7 +382,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	size, _ := pd.layer.DiffSize()
 
 	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, arch), progressOutput, size, pd.ID(), "Pushing")
-	compressedReader, compressionDone := compress(reader)
+	compressedReader, compressionDone := compress(reader, pd.layerCompression)
 	defer func() {
 		reader.Close()
 		<-compressionDone
@@ -397,8 +405,13 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	logrus.Debugf("uploaded layer %s (%s), %d bytes", diffID, pushDigest, nn)
 	progress.Update(progressOutput, pd.ID(), "Pushed")
 
+	mediaType := schema2.MediaTypeLayer
+	if pd.layerCompression == archive.Zstd {
+		mediaType = MediaTypeLayerZstd
+	}
+
 	// Cache mapping from this layer's DiffID to the blobsum
-	if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: pushDigest, SourceRepository: pd.repoInfo.FullName()}); err != nil {
+	if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: pushDigest, SourceRepository: pd.repoInfo.FullName(), MediaType: mediaType}); err != nil {
 		return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 	}
 
@@ -410,7 +423,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 
 	descriptor := distribution.Descriptor{
 		Digest:    pushDigest,
-		MediaType: schema2.MediaTypeLayer,
+		MediaType: mediaType,
 		Size:      nn,
 	}
 	pd.pushState.remoteLayers[diffID] = descriptor
@@ -440,7 +453,7 @@ func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, rep
 		descriptor, err := repo.Blobs(ctx).Stat(ctx, meta.Digest)
 		switch err {
 		case nil:
-			descriptor.MediaType = schema2.MediaTypeLayer
+			descriptor.MediaType = layerMediaType(meta)
 			return descriptor, true, nil
 		case distribution.ErrBlobUnknown:
 			// nop
@@ -450,3 +463,12 @@ func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, rep
 	}
 	return distribution.Descriptor{}, false, nil
 }
+
+// layerMediaType returns the media type of the layer blob described by meta.
+// Blobs recorded without a media type are gzip-compressed layers.
+func layerMediaType(meta metadata.V2Metadata) string {
+	if meta.MediaType != "" {
+		return meta.MediaType
+	}
+	return schema2.MediaTypeLayer
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -24,6 +27,10 @@ const maxDownloadAttempts = 5
 type LayerDownloadManager struct {
 	layerStore layer.Store
 	tm         TransferManager
+	partials   *PartialDownloadStore
+	// stagingSlots bounds the number of layers being decompressed
+	// ahead of their registration.
+	stagingSlots chan struct{}
 }
 
 // SetConcurrency set the max concurrent downloads for each pull
@@ -32,11 +39,31 @@ func (ldm *LayerDownloadManager) SetConcurrency(concurrency int) {
 }
 
 // NewLayerDownloadManager returns a new LayerDownloadManager.
-func NewLayerDownloadManager(layerStore layer.Store, concurrencyLimit int) *LayerDownloadManager {
-	return &LayerDownloadManager{
-		layerStore: layerStore,
-		tm:         NewTransferManager(concurrencyLimit),
+func NewLayerDownloadManager(layerStore layer.Store, concurrencyLimit int, options ...func(*LayerDownloadManager)) *LayerDownloadManager {
+	manager := LayerDownloadManager{
+		layerStore:   layerStore,
+		tm:           NewTransferManager(concurrencyLimit),
+		stagingSlots: make(chan struct{}, runtime.NumCPU()),
 	}
+	for _, option := range options {
+		option(&manager)
+	}
+	return &manager
+}
+
+// WithPartialDownloadStore configures the LayerDownloadManager to keep the
+// data of interrupted downloads in store, so they can be resumed by later
+// pulls.
+func WithPartialDownloadStore(store *PartialDownloadStore) func(*LayerDownloadManager) {
+	return func(ldm *LayerDownloadManager) {
+		ldm.partials = store
+	}
+}
+
+// PartialDownloadStore returns the store keeping partial downloads, or nil
+// if interrupted downloads are not kept.
+func (ldm *LayerDownloadManager) PartialDownloadStore() *PartialDownloadStore {
+	return ldm.partials
 }
 
 type downloadTransfer struct {
@@ -293,6 +320,19 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 			close(inactive)
 
 			if parentDownload != nil {
+				select {
+				case <-parentDownload.Done():
+				default:
+					// The parent layer is not registered yet.
+					// Decompress this layer meanwhile, so only the
+					// extraction is left once the parent is ready.
+					downloadReader, size, err = ldm.stage(d.Transfer.Context(), descriptor, downloadReader, size, progressOutput)
+					if err != nil {
+						d.err = err
+						return
+					}
+				}
+
 				select {
 				case <-d.Transfer.Context().Done():
 					d.err = errors.New("layer registration cancelled")
@@ -358,6 +398,61 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 	}
 }
 
+// stage decompresses the downloaded layer data into a temporary file, and
+// returns the uncompressed data along with its size. The number of layers
+// being staged at the same time is bounded by the number of CPUs.
+func (ldm *LayerDownloadManager) stage(ctx context.Context, descriptor DownloadDescriptor, downloadReader io.ReadCloser, size int64, progressOutput progress.Output) (io.ReadCloser, int64, error) {
+	defer downloadReader.Close()
+
+	select {
+	case ldm.stagingSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, 0, errors.New("layer decompression cancelled")
+	}
+	defer func() { <-ldm.stagingSlots }()
+
+	stagingFile, err := ioutil.TempFile("", "StagedLayer")
+	if err != nil {
+		return nil, 0, err
+	}
+	removeStagingFile := func() error {
+		stagingFile.Close()
+		err := os.RemoveAll(stagingFile.Name())
+		if err != nil {
+			logrus.Errorf("Failed to remove temp file: %s", stagingFile.Name())
+		}
+		return err
+	}
+
+	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, downloadReader), progressOutput, size, descriptor.ID(), "Decompressing")
+	defer reader.Close()
+
+	inflatedLayerData, err := archive.DecompressStream(reader)
+	if err != nil {
+		removeStagingFile()
+		return nil, 0, fmt.Errorf("could not get decompression stream: %v", err)
+	}
+	defer inflatedLayerData.Close()
+
+	stagedSize, err := io.Copy(stagingFile, inflatedLayerData)
+	if err != nil {
+		removeStagingFile()
+		select {
+		case <-ctx.Done():
+			return nil, 0, errors.New("layer decompression cancelled")
+		default:
+			return nil, 0, fmt.Errorf("failed to decompress layer: %v", err)
+		}
+	}
+	if _, err := stagingFile.Seek(0, os.SEEK_SET); err != nil {
+		removeStagingFile()
+		return nil, 0, err
+	}
+
+	progress.Update(progressOutput, descriptor.ID(), "Waiting for parent layer")
+	return ioutils.NewReadCloserWrapper(stagingFile, removeStagingFile), stagedSize, nil
+}
+
 // makeDownloadFuncFromDownload returns a function that performs the layer
 // registration when the layer data is coming from an existing download. It
 // waits for sourceDownload and parentDownload to complete, and then
@@ -2,6 +2,7 @@ package xfer
 
 import (
 	"bytes"
+	"compress/gzip"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -111,7 +112,7 @@ func (ls *mockLayerStore) Get(chainID layer.ChainID) (layer.Layer, error) {
 func (ls *mockLayerStore) Release(l layer.Layer) ([]layer.Metadata, error) {
 	return []layer.Metadata{}, nil
 }
-func (ls *mockLayerStore) CreateRWLayer(string, layer.ChainID, string, layer.MountInit, map[string]string) (layer.RWLayer, error) {
+func (ls *mockLayerStore) CreateRWLayer(string, layer.ChainID, *layer.CreateRWLayerOpts) (layer.RWLayer, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -339,3 +340,33 @@ func TestCancelledDownload(t *testing.T) {
 	close(progressChan)
 	<-progressDone
 }
+
+func TestStageDecompressesLayer(t *testing.T) {
+	ldm := NewLayerDownloadManager(&mockLayerStore{make(map[layer.ChainID]*mockLayer)}, maxDownloadConcurrency)
+
+	data := bytes.Repeat([]byte("layer data"), 1024)
+	var compressed bytes.Buffer
+	gzWriter := gzip.NewWriter(&compressed)
+	if _, err := gzWriter.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	gzWriter.Close()
+
+	descriptor := &mockDownloadDescriptor{id: "id1"}
+	staged, size, err := ldm.stage(context.Background(), descriptor, ioutil.NopCloser(&compressed), int64(compressed.Len()), progress.ChanOutput(make(chan progress.Progress, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer staged.Close()
+
+	if size != int64(len(data)) {
+		t.Fatalf("expected staged size %d, got %d", len(data), size)
+	}
+	stagedData, err := ioutil.ReadAll(staged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stagedData, data) {
+		t.Fatal("staged layer data does not match the uncompressed layer data")
+	}
+}
@@ -0,0 +1,125 @@
+package xfer
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+)
+
+// ErrPartialDownloadInUse is returned by PartialDownloadStore.Acquire when
+// another download of the same content holds the partial download.
+var ErrPartialDownloadInUse = errors.New("partial download is in use")
+
+// PartialDownloadStore keeps the data of interrupted downloads on disk,
+// keyed by content digest, so that a later download of the same content can
+// resume where the previous one stopped instead of starting from zero.
+type PartialDownloadStore struct {
+	root string
+
+	mu    sync.Mutex
+	inUse map[digest.Digest]struct{}
+}
+
+// NewPartialDownloadStore returns a PartialDownloadStore keeping partial
+// downloads in root.
+func NewPartialDownloadStore(root string) (*PartialDownloadStore, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	return &PartialDownloadStore{
+		root:  root,
+		inUse: make(map[digest.Digest]struct{}),
+	}, nil
+}
+
+func (s *PartialDownloadStore) path(dgst digest.Digest) string {
+	return filepath.Join(s.root, dgst.Algorithm().String()+"-"+dgst.Hex())
+}
+
+// Acquire opens the partial download for dgst, creating an empty one if
+// there is none. The file is positioned at its beginning. The caller must
+// call either Release or Discard on the returned PartialDownload.
+func (s *PartialDownloadStore) Acquire(dgst digest.Digest) (*PartialDownload, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inUse[dgst]; ok {
+		return nil, ErrPartialDownloadInUse
+	}
+	f, err := os.OpenFile(s.path(dgst), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.inUse[dgst] = struct{}{}
+	return &PartialDownload{File: f, store: s, dgst: dgst}, nil
+}
+
+func (s *PartialDownloadStore) release(dgst digest.Digest) {
+	s.mu.Lock()
+	delete(s.inUse, dgst)
+	s.mu.Unlock()
+}
+
+// Prune removes partial downloads which have not been written to for
+// longer than maxAge and are not in use.
+func (s *PartialDownloadStore) Prune(maxAge time.Duration) error {
+	files, err := ioutil.ReadDir(s.root)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, f := range files {
+		if time.Since(f.ModTime()) < maxAge {
+			continue
+		}
+		inUse := false
+		for dgst := range s.inUse {
+			if filepath.Base(s.path(dgst)) == f.Name() {
+				inUse = true
+				break
+			}
+		}
+		if inUse {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.root, f.Name())); err != nil {
+			logrus.Errorf("Failed to remove stale partial download %s: %v", f.Name(), err)
+		}
+	}
+	return nil
+}
+
+// PartialDownload is the on-disk data of a download held by a single
+// transfer.
+type PartialDownload struct {
+	*os.File
+	store *PartialDownloadStore
+	dgst  digest.Digest
+}
+
+// Release closes the partial download and keeps its data for a later
+// download of the same content.
+func (pd *PartialDownload) Release() error {
+	defer pd.store.release(pd.dgst)
+	return pd.File.Close()
+}
+
+// Discard closes and removes the partial download.
+func (pd *PartialDownload) Discard() error {
+	defer pd.store.release(pd.dgst)
+	pd.File.Close()
+	return os.RemoveAll(pd.File.Name())
+}
@@ -0,0 +1,148 @@
+package xfer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/digest"
+)
+
+func TestPartialDownloadStoreResume(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-downloads-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	store, err := NewPartialDownloadStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dgst := digest.FromBytes([]byte("partial"))
+
+	partial, err := store.Acquire(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Acquire(dgst); err != ErrPartialDownloadInUse {
+		t.Fatalf("expected ErrPartialDownloadInUse, got %v", err)
+	}
+	if _, err := partial.Write([]byte("part")); err != nil {
+		t.Fatal(err)
+	}
+	if err := partial.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later download gets the data written so far.
+	partial, err = store.Acquire(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "part" {
+		t.Fatalf("expected partial download to contain %q, got %q", "part", data)
+	}
+	name := partial.Name()
+	if err := partial.Discard(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("expected discarded partial download to be removed, got %v", err)
+	}
+
+	partial, err = store.Acquire(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer partial.Discard()
+	data, err = ioutil.ReadAll(partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("expected a new empty partial download, got %q", data)
+	}
+}
+
+func TestPartialDownloadStoreInvalidDigest(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-downloads-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	store, err := NewPartialDownloadStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Acquire(digest.Digest("sha256:../../etc/passwd")); err == nil {
+		t.Fatal("expected an error acquiring a partial download for an invalid digest")
+	}
+}
+
+func TestPartialDownloadStorePrune(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-downloads-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	store, err := NewPartialDownloadStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale := digest.FromBytes([]byte("stale"))
+	inUse := digest.FromBytes([]byte("in use"))
+	fresh := digest.FromBytes([]byte("fresh"))
+	for _, dgst := range []digest.Digest{stale, inUse, fresh} {
+		partial, err := store.Acquire(dgst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := partial.Release(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	for _, dgst := range []digest.Digest{stale, inUse} {
+		if err := os.Chtimes(store.path(dgst), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	partial, err := store.Acquire(inUse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer partial.Release()
+
+	if err := store.Prune(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining := make(map[string]bool)
+	for _, f := range files {
+		remaining[f.Name()] = true
+	}
+	if remaining[filepath.Base(store.path(stale))] {
+		t.Fatal("expected stale partial download to be pruned")
+	}
+	if !remaining[filepath.Base(store.path(inUse))] {
+		t.Fatal("expected partial download in use to be kept")
+	}
+	if !remaining[filepath.Base(store.path(fresh))] {
+		t.Fatal("expected recent partial download to be kept")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/ioutils"
 )
 
@@ -235,6 +236,37 @@ func (fms *fileMetadataStore) TarSplitReader(layer ChainID) (io.ReadCloser, erro
 	}), nil
 }
 
+func (fms *fileMetadataStore) SetRemapIDs(layer ChainID, remapIDs []string) error {
+	if len(remapIDs) == 0 {
+		if err := os.Remove(fms.getLayerFilename(layer, "remap-ids")); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	content, err := json.Marshal(remapIDs)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(fms.getLayerFilename(layer, "remap-ids"), content, 0644)
+}
+
+func (fms *fileMetadataStore) GetRemapIDs(layer ChainID) ([]string, error) {
+	content, err := ioutil.ReadFile(fms.getLayerFilename(layer, "remap-ids"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var remapIDs []string
+	if err := json.Unmarshal(content, &remapIDs); err != nil {
+		return nil, err
+	}
+
+	return remapIDs, nil
+}
+
 func (fms *fileMetadataStore) SetMountID(mount string, mountID string) error {
 	if err := os.MkdirAll(fms.getMountDirectory(mount), 0755); err != nil {
 		return err
@@ -256,6 +288,23 @@ func (fms *fileMetadataStore) SetMountParent(mount string, parent ChainID) error
 	return ioutil.WriteFile(fms.getMountFilename(mount, "parent"), []byte(digest.Digest(parent).String()), 0644)
 }
 
+// mountIDMaps is the content of the "idmaps" file of a mount.
+type mountIDMaps struct {
+	UIDMaps []idtools.IDMap
+	GIDMaps []idtools.IDMap
+}
+
+func (fms *fileMetadataStore) SetMountIDMaps(mount string, uidMaps, gidMaps []idtools.IDMap) error {
+	if err := os.MkdirAll(fms.getMountDirectory(mount), 0755); err != nil {
+		return err
+	}
+	content, err := json.Marshal(mountIDMaps{UIDMaps: uidMaps, GIDMaps: gidMaps})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fms.getMountFilename(mount, "idmaps"), content, 0644)
+}
+
 func (fms *fileMetadataStore) GetMountID(mount string) (string, error) {
 	contentBytes, err := ioutil.ReadFile(fms.getMountFilename(mount, "mount-id"))
 	if err != nil {
@@ -304,6 +353,23 @@ func (fms *fileMetadataStore) GetMountParent(mount string) (ChainID, error) {
 	return ChainID(dgst), nil
 }
 
+func (fms *fileMetadataStore) GetMountIDMaps(mount string) ([]idtools.IDMap, []idtools.IDMap, error) {
+	content, err := ioutil.ReadFile(fms.getMountFilename(mount, "idmaps"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	var maps mountIDMaps
+	if err := json.Unmarshal(content, &maps); err != nil {
+		return nil, nil, err
+	}
+
+	return maps.UIDMaps, maps.GIDMaps, nil
+}
+
 func (fms *fileMetadataStore) List() ([]ChainID, []string, error) {
 	var ids []ChainID
 	for _, algorithm := range supportedAlgorithms {
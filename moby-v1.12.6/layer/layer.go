@@ -17,6 +17,7 @@ import (
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/digest"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
 )
 
 var (
@@ -165,6 +166,20 @@ type Metadata struct {
 // RWLayer.
 type MountInit func(root string) error
 
+// CreateRWLayerOpts contains optional arguments to be passed to CreateRWLayer
+type CreateRWLayerOpts struct {
+	MountLabel string
+	InitFunc   MountInit
+	StorageOpt map[string]string
+
+	// UIDMaps and GIDMaps are the user namespace mappings of a read-write
+	// layer using its own range of IDs. The files of its parent layers are
+	// shifted into this range in a layer shared by all the read-write
+	// layers using the same parent and mappings.
+	UIDMaps []idtools.IDMap
+	GIDMaps []idtools.IDMap
+}
+
 // Store represents a backend for managing both
 // read-only and read-write layers.
 type Store interface {
@@ -172,7 +187,7 @@ type Store interface {
 	Get(ChainID) (Layer, error)
 	Release(Layer) ([]Metadata, error)
 
-	CreateRWLayer(id string, parent ChainID, mountLabel string, initFunc MountInit, storageOpt map[string]string) (RWLayer, error)
+	CreateRWLayer(id string, parent ChainID, opts *CreateRWLayerOpts) (RWLayer, error)
 	GetRWLayer(id string) (RWLayer, error)
 	GetMountID(id string) (string, error)
 	ReleaseRWLayer(RWLayer) ([]Metadata, error)
@@ -218,13 +233,20 @@ type MetadataStore interface {
 	GetDescriptor(ChainID) (distribution.Descriptor, error)
 	TarSplitReader(ChainID) (io.ReadCloser, error)
 
+	// SetRemapIDs records the remap layers kept on top of a layer, and
+	// GetRemapIDs returns them.
+	SetRemapIDs(ChainID, []string) error
+	GetRemapIDs(ChainID) ([]string, error)
+
 	SetMountID(string, string) error
 	SetInitID(string, string) error
 	SetMountParent(string, ChainID) error
+	SetMountIDMaps(string, []idtools.IDMap, []idtools.IDMap) error
 
 	GetMountID(string) (string, error)
 	GetInitID(string) (string, error)
 	GetMountParent(string) (ChainID, error)
+	GetMountIDMaps(string) ([]idtools.IDMap, []idtools.IDMap, error)
 
 	// List returns the full list of referenced
 	// read-only and read-write layers
@@ -177,10 +177,17 @@ func (ls *layerStore) loadMount(mount string) error {
 		return err
 	}
 
+	uidMaps, gidMaps, err := ls.store.GetMountIDMaps(mount)
+	if err != nil {
+		return err
+	}
+
 	ml := &mountedLayer{
 		name:       mount,
 		mountID:    mountID,
 		initID:     initID,
+		uidMaps:    uidMaps,
+		gidMaps:    gidMaps,
 		layerStore: ls,
 		references: map[RWLayer]*referencedRWLayer{},
 	}
@@ -193,6 +200,10 @@ func (ls *layerStore) loadMount(mount string) error {
 		ml.parent = p
 
 		p.referenceCount++
+
+		if len(uidMaps) > 0 || len(gidMaps) > 0 {
+			ml.remapID = remapLayerID(p.cacheID, uidMaps, gidMaps)
+		}
 	}
 
 	ls.mounts[ml.name] = ml
@@ -358,7 +369,12 @@ func (ls *layerStore) Get(l ChainID) (Layer, error) {
 }
 
 func (ls *layerStore) deleteLayer(layer *roLayer, metadata *Metadata) error {
-	err := ls.driver.Remove(layer.cacheID)
+	err := ls.removeRemapLayers(layer)
+	if err != nil {
+		return err
+	}
+
+	err = ls.driver.Remove(layer.cacheID)
 	if err != nil {
 		return err
 	}
@@ -429,7 +445,23 @@ func (ls *layerStore) Release(l Layer) ([]Metadata, error) {
 	return ls.releaseLayer(layer)
 }
 
-func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel string, initFunc MountInit, storageOpt map[string]string) (RWLayer, error) {
+func (ls *layerStore) CreateRWLayer(name string, parent ChainID, opts *CreateRWLayerOpts) (RWLayer, error) {
+	var (
+		mountLabel string
+		initFunc   MountInit
+		storageOpt map[string]string
+		uidMaps    []idtools.IDMap
+		gidMaps    []idtools.IDMap
+	)
+
+	if opts != nil {
+		mountLabel = opts.MountLabel
+		initFunc = opts.InitFunc
+		storageOpt = opts.StorageOpt
+		uidMaps = opts.UIDMaps
+		gidMaps = opts.GIDMaps
+	}
+
 	ls.mountL.Lock()
 	defer ls.mountL.Unlock()
 	m, ok := ls.mounts[name]
@@ -461,10 +493,20 @@ func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel stri
 		name:       name,
 		parent:     p,
 		mountID:    ls.mountID(name),
+		uidMaps:    uidMaps,
+		gidMaps:    gidMaps,
 		layerStore: ls,
 		references: map[RWLayer]*referencedRWLayer{},
 	}
 
+	if p != nil && (len(uidMaps) > 0 || len(gidMaps) > 0) {
+		m.remapID = remapLayerID(p.cacheID, uidMaps, gidMaps)
+		if err = ls.initRemapLayer(m.remapID, p, mountLabel, uidMaps, gidMaps); err != nil {
+			return nil, err
+		}
+		pid = m.remapID
+	}
+
 	if initFunc != nil {
 		pid, err = ls.initMount(m.mountID, pid, mountLabel, initFunc, storageOpt)
 		if err != nil {
@@ -571,6 +613,12 @@ func (ls *layerStore) saveMount(mount *mountedLayer) error {
 		}
 	}
 
+	if len(mount.uidMaps) > 0 || len(mount.gidMaps) > 0 {
+		if err := ls.store.SetMountIDMaps(mount.name, mount.uidMaps, mount.gidMaps); err != nil {
+			return err
+		}
+	}
+
 	ls.mounts[mount.name] = mount
 
 	return nil
@@ -4,12 +4,16 @@ import (
 	"io"
 
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
 )
 
 type mountedLayer struct {
 	name       string
 	mountID    string
 	initID     string
+	remapID    string
+	uidMaps    []idtools.IDMap
+	gidMaps    []idtools.IDMap
 	parent     *roLayer
 	path       string
 	layerStore *layerStore
@@ -21,6 +25,9 @@ func (ml *mountedLayer) cacheParent() string {
 	if ml.initID != "" {
 		return ml.initID
 	}
+	if ml.remapID != "" {
+		return ml.remapID
+	}
 	if ml.parent != nil {
 		return ml.parent.cacheID
 	}
@@ -32,6 +39,10 @@ func (ml *mountedLayer) TarStream() (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(ml.uidMaps) > 0 || len(ml.gidMaps) > 0 {
+		// Shift the IDs of the files back from the range of the layer.
+		return unmapTarStream(archiver, ml.uidMaps, ml.gidMaps), nil
+	}
 	return archiver, nil
 }
 
@@ -0,0 +1,156 @@
+package layer
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/pkg/idtools"
+)
+
+// remapLayerID returns the ID of the layer holding the files of the layer
+// cacheID shifted into the range of the given mappings.
+func remapLayerID(cacheID string, uidMaps, gidMaps []idtools.IDMap) string {
+	maps, _ := json.Marshal([][]idtools.IDMap{uidMaps, gidMaps})
+	return fmt.Sprintf("%s-remap-%s", cacheID, digest.FromBytes(maps).Hex()[:12])
+}
+
+// remapLayerInUse returns true if a read-write layer uses the remap layer
+// remapID. It must be called with mountL held.
+func (ls *layerStore) remapLayerInUse(remapID string) bool {
+	for _, m := range ls.mounts {
+		if m.remapID == remapID {
+			return true
+		}
+	}
+	return false
+}
+
+// initRemapLayer creates the remap layer remapID on top of parent and
+// shifts the IDs of its files into the range of the given mappings. Each
+// file of the parent chain is copied by the shift, so the remap layer is
+// kept once shifted, and reused by later read-write layers on the same
+// parent and range until the parent is removed. It must be called with
+// mountL held.
+func (ls *layerStore) initRemapLayer(remapID string, parent *roLayer, mountLabel string, uidMaps, gidMaps []idtools.IDMap) error {
+	if ls.remapLayerInUse(remapID) {
+		return nil
+	}
+
+	remapIDs, err := ls.store.GetRemapIDs(parent.chainID)
+	if err != nil {
+		return err
+	}
+	for _, id := range remapIDs {
+		if id == remapID && ls.driver.Exists(remapID) {
+			return nil
+		}
+	}
+
+	// A remap layer which is not recorded on its parent is left over from
+	// an interrupted shift, and may only be partly shifted.
+	if ls.driver.Exists(remapID) {
+		if err := ls.driver.Remove(remapID); err != nil {
+			return err
+		}
+	}
+
+	if err := ls.driver.Create(remapID, parent.cacheID, mountLabel, nil); err != nil {
+		return err
+	}
+	root, err := ls.driver.Get(remapID, "")
+	if err != nil {
+		ls.driver.Remove(remapID)
+		return err
+	}
+
+	logrus.Debugf("Shifting the IDs of layer %s into a user namespace range", parent.cacheID)
+	err = shiftIDs(root, uidMaps, gidMaps)
+	if putErr := ls.driver.Put(remapID); err == nil {
+		err = putErr
+	}
+	if err == nil {
+		err = ls.store.SetRemapIDs(parent.chainID, appendRemapID(remapIDs, remapID))
+	}
+	if err != nil {
+		ls.driver.Remove(remapID)
+		return fmt.Errorf("error shifting the IDs of layer %s: %v", parent.cacheID, err)
+	}
+	return nil
+}
+
+// appendRemapID returns remapIDs with remapID added once.
+func appendRemapID(remapIDs []string, remapID string) []string {
+	for _, id := range remapIDs {
+		if id == remapID {
+			return remapIDs
+		}
+	}
+	return append(remapIDs, remapID)
+}
+
+// removeRemapLayers removes the remap layers kept on top of layer. It is
+// called when layer is deleted, once no read-write layer can use them.
+func (ls *layerStore) removeRemapLayers(layer *roLayer) error {
+	remapIDs, err := ls.store.GetRemapIDs(layer.chainID)
+	if err != nil {
+		return err
+	}
+	for _, remapID := range remapIDs {
+		if !ls.driver.Exists(remapID) {
+			continue
+		}
+		if err := ls.driver.Remove(remapID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toContainerID returns the ID in the container of the host ID id, or id
+// itself if it is outside of the mappings.
+func toContainerID(id int, idMaps []idtools.IDMap) int {
+	if len(idMaps) == 0 {
+		return id
+	}
+	contID, err := idtools.ToContainer(id, idMaps)
+	if err != nil {
+		return id
+	}
+	return contID
+}
+
+// unmapTarStream returns a tar stream with the entries of ts, with their
+// owners shifted back from the range of the given mappings.
+func unmapTarStream(ts io.ReadCloser, uidMaps, gidMaps []idtools.IDMap) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		tr := tar.NewReader(ts)
+		tw := tar.NewWriter(pw)
+		err := func() error {
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					return tw.Close()
+				}
+				if err != nil {
+					return err
+				}
+				hdr.Uid = toContainerID(hdr.Uid, uidMaps)
+				hdr.Gid = toContainerID(hdr.Gid, gidMaps)
+				if err := tw.WriteHeader(hdr); err != nil {
+					return err
+				}
+				if _, err := io.Copy(tw, tr); err != nil {
+					return err
+				}
+			}
+		}()
+		ts.Close()
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
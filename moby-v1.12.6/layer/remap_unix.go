@@ -0,0 +1,73 @@
+// +build linux freebsd darwin openbsd solaris
+
+package layer
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/system"
+)
+
+// shiftIDs changes the owners of the files under root from IDs in the
+// container to the host IDs of the given mappings. File capabilities and
+// setuid and setgid bits, which chown clears, are kept.
+func shiftIDs(root string, uidMaps, gidMaps []idtools.IDMap) error {
+	type inode struct {
+		dev uint64
+		ino uint64
+	}
+	shifted := make(map[inode]struct{})
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return nil
+		}
+
+		// Hard links share their owner, shift it once.
+		if st.Nlink > 1 {
+			i := inode{dev: uint64(st.Dev), ino: uint64(st.Ino)}
+			if _, ok := shifted[i]; ok {
+				return nil
+			}
+			shifted[i] = struct{}{}
+		}
+
+		uid, gid := int(st.Uid), int(st.Gid)
+		if len(uidMaps) > 0 {
+			if uid, err = idtools.ToHost(uid, uidMaps); err != nil {
+				return err
+			}
+		}
+		if len(gidMaps) > 0 {
+			if gid, err = idtools.ToHost(gid, gidMaps); err != nil {
+				return err
+			}
+		}
+
+		capability, _ := system.Lgetxattr(path, "security.capability")
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		if info.Mode()&(os.ModeSetuid|os.ModeSetgid) != 0 {
+			if err := os.Chmod(path, info.Mode()); err != nil {
+				return err
+			}
+		}
+		if capability != nil {
+			if err := system.Lsetxattr(path, "security.capability", capability, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
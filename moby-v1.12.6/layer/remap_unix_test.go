@@ -0,0 +1,141 @@
+// +build linux freebsd
+
+package layer
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/pkg/idtools"
+)
+
+func TestRemappedRWLayer(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing the owner of files requires root")
+	}
+
+	// The graph driver of the daemon has no mappings when ranges of the
+	// user namespace pool are in use.
+	td, err := ioutil.TempDir("", "remap-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(td)
+	driver, err := graphdriver.GetDriver("vfs", filepath.Join(td, "graph"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Cleanup()
+	fms, err := NewFSMetadataStore(filepath.Join(td, "layers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := NewStoreFromGraphDriver(fms, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layer, err := createLayer(ls, "", initWithFiles(newTestFile("file1", []byte("Base contents"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idMaps := []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}}
+	opts := &CreateRWLayerOpts{UIDMaps: idMaps, GIDMaps: idMaps}
+	m1, err := ls.CreateRWLayer("remap-mount1", layer.ChainID(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := ls.CreateRWLayer("remap-mount2", layer.ChainID(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remapID := getMountLayer(m1).remapID
+	if remapID == "" || remapID != getMountLayer(m2).remapID {
+		t.Fatalf("expected both mounts to share a remap layer, got %q and %q", remapID, getMountLayer(m2).remapID)
+	}
+
+	path, err := m1.Mount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filepath.Join(path, "file1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := fi.Sys().(*syscall.Stat_t); st.Uid != 100000 || st.Gid != 100000 {
+		t.Fatalf("expected file1 to be owned by 100000:100000, got %d:%d", st.Uid, st.Gid)
+	}
+	if err := ioutil.WriteFile(filepath.Join(path, "file2"), []byte("Added contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Lchown(filepath.Join(path, "file2"), 100001, 100001); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := m1.TarStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(ts)
+	found := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name == "file2" {
+			found = true
+			if hdr.Uid != 1 || hdr.Gid != 1 {
+				t.Fatalf("expected file2 to be owned by 1:1 in the diff, got %d:%d", hdr.Uid, hdr.Gid)
+			}
+		}
+	}
+	ts.Close()
+	if !found {
+		t.Fatal("expected file2 in the diff")
+	}
+	if err := m1.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ls.ReleaseRWLayer(m1); err != nil {
+		t.Fatal(err)
+	}
+	if !driver.Exists(remapID) {
+		t.Fatal("expected the remap layer to be kept while a mount uses it")
+	}
+	if _, err := ls.ReleaseRWLayer(m2); err != nil {
+		t.Fatal(err)
+	}
+	if !driver.Exists(remapID) {
+		t.Fatal("expected the remap layer to be kept until its parent is removed")
+	}
+
+	m3, err := ls.CreateRWLayer("remap-mount3", layer.ChainID(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getMountLayer(m3).remapID != remapID {
+		t.Fatalf("expected the remap layer %q to be reused, got %q", remapID, getMountLayer(m3).remapID)
+	}
+	if _, err := ls.ReleaseRWLayer(m3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ls.Release(layer); err != nil {
+		t.Fatal(err)
+	}
+	if driver.Exists(remapID) {
+		t.Fatal("expected the remap layer to be removed with its parent")
+	}
+}
@@ -0,0 +1,12 @@
+package layer
+
+import (
+	"errors"
+
+	"github.com/docker/docker/pkg/idtools"
+)
+
+// shiftIDs is not supported on Windows, which has no user namespaces.
+func shiftIDs(root string, uidMaps, gidMaps []idtools.IDMap) error {
+	return errors.New("user namespace mappings are not supported on Windows")
+}
@@ -97,6 +97,8 @@ const (
 	Gzip
 	// Xz is xz compression algorithm.
 	Xz
+	// Zstd is zstd compression algorithm.
+	Zstd
 )
 
 const (
@@ -142,6 +144,7 @@ func DetectCompression(source []byte) Compression {
 		Bzip2: {0x42, 0x5A, 0x68},
 		Gzip:  {0x1F, 0x8B, 0x08},
 		Xz:    {0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00},
+		Zstd:  {0x28, 0xB5, 0x2F, 0xFD},
 	} {
 		if len(source) < len(m) {
 			logrus.Debug("Len too short")
@@ -154,12 +157,70 @@ func DetectCompression(source []byte) Compression {
 	return Uncompressed
 }
 
+// unpigzPath is the path of the unpigz binary used to decompress gzip
+// streams, or empty if gzip streams are decompressed in process. Setting
+// DOCKER_DISABLE_PIGZ disables the use of unpigz.
+var unpigzPath string
+
+func init() {
+	if os.Getenv("DOCKER_DISABLE_PIGZ") != "" {
+		return
+	}
+	if path, err := exec.LookPath("unpigz"); err == nil {
+		unpigzPath = path
+	}
+}
+
+// gzDecompress returns a reader of the decompressed gzip stream. unpigz is
+// used when it is available, as it reads, decompresses and checksums the
+// stream in separate threads, which is considerably faster for large layers
+// than decompressing in process.
+func gzDecompress(archive io.Reader) (io.ReadCloser, error) {
+	if unpigzPath == "" {
+		gzReader, err := gzip.NewReader(archive)
+		if err != nil {
+			return nil, err
+		}
+		return gzReader, nil
+	}
+
+	cmd := exec.Command(unpigzPath, "-d", "-c")
+	pipeR, chdone, err := cmdStream(cmd, archive)
+	if err != nil {
+		return nil, err
+	}
+	return ioutils.NewReadCloserWrapper(pipeR, func() error {
+		pipeR.Close()
+		select {
+		case <-chdone:
+		default:
+			// The stream was not read to the end, unpigz is blocked
+			// writing to the closed pipe.
+			cmd.Process.Kill()
+			<-chdone
+		}
+		return nil
+	}), nil
+}
+
 func xzDecompress(archive io.Reader) (io.ReadCloser, <-chan struct{}, error) {
 	args := []string{"xz", "-d", "-c", "-q"}
 
 	return cmdStream(exec.Command(args[0], args[1:]...), archive)
 }
 
+func zstdDecompress(archive io.Reader) (io.ReadCloser, <-chan struct{}, error) {
+	args := []string{"zstd", "-d", "-c", "-q"}
+
+	return cmdStream(exec.Command(args[0], args[1:]...), archive)
+}
+
+func zstdCompress(dest io.Writer) (io.WriteCloser, error) {
+	args := []string{"zstd", "-c", "-q"}
+
+	return cmdWriteStream(exec.Command(args[0], args[1:]...), dest)
+}
+
 // DecompressStream decompresses the archive and returns a ReaderCloser with the decompressed archive.
 func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 	p := pools.BufioReader32KPool
@@ -181,7 +242,7 @@ func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 		readBufWrapper := p.NewReadCloserWrapper(buf, buf)
 		return readBufWrapper, nil
 	case Gzip:
-		gzReader, err := gzip.NewReader(buf)
+		gzReader, err := gzDecompress(buf)
 		if err != nil {
 			return nil, err
 		}
@@ -201,6 +262,16 @@ func DecompressStream(archive io.Reader) (io.ReadCloser, error) {
 			<-chdone
 			return readBufWrapper.Close()
 		}), nil
+	case Zstd:
+		zstdReader, chdone, err := zstdDecompress(buf)
+		if err != nil {
+			return nil, err
+		}
+		readBufWrapper := p.NewReadCloserWrapper(buf, zstdReader)
+		return ioutils.NewReadCloserWrapper(readBufWrapper, func() error {
+			<-chdone
+			return readBufWrapper.Close()
+		}), nil
 	default:
 		return nil, fmt.Errorf("Unsupported compression format %s", (&compression).Extension())
 	}
@@ -218,9 +289,16 @@ func CompressStream(dest io.Writer, compression Compression) (io.WriteCloser, er
 		gzWriter := gzip.NewWriter(dest)
 		writeBufWrapper := p.NewWriteCloserWrapper(buf, gzWriter)
 		return writeBufWrapper, nil
+	case Zstd:
+		zstdWriter, err := zstdCompress(dest)
+		if err != nil {
+			return nil, err
+		}
+		writeBufWrapper := p.NewWriteCloserWrapper(buf, zstdWriter)
+		return writeBufWrapper, nil
 	case Bzip2, Xz:
 		// archive/bzip2 does not support writing, and there is no xz support at all
-		// However, this is not a problem as docker only currently generates gzipped tars
+		// However, this is not a problem as docker only currently generates gzipped or zstd tars
 		return nil, fmt.Errorf("Unsupported compression format %s", (&compression).Extension())
 	default:
 		return nil, fmt.Errorf("Unsupported compression format %s", (&compression).Extension())
@@ -238,6 +316,8 @@ func (compression *Compression) Extension() string {
 		return "tar.gz"
 	case Xz:
 		return "tar.xz"
+	case Zstd:
+		return "tar.zst"
 	}
 	return ""
 }
@@ -857,7 +937,7 @@ loop:
 // Untar reads a stream of bytes from `archive`, parses it as a tar archive,
 // and unpacks it into the directory at `dest`.
 // The archive may be compressed with one of the following algorithms:
-//  identity (uncompressed), gzip, bzip2, xz.
+//  identity (uncompressed), gzip, bzip2, xz, zstd.
 // FIXME: specify behavior when target path exists vs. doesn't exist.
 func Untar(tarArchive io.Reader, dest string, options *TarOptions) error {
 	return untarHandler(tarArchive, dest, options, true)
@@ -1109,6 +1189,48 @@ func cmdStream(cmd *exec.Cmd, input io.Reader) (io.ReadCloser, <-chan struct{},
 	return pipeR, chdone, nil
 }
 
+// cmdWriteStream executes a command, and returns its stdin as a stream.
+// The command's stdout is written to output. Closing the stream waits for
+// the command to exit, and returns its error if it failed. The command is
+// killed when writing to output fails, so that it does not block on its
+// stdout while the stream is closed.
+func cmdWriteStream(cmd *exec.Cmd, output io.Writer) (io.WriteCloser, error) {
+	cmd.Stdout = &killOnErrorWriter{cmd: cmd, w: output}
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return ioutils.NewWriteCloserWrapper(stdin, func() error {
+		stdin.Close()
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("%s: %s", err, errBuf.String())
+		}
+		return nil
+	}), nil
+}
+
+// killOnErrorWriter writes the stdout of a command, and kills the command
+// when a write fails.
+type killOnErrorWriter struct {
+	cmd *exec.Cmd
+	w   io.Writer
+}
+
+func (w *killOnErrorWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.cmd.Process.Kill()
+	}
+	return n, err
+}
+
 // NewTempArchive reads the content of src into a temporary file, and returns the contents
 // of that file as an archive. The archive can only be read once - as soon as reading completes,
 // the file will be deleted.
@@ -3,6 +3,7 @@ package archive
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -107,6 +108,65 @@ func TestDecompressStreamGzip(t *testing.T) {
 	}
 }
 
+func TestDecompressStreamGzipUnpigz(t *testing.T) {
+	if _, err := exec.LookPath("gzip"); err != nil {
+		t.Skip("gzip not found")
+	}
+	dir, err := ioutil.TempDir("", "docker-test-unpigz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Stand in for unpigz, which takes the same arguments as gzip.
+	fakeUnpigz := filepath.Join(dir, "unpigz")
+	if err := ioutil.WriteFile(fakeUnpigz, []byte("#!/bin/sh\nexec gzip \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { unpigzPath = path }(unpigzPath)
+	unpigzPath = fakeUnpigz
+
+	data := bytes.Repeat([]byte("docker"), 1<<16)
+	var compressed bytes.Buffer
+	gzWriter := gzip.NewWriter(&compressed)
+	if _, err := gzWriter.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	gzWriter.Close()
+
+	rc, err := DecompressStream(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, data) {
+		t.Fatal("decompressed data does not match the original data")
+	}
+
+	// Closing the stream before reading it to the end must not block.
+	rc, err = DecompressStream(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rc.Read(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		rc.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("closing a partially read stream timed out")
+	}
+}
+
 func TestDecompressStreamBzip2(t *testing.T) {
 	cmd := exec.Command("sh", "-c", "touch /tmp/archive && bzip2 -f /tmp/archive")
 	output, err := cmd.CombinedOutput()
@@ -136,6 +196,43 @@ func TestDecompressStreamXz(t *testing.T) {
 	}
 }
 
+func TestCompressDecompressStreamZstd(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd not found")
+	}
+	data := bytes.Repeat([]byte("docker"), 1<<16)
+
+	var compressed bytes.Buffer
+	w, err := CompressStream(&compressed, Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if c := DetectCompression(compressed.Bytes()); c != Zstd {
+		t.Fatalf("expected zstd compression to be detected, got %s", (&c).Extension())
+	}
+
+	rc, err := DecompressStream(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, data) {
+		t.Fatal("decompressed data does not match the original data")
+	}
+}
+
 func TestCompressStreamXzUnsuported(t *testing.T) {
 	dest, err := os.Create(tmp + "dest")
 	if err != nil {
@@ -259,6 +356,33 @@ func TestCmdStreamGood(t *testing.T) {
 	}
 }
 
+type errorWriter struct{}
+
+func (errorWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("write failed")
+}
+
+func TestCmdWriteStreamOutputError(t *testing.T) {
+	in, err := cmdWriteStream(exec.Command("cat"), errorWriter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errCh := make(chan error)
+	go func() {
+		// The writes fail once the command is killed.
+		io.Copy(in, bytes.NewReader(make([]byte, 1<<20)))
+		errCh <- in.Close()
+	}()
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("Closing the stream should have failed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Closing the stream did not complete in 5 seconds; probable deadlock")
+	}
+}
+
 func TestUntarPathWithInvalidDest(t *testing.T) {
 	tempFolder, err := ioutil.TempDir("", "docker-archive-test")
 	if err != nil {
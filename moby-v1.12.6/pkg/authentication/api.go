@@ -0,0 +1,45 @@
+package authentication
+
+const (
+	// AuthNApiRequest is the url for daemon request authentication
+	AuthNApiRequest = "AuthNPlugin.Authenticate"
+
+	// AuthNApiImplements is the name of the interface all AuthN plugins implement
+	AuthNApiImplements = "authn"
+)
+
+// Request holds data required for authN plugins
+type Request struct {
+	// RequestMethod holds the HTTP method (GET/POST/PUT)
+	RequestMethod string `json:"RequestMethod,omitempty"`
+
+	// RequestUri holds the full HTTP uri (e.g., /v1.21/version)
+	RequestURI string `json:"RequestUri,omitempty"`
+
+	// RequestHeaders stores the raw request headers sent to the docker daemon,
+	// including the Authorization header
+	RequestHeaders map[string]string `json:"RequestHeaders,omitempty"`
+
+	// TLSCommonName holds the common name of the TLS client certificate, if any
+	TLSCommonName string `json:"TLSCommonName,omitempty"`
+}
+
+// Response represents authN plugin response
+type Response struct {
+	// Authenticated indicates whether the plugin recognized the user
+	Authenticated bool `json:"Authenticated"`
+
+	// User holds the name of the authenticated user
+	User string `json:"User,omitempty"`
+
+	// Groups holds the groups the authenticated user belongs to
+	Groups []string `json:"Groups,omitempty"`
+
+	// Msg stores the reason the request is rejected. When a plugin does not
+	// authenticate a request and sets no message, the next authenticator
+	// is tried.
+	Msg string `json:"Msg,omitempty"`
+
+	// Err stores a message in case there's an error
+	Err string `json:"Err,omitempty"`
+}
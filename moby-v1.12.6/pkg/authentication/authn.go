@@ -0,0 +1,60 @@
+package authentication
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// User is a user of the docker API, as identified by an Authenticator.
+type User struct {
+	// Name is the name of the user
+	Name string
+
+	// Groups are the groups the user belongs to
+	Groups []string
+
+	// AuthNMethod is the mechanism used to identify the user (e.g., TLS,
+	// token, htpasswd or the name of an authentication plugin)
+	AuthNMethod string
+}
+
+// Authenticator identifies the user making an API request.
+type Authenticator interface {
+	// Name returns the name of the authenticator
+	Name() string
+
+	// Authenticate returns the user making the request. It returns a nil
+	// user if the request carries no credentials the authenticator
+	// recognizes, and an error if it carries credentials which are invalid.
+	Authenticate(r *http.Request) (*User, error)
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx holding the authenticated user.
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the authenticated user held by ctx, or nil if the
+// request was not authenticated.
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userKey{}).(*User)
+	return user
+}
+
+// authenticationError represents an authentication failure
+type authenticationError struct {
+	error
+}
+
+// HTTPErrorStatusCode returns the authentication error status code (unauthorized)
+func (e authenticationError) HTTPErrorStatusCode() int {
+	return http.StatusUnauthorized
+}
+
+func newAuthenticationError(authenticator, msg string) authenticationError {
+	return authenticationError{error: fmt.Errorf("authentication failed with %s: %s", authenticator, msg)}
+}
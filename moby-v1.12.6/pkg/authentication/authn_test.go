@@ -0,0 +1,153 @@
+package authentication
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApr1(t *testing.T) {
+	// Generated with `openssl passwd -apr1 -salt saltsalt secret`
+	expected := "$apr1$saltsalt$LrttParrLPdxvgutaSXWJ0"
+	if hash := apr1("secret", "saltsalt"); hash != expected {
+		t.Fatalf("expected %s, got %s", expected, hash)
+	}
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authn-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeTestFile(t, dir, "tokens", "# tokens\nsecret1 alice dev,ops\n\nsecret2 bob\n")
+	a, err := NewTokenAuthenticator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := http.NewRequest("GET", "/info", nil)
+	if user, err := a.Authenticate(r); user != nil || err != nil {
+		t.Fatalf("expected request without a token to be ignored, got %v, %v", user, err)
+	}
+
+	r.Header.Set("Authorization", "Bearer secret1")
+	user, err := a.Authenticate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &User{Name: "alice", Groups: []string{"dev", "ops"}, AuthNMethod: "token"}
+	if !reflect.DeepEqual(user, expected) {
+		t.Fatalf("expected %v, got %v", expected, user)
+	}
+
+	r.Header.Set("Authorization", "Bearer wrong")
+	if _, err := a.Authenticate(r); err == nil {
+		t.Fatal("expected an error for an invalid token")
+	}
+
+	path = writeTestFile(t, dir, "invalid", "secret1\n")
+	if _, err := NewTokenAuthenticator(path); err == nil {
+		t.Fatal("expected an error for a token without user")
+	}
+}
+
+func TestHtpasswdAuthenticator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authn-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// All users have the password "secret".
+	path := writeTestFile(t, dir, "htpasswd", "alice:$apr1$saltsalt$LrttParrLPdxvgutaSXWJ0\nbob:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=\ncarol:$2y$05$LfA2DUb51140zaGwqU2h5.U3aMOpCFU4GLX0GumQu7r0T/dCtXTXK\n")
+	a, err := NewHtpasswdAuthenticator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		r, _ := http.NewRequest("GET", "/info", nil)
+		r.SetBasicAuth(name, "secret")
+		user, err := a.Authenticate(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != name || user.AuthNMethod != "htpasswd" {
+			t.Fatalf("expected user %s authenticated by htpasswd, got %v", name, user)
+		}
+
+		r.SetBasicAuth(name, "wrong")
+		if _, err := a.Authenticate(r); err == nil {
+			t.Fatalf("expected an error for a wrong password of %s", name)
+		}
+	}
+
+	path = writeTestFile(t, dir, "crypt", "alice:rl0uE3KGOTQMA\n")
+	if _, err := NewHtpasswdAuthenticator(path); err == nil {
+		t.Fatal("expected an error for an unsupported hash")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authn-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := NewTokenAuthenticator(writeTestFile(t, dir, "tokens", "secret1 alice dev\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := ReadGroupFile(writeTestFile(t, dir, "groups", "admins: alice bob\ndev: alice\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMiddleware([]Authenticator{a}, groups)
+
+	var user *User
+	handler := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		user = UserFromContext(ctx)
+		return nil
+	})
+
+	r, _ := http.NewRequest("POST", "/containers/foo/start", nil)
+	r.Header.Set("Authorization", "Bearer secret1")
+	if err := handler(context.Background(), nil, r, map[string]string{"name": "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := &User{Name: "alice", Groups: []string{"dev", "admins"}, AuthNMethod: "token"}
+	if !reflect.DeepEqual(user, expected) {
+		t.Fatalf("expected %v, got %v", expected, user)
+	}
+
+	r, _ = http.NewRequest("GET", "/info", nil)
+	if err := handler(context.Background(), nil, r, nil); err != nil {
+		t.Fatal(err)
+	}
+	if user != nil {
+		t.Fatalf("expected an anonymous request, got user %v", user)
+	}
+
+	r.Header.Set("Authorization", "Bearer wrong")
+	err = handler(context.Background(), nil, r, nil)
+	if e, ok := err.(authenticationError); !ok || e.HTTPErrorStatusCode() != http.StatusUnauthorized {
+		t.Fatalf("expected an authentication error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package authentication
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadGroupFile reads the group file at path and returns the groups of each
+// user. Each line of the file holds a group name followed by a colon and the
+// whitespace-separated names of its members, as in Apache group files.
+// Empty lines and lines starting with # are ignored.
+func ReadGroupFile(path string) (map[string][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	groups := make(map[string][]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.SplitN(text, ":", 2)
+		group := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || group == "" {
+			return nil, fmt.Errorf("invalid group file %s: line %d must be <group>: <user> ...", path, line)
+		}
+		for _, user := range strings.Fields(parts[1]) {
+			groups[user] = appendGroup(groups[user], group)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// appendGroup adds group to groups unless it is already present.
+func appendGroup(groups []string, group string) []string {
+	for _, g := range groups {
+		if g == group {
+			return groups
+		}
+	}
+	return append(groups, group)
+}
@@ -0,0 +1,75 @@
+package authentication
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// Middleware uses a list of authenticators to identify the user making
+// each API request, and stores the user in the request context for the
+// middlewares and handlers which run after it.
+type Middleware struct {
+	authenticators []Authenticator
+	groups         map[string][]string
+}
+
+// NewMiddleware creates a new Middleware with a slice of authenticators,
+// which are tried in order. groups lists additional groups for each user
+// name.
+func NewMiddleware(authenticators []Authenticator, groups map[string][]string) Middleware {
+	return Middleware{
+		authenticators: authenticators,
+		groups:         groups,
+	}
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m Middleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		user, err := m.authenticate(r)
+		if err != nil {
+			logrus.Errorf("Authentication for %s %s returned error: %s", r.Method, r.RequestURI, err)
+			return err
+		}
+
+		if user != nil {
+			for _, group := range m.groups[user.Name] {
+				user.Groups = appendGroup(user.Groups, group)
+			}
+			ctx = WithUser(ctx, user)
+		}
+
+		return handler(ctx, w, r, vars)
+	}
+}
+
+// authenticate returns the user identified by the first authenticator which
+// recognizes the credentials of the request. Requests which no authenticator
+// recognizes are identified by their TLS client certificate, if any.
+func (m Middleware) authenticate(r *http.Request) (*User, error) {
+	for _, authenticator := range m.authenticators {
+		logrus.Debugf("AuthN request using %s", authenticator.Name())
+
+		user, err := authenticator.Authenticate(r)
+		if err != nil {
+			if _, ok := err.(authenticationError); ok {
+				return nil, err
+			}
+			return nil, fmt.Errorf("authenticator %s failed with error: %s", authenticator.Name(), err)
+		}
+		if user != nil {
+			return user, nil
+		}
+	}
+
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return &User{
+			Name:        r.TLS.PeerCertificates[0].Subject.CommonName,
+			AuthNMethod: "TLS",
+		}, nil
+	}
+	return nil, nil
+}
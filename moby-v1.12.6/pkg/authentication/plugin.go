@@ -0,0 +1,111 @@
+package authentication
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/pkg/plugins"
+)
+
+// NewPlugins constructs the authenticators for the authentication plugins
+// based on plugin names
+func NewPlugins(names []string) []Authenticator {
+	authenticators := []Authenticator{}
+	pluginsMap := make(map[string]struct{})
+	for _, name := range names {
+		if _, ok := pluginsMap[name]; ok {
+			continue
+		}
+		pluginsMap[name] = struct{}{}
+		authenticators = append(authenticators, newAuthenticationPlugin(name))
+	}
+	return authenticators
+}
+
+// authenticationPlugin is an internal adapter to docker plugin system
+type authenticationPlugin struct {
+	plugin *plugins.Client
+	name   string
+	once   sync.Once
+}
+
+func newAuthenticationPlugin(name string) Authenticator {
+	return &authenticationPlugin{name: name}
+}
+
+func (a *authenticationPlugin) Name() string {
+	return a.name
+}
+
+func (a *authenticationPlugin) Authenticate(r *http.Request) (*User, error) {
+	if err := a.initPlugin(); err != nil {
+		return nil, err
+	}
+
+	authReq := &Request{
+		RequestMethod:  r.Method,
+		RequestURI:     r.RequestURI,
+		RequestHeaders: headers(r.Header),
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		authReq.TLSCommonName = r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+
+	authRes := &Response{}
+	if err := a.plugin.Call(AuthNApiRequest, authReq, authRes); err != nil {
+		return nil, err
+	}
+	if authRes.Err != "" {
+		return nil, errors.New(authRes.Err)
+	}
+	if !authRes.Authenticated {
+		if authRes.Msg != "" {
+			return nil, newAuthenticationError(a.name, authRes.Msg)
+		}
+		return nil, nil
+	}
+	if authRes.User == "" {
+		return nil, newAuthenticationError(a.name, "plugin returned no user")
+	}
+
+	return &User{
+		Name:        authRes.User,
+		Groups:      authRes.Groups,
+		AuthNMethod: a.name,
+	}, nil
+}
+
+// initPlugin initializes the authentication plugin if needed
+func (a *authenticationPlugin) initPlugin() error {
+	// Lazy loading of plugins
+	var err error
+	a.once.Do(func() {
+		if a.plugin == nil {
+			plugin, e := plugins.Get(a.name, AuthNApiImplements)
+			if e != nil {
+				err = e
+				return
+			}
+			a.plugin = plugin.Client()
+		}
+	})
+	return err
+}
+
+// headers returns flatten version of the http headers excluding registry
+// credentials
+func headers(header http.Header) map[string]string {
+	v := make(map[string]string, 0)
+	for k, values := range header {
+		// Skip registry credentials, which are not meant for the daemon
+		if strings.EqualFold(k, "X-Registry-Config") || strings.EqualFold(k, "X-Registry-Auth") {
+			continue
+		}
+		for _, val := range values {
+			v[k] = val
+		}
+	}
+	return v
+}
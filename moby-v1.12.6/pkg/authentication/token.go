@@ -0,0 +1,85 @@
+package authentication
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type tokenEntry struct {
+	token  string
+	user   string
+	groups []string
+}
+
+// tokenAuthenticator identifies users by the static bearer token they pass
+// in the Authorization header.
+type tokenAuthenticator struct {
+	entries []tokenEntry
+}
+
+// NewTokenAuthenticator returns an Authenticator for the bearer tokens
+// listed in the file at path. Each line of the file holds a token, the name
+// of its user and, optionally, a comma-separated list of groups, separated
+// by whitespace. Empty lines and lines starting with # are ignored.
+func NewTokenAuthenticator(path string) (Authenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	a := &tokenAuthenticator{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid token file %s: line %d must be <token> <user> [<group>,...]", path, line)
+		}
+		entry := tokenEntry{token: fields[0], user: fields[1]}
+		if len(fields) == 3 {
+			entry.groups = strings.Split(fields[2], ",")
+		}
+		a.entries = append(a.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *tokenAuthenticator) Name() string {
+	return "token"
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < len("Bearer ") || !strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+		return nil, nil
+	}
+	token := []byte(strings.TrimSpace(auth[len("Bearer "):]))
+
+	var found *tokenEntry
+	for i := range a.entries {
+		// Compare all the tokens in constant time, so that the time taken
+		// does not reveal which token matched.
+		if subtle.ConstantTimeCompare([]byte(a.entries[i].token), token) == 1 {
+			found = &a.entries[i]
+		}
+	}
+	if found == nil {
+		return nil, newAuthenticationError(a.Name(), "invalid token")
+	}
+	return &User{
+		Name:        found.user,
+		Groups:      found.groups,
+		AuthNMethod: a.Name(),
+	}, nil
+}
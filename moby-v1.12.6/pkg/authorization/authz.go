@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
 
@@ -30,10 +31,11 @@ const maxBodySize = 1048576 // 1MB
 // If multiple authZ plugins are specified, the block/allow decision is based on ANDing all plugin results
 // For response manipulation, the response from each plugin is piped between plugins. Plugin execution order
 // is determined according to daemon parameters
-func NewCtx(authZPlugins []Plugin, user, userAuthNMethod, requestMethod, requestURI string) *Ctx {
+func NewCtx(authZPlugins []Plugin, user string, userGroups []string, userAuthNMethod, requestMethod, requestURI string) *Ctx {
 	return &Ctx{
 		plugins:         authZPlugins,
 		user:            user,
+		userGroups:      userGroups,
 		userAuthNMethod: userAuthNMethod,
 		requestMethod:   requestMethod,
 		requestURI:      requestURI,
@@ -43,6 +45,7 @@ func NewCtx(authZPlugins []Plugin, user, userAuthNMethod, requestMethod, request
 // Ctx stores a single request-response interaction context
 type Ctx struct {
 	user            string
+	userGroups      []string
 	userAuthNMethod string
 	requestMethod   string
 	requestURI      string
@@ -69,6 +72,7 @@ func (ctx *Ctx) AuthZRequest(w http.ResponseWriter, r *http.Request) error {
 
 	ctx.authReq = &Request{
 		User:            ctx.user,
+		UserGroups:      ctx.userGroups,
 		UserAuthNMethod: ctx.userAuthNMethod,
 		RequestMethod:   ctx.requestMethod,
 		RequestURI:      ctx.requestURI,
@@ -145,8 +149,9 @@ func sendBody(url string, header http.Header) bool {
 		return false
 	}
 
-	// body is sent only for text or json messages
-	return header.Get("Content-Type") == "application/json"
+	// body is sent only for json messages
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
 }
 
 // headers returns flatten version of the http headers excluding authorization
@@ -172,6 +172,32 @@ func TestDrainBody(t *testing.T) {
 	}
 }
 
+func TestSendBody(t *testing.T) {
+	tests := []struct {
+		url         string
+		contentType string
+		expected    bool
+	}{
+		{"/v1.24/containers/create", "application/json", true},
+		{"/v1.24/containers/create", "Application/JSON", true},
+		{"/v1.24/containers/create", "application/json; charset=utf-8", true},
+		{"/v1.24/containers/create", "application/json;;", false},
+		{"/v1.24/containers/create", "application/x-tar", false},
+		{"/v1.24/containers/create", "", false},
+		{"/v1.24/auth", "application/json", false},
+	}
+
+	for _, test := range tests {
+		header := http.Header{}
+		if test.contentType != "" {
+			header.Set("Content-Type", test.contentType)
+		}
+		if sendBody(test.url, header) != test.expected {
+			t.Fatalf("Expected sendBody to return %v for %s with Content-Type %q", test.expected, test.url, test.contentType)
+		}
+	}
+}
+
 func TestResponseModifierOverride(t *testing.T) {
 	r := httptest.NewRecorder()
 	m := NewResponseModifier(r)
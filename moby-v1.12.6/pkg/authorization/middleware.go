@@ -2,42 +2,65 @@ package authorization
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/authentication"
 	"golang.org/x/net/context"
 )
 
 // Middleware uses a list of plugins to
 // handle authorization in the API requests.
 type Middleware struct {
+	mu      sync.Mutex
 	plugins []Plugin
 }
 
 // NewMiddleware creates a new Middleware
 // with a slice of plugins.
-func NewMiddleware(p []Plugin) Middleware {
-	return Middleware{
+func NewMiddleware(p []Plugin) *Middleware {
+	return &Middleware{
 		plugins: p,
 	}
 }
 
+// SetPlugins replaces the plugins of the middleware. Requests are not
+// authorized while it has no plugins.
+func (m *Middleware) SetPlugins(p []Plugin) {
+	m.mu.Lock()
+	m.plugins = p
+	m.mu.Unlock()
+}
+
+func (m *Middleware) getPlugins() []Plugin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.plugins
+}
+
 // WrapHandler returns a new handler function wrapping the previous one in the request chain.
-func (m Middleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+func (m *Middleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		plugins := m.getPlugins()
+		if len(plugins) == 0 {
+			return handler(ctx, w, r, vars)
+		}
 
-		user := ""
-		userAuthNMethod := ""
+		var (
+			user            string
+			userGroups      []string
+			userAuthNMethod string
+		)
 
-		// Default authorization using existing TLS connection credentials
-		// FIXME: Non trivial authorization mechanisms (such as advanced certificate validations, kerberos support
-		// and ldap) will be extracted using AuthN feature, which is tracked under:
-		// https://github.com/docker/docker/pull/20883
-		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
-			user = r.TLS.PeerCertificates[0].Subject.CommonName
-			userAuthNMethod = "TLS"
+		// The user is identified by the authentication middleware, which
+		// runs before this one.
+		if u := authentication.UserFromContext(ctx); u != nil {
+			user = u.Name
+			userGroups = u.Groups
+			userAuthNMethod = u.AuthNMethod
 		}
 
-		authCtx := NewCtx(m.plugins, user, userAuthNMethod, r.Method, r.RequestURI)
+		authCtx := NewCtx(plugins, user, userGroups, userAuthNMethod, r.Method, r.RequestURI)
 
 		if err := authCtx.AuthZRequest(w, r); err != nil {
 			logrus.Errorf("AuthZRequest for %s %s returned error: %s", r.Method, r.RequestURI, err)
@@ -0,0 +1,441 @@
+package authorization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/mount"
+)
+
+const (
+	policyActionAllow = "allow"
+	policyActionDeny  = "deny"
+)
+
+// versionPrefix matches the API version prefix of request paths.
+var versionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// PolicyRule is a rule of an authorization policy. A rule matches a request
+// when all of its conditions match; conditions which are not set match any
+// request.
+type PolicyRule struct {
+	// Name identifies the rule in authorization errors.
+	Name string `json:"name,omitempty"`
+
+	// Action is either "allow" or "deny".
+	Action string `json:"action"`
+
+	// Users and Groups match requests of any of the listed users, or of
+	// users in any of the listed groups.
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	// Methods match requests with any of the listed HTTP methods.
+	Methods []string `json:"methods,omitempty"`
+
+	// Endpoints match requests whose path, without the API version
+	// prefix, matches any of the listed patterns (e.g. "/containers/*/exec").
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Privileged matches requests creating privileged containers or execs
+	// if true, and other requests if false.
+	Privileged *bool `json:"privileged,omitempty"`
+
+	// BindMounts match requests bind-mounting a host path under any of
+	// the listed paths, with Binds, bind-type Mounts, or volumes of the
+	// local driver bind-mounting a device. "/" matches any host bind mount.
+	BindMounts []string `json:"bindMounts,omitempty"`
+
+	// Capabilities match requests adding any of the listed capabilities.
+	// "ALL" matches requests adding any capability.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// NetworkModes match requests creating containers with any of the
+	// listed network modes (e.g. "host", or "container" for any
+	// "container:<name|id>" mode).
+	NetworkModes []string `json:"networkModes,omitempty"`
+}
+
+// policyFile is the content of an authorization policy file.
+type policyFile struct {
+	// Default is the action for requests which match no rule, "allow"
+	// when not set.
+	Default string       `json:"default,omitempty"`
+	Rules   []PolicyRule `json:"rules"`
+}
+
+// HostConfigDecoder decodes the host configuration of container create and
+// start requests, including the deprecated top-level fields the daemon
+// still honours.
+type HostConfigDecoder interface {
+	DecodeHostConfig(src io.Reader) (*container.HostConfig, error)
+}
+
+// Policy is an authorization plugin built into the daemon. It allows or
+// denies requests according to the first rule of a policy file which
+// matches them.
+type Policy struct {
+	mu      sync.RWMutex
+	policy  policyFile
+	decoder HostConfigDecoder
+}
+
+// NewPolicy creates a Policy with the rules of the policy file at file.
+// Container configurations are decoded with decoder, the way the daemon
+// decodes them.
+func NewPolicy(file string, decoder HostConfigDecoder) (*Policy, error) {
+	p := &Policy{decoder: decoder}
+	if err := p.Reload(file); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload replaces the rules of the policy with those of the policy file
+// at file. The rules are left unchanged if the file is not valid.
+func (p *Policy) Reload(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var policy policyFile
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return fmt.Errorf("invalid authorization policy %s: %v", file, err)
+	}
+	if policy.Default == "" {
+		policy.Default = policyActionAllow
+	}
+	if err := validatePolicyAction(policy.Default); err != nil {
+		return fmt.Errorf("invalid authorization policy %s: default %v", file, err)
+	}
+	for i, rule := range policy.Rules {
+		if err := validatePolicyAction(rule.Action); err != nil {
+			return fmt.Errorf("invalid authorization policy %s: rule %d %v", file, i, err)
+		}
+		for _, endpoint := range rule.Endpoints {
+			if _, err := path.Match(endpoint, "/"); err != nil {
+				return fmt.Errorf("invalid authorization policy %s: rule %d endpoint %s: %v", file, i, endpoint, err)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.policy = policy
+	p.mu.Unlock()
+	logrus.Infof("Loaded authorization policy %s with %d rules", file, len(policy.Rules))
+	return nil
+}
+
+func validatePolicyAction(action string) error {
+	if action != policyActionAllow && action != policyActionDeny {
+		return fmt.Errorf("action must be %q or %q, got %q", policyActionAllow, policyActionDeny, action)
+	}
+	return nil
+}
+
+// Name returns the name the policy is reported as in authorization errors.
+func (p *Policy) Name() string {
+	return "policy"
+}
+
+// AuthZRequest allows or denies the request according to the first rule
+// which matches it.
+func (p *Policy) AuthZRequest(authReq *Request) (*Response, error) {
+	p.mu.RLock()
+	policy := p.policy
+	p.mu.RUnlock()
+
+	req := newPolicyRequest(authReq, p.decoder)
+	for i, rule := range policy.Rules {
+		if !rule.matches(req) {
+			continue
+		}
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		if rule.Action == policyActionDeny {
+			return &Response{Allow: false, Msg: fmt.Sprintf("denied by rule %s", name)}, nil
+		}
+		return &Response{Allow: true}, nil
+	}
+
+	if policy.Default == policyActionDeny {
+		return &Response{Allow: false, Msg: "denied by default"}, nil
+	}
+	return &Response{Allow: true}, nil
+}
+
+// AuthZResponse allows all responses, the policy only applies to requests.
+func (p *Policy) AuthZResponse(authReq *Request) (*Response, error) {
+	return &Response{Allow: true}, nil
+}
+
+// policyRequestBody holds the fields of request bodies which policy rules
+// can match. Exec configurations have a top-level Privileged field,
+// container configurations have a HostConfig, and volume create and clone
+// requests have the driver and options of the volume.
+type policyRequestBody struct {
+	Privileged bool
+	HostConfig *container.HostConfig
+	Driver     string
+	DriverOpts map[string]string
+}
+
+// policyRequest is a request as seen by policy rules.
+type policyRequest struct {
+	*Request
+	path string
+	// body is nil if the body of the request could not be inspected.
+	body *policyRequestBody
+}
+
+func newPolicyRequest(authReq *Request, decoder HostConfigDecoder) policyRequest {
+	req := policyRequest{Request: authReq}
+
+	req.path = authReq.RequestURI
+	if u, err := url.ParseRequestURI(authReq.RequestURI); err == nil {
+		req.path = u.Path
+	}
+	req.path = versionPrefix.ReplaceAllString(req.path, "")
+
+	switch {
+	case len(authReq.RequestBody) > 0 && hasHostConfigBody(req.path):
+		// Decode the host configuration as the daemon does, so that the
+		// deprecated top-level fields of create requests and the host
+		// configuration of legacy start requests are seen by the rules.
+		if hostConfig, err := decoder.DecodeHostConfig(bytes.NewReader(authReq.RequestBody)); err == nil {
+			req.body = &policyRequestBody{HostConfig: hostConfig}
+		}
+	case len(authReq.RequestBody) > 0:
+		var body policyRequestBody
+		if err := json.Unmarshal(authReq.RequestBody, &body); err == nil {
+			req.body = &body
+		}
+	case !hasJSONBody(authReq.RequestHeaders):
+		req.body = &policyRequestBody{}
+	}
+	return req
+}
+
+// hasHostConfigBody returns true if the body of requests to the endpoint at
+// requestPath holds a container host configuration.
+func hasHostConfigBody(requestPath string) bool {
+	if requestPath == "/containers/create" {
+		return true
+	}
+	ok, _ := path.Match("/containers/*/start", requestPath)
+	return ok
+}
+
+// hasJSONBody returns true if the headers of a request announce a body the
+// daemon may decode as JSON. Such a body is not passed to plugins when it is
+// too large or streamed. A Content-Type which cannot be parsed is treated as
+// JSON, so that the body is not taken for an empty one.
+func hasJSONBody(headers map[string]string) bool {
+	for k, v := range headers {
+		if strings.EqualFold(k, "Content-Length") && v == "0" {
+			return false
+		}
+	}
+	for k, v := range headers {
+		if !strings.EqualFold(k, "Content-Type") {
+			continue
+		}
+		mediaType, _, err := mime.ParseMediaType(v)
+		return err != nil || mediaType == "application/json"
+	}
+	return false
+}
+
+func (rule *PolicyRule) hasBodyConditions() bool {
+	return rule.Privileged != nil || len(rule.BindMounts) > 0 || len(rule.Capabilities) > 0 || len(rule.NetworkModes) > 0
+}
+
+func (rule *PolicyRule) matches(req policyRequest) bool {
+	if !rule.matchesUser(req.User, req.UserGroups) {
+		return false
+	}
+	if len(rule.Methods) > 0 && !containsFold(rule.Methods, req.RequestMethod) {
+		return false
+	}
+	if len(rule.Endpoints) > 0 && !matchesEndpoint(rule.Endpoints, req.path) {
+		return false
+	}
+	if !rule.hasBodyConditions() {
+		return true
+	}
+	if req.body == nil {
+		// The body could not be inspected, because it is too large, streamed
+		// or not valid JSON. Err on the side of denying the request.
+		return rule.Action == policyActionDeny
+	}
+	return rule.matchesBody(req.body)
+}
+
+func (rule *PolicyRule) matchesUser(user string, groups []string) bool {
+	if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+		return true
+	}
+	for _, u := range rule.Users {
+		if u == user {
+			return true
+		}
+	}
+	for _, g := range groups {
+		for _, rg := range rule.Groups {
+			if g == rg {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rule *PolicyRule) matchesBody(body *policyRequestBody) bool {
+	hostConfig := body.HostConfig
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+
+	if rule.Privileged != nil && *rule.Privileged != (body.Privileged || hostConfig.Privileged) {
+		return false
+	}
+	if len(rule.BindMounts) > 0 && !matchesBindMounts(rule.BindMounts, body.bindSources()) {
+		return false
+	}
+	if len(rule.Capabilities) > 0 && !matchesCapabilities(rule.Capabilities, hostConfig.CapAdd) {
+		return false
+	}
+	if len(rule.NetworkModes) > 0 && !matchesNetworkMode(rule.NetworkModes, string(hostConfig.NetworkMode)) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesEndpoint(patterns []string, requestPath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, requestPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bindSources returns the host paths the request bind-mounts, with Binds,
+// bind-type Mounts, volume-type Mounts creating a local volume, or by
+// creating a local volume.
+func (body *policyRequestBody) bindSources() []string {
+	var sources []string
+	if body.HostConfig != nil {
+		for _, bind := range body.HostConfig.Binds {
+			source := strings.SplitN(bind, ":", 2)[0]
+			if !strings.HasPrefix(source, "/") {
+				// Named volume
+				continue
+			}
+			sources = append(sources, source)
+		}
+		for _, m := range body.HostConfig.Mounts {
+			switch {
+			case m.Type == mount.TypeBind:
+				sources = append(sources, m.Source)
+			case m.Type == mount.TypeVolume && m.VolumeOptions != nil && m.VolumeOptions.DriverConfig != nil:
+				driver := m.VolumeOptions.DriverConfig
+				if device, ok := localBindDevice(driver.Name, driver.Options); ok {
+					sources = append(sources, device)
+				}
+			}
+		}
+	}
+	if device, ok := localBindDevice(body.Driver, body.DriverOpts); ok {
+		sources = append(sources, device)
+	}
+	return sources
+}
+
+// localBindDevice returns the device of a volume of the local driver whose
+// options bind-mount the device, as with "o=bind,device=/path". An empty
+// driver is the local driver, the default of the daemon.
+func localBindDevice(driver string, opts map[string]string) (string, bool) {
+	if driver != "" && driver != "local" {
+		return "", false
+	}
+	device := opts["device"]
+	if device == "" {
+		return "", false
+	}
+	for _, o := range strings.Split(opts["o"], ",") {
+		if o == "bind" || o == "rbind" {
+			return device, true
+		}
+	}
+	return "", false
+}
+
+// matchesBindMounts returns true if any of sources is under any of paths.
+func matchesBindMounts(paths []string, sources []string) bool {
+	for _, source := range sources {
+		if !filepath.IsAbs(source) {
+			// A relative device is resolved against the working directory
+			// of the daemon, so it may be under any path.
+			return true
+		}
+		source = filepath.Clean(source)
+		for _, p := range paths {
+			p = filepath.Clean(p)
+			if p == "/" || source == p || strings.HasPrefix(source, p+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+}
+
+// matchesCapabilities returns true if capAdd adds any of capabilities.
+func matchesCapabilities(capabilities []string, capAdd []string) bool {
+	for _, added := range capAdd {
+		added = normalizeCapability(added)
+		for _, c := range capabilities {
+			c = normalizeCapability(c)
+			if c == "ALL" || added == "ALL" || c == added {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesNetworkMode(modes []string, networkMode string) bool {
+	for _, mode := range modes {
+		if networkMode == mode || strings.HasPrefix(networkMode, mode+":") {
+			return true
+		}
+	}
+	return false
+}
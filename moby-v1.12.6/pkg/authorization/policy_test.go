@@ -0,0 +1,189 @@
+package authorization
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/runconfig"
+)
+
+const testPolicy = `{
+	"default": "deny",
+	"rules": [
+		{"name": "admins", "action": "allow", "groups": ["admins"]},
+		{"name": "privileged", "action": "deny", "privileged": true},
+		{"name": "etc", "action": "deny", "bindMounts": ["/etc"]},
+		{"name": "caps", "action": "deny", "capabilities": ["SYS_ADMIN"]},
+		{"name": "host-network", "action": "deny", "networkModes": ["host", "container"]},
+		{"name": "exec", "action": "deny", "users": ["bob"], "endpoints": ["/containers/*/exec"]},
+		{"name": "read", "action": "allow", "methods": ["GET", "HEAD"]},
+		{"name": "create", "action": "allow", "methods": ["POST"], "endpoints": ["/containers/create", "/containers/*/start", "/containers/*/exec", "/volumes/create", "/volumes/*/clone"]}
+	]
+}`
+
+func writePolicy(t *testing.T, dir, content string) string {
+	file := filepath.Join(dir, "policy.json")
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authz-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p, err := NewPolicy(writePolicy(t, dir, testPolicy), runconfig.ContainerDecoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		user    string
+		groups  []string
+		method  string
+		uri     string
+		body    string
+		headers map[string]string
+		allow   bool
+	}{
+		{user: "alice", method: "GET", uri: "/v1.24/containers/json?all=1", allow: true},
+		{user: "alice", method: "DELETE", uri: "/v1.24/containers/abc", allow: false},
+		{user: "root", groups: []string{"admins"}, method: "DELETE", uri: "/v1.24/containers/abc", allow: true},
+		{user: "root", groups: []string{"admins"}, method: "POST", uri: "/containers/create", body: `{"HostConfig":{"Privileged":true}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Binds":["vol:/data","/home/alice:/home"]}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"Privileged":true}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/abc/exec", body: `{"Cmd":["sh"],"Privileged":true}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"Binds":["/etc/../etc/ssl:/ssl:ro"]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"Binds":["/etcetera:/data"]}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"CapAdd":["cap_sys_admin"]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"CapAdd":["ALL"]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"CapAdd":["NET_ADMIN"]}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"NetworkMode":"host"}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"NetworkMode":"container:abc"}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"HostConfig":{"NetworkMode":"bridge"}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", headers: map[string]string{"Content-Type": "application/json"}, allow: false},
+		// Bodies not passed to the policy, whatever the case or parameters
+		// of their media type, or when it cannot be parsed
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", headers: map[string]string{"Content-Type": "Application/JSON"}, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", headers: map[string]string{"content-type": "application/json; charset=utf-8"}, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", headers: map[string]string{"Content-Type": "application/json;;"}, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", headers: map[string]string{"Content-Type": "application/json", "Content-Length": "0"}, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", headers: map[string]string{"Content-Type": "text/plain"}, allow: true},
+		{user: "bob", method: "POST", uri: "/v1.24/containers/abc/exec", body: `{"Cmd":["sh"]}`, allow: false},
+		{user: "bob", method: "POST", uri: "/v1.24/containers/abc/start", allow: true},
+		// Bind-type mounts
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"bind","Source":"/etc","Target":"/host"}]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"bind","Source":"/etc/../etc/ssl","Target":"/ssl"}]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"volume","Source":"etc","Target":"/etc"}]}}`, allow: true},
+		// Local volumes bind-mounting a device
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"volume","Source":"etc","Target":"/host","VolumeOptions":{"DriverConfig":{"Name":"local","Options":{"type":"none","o":"bind","device":"/etc"}}}}]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"volume","Source":"etc","Target":"/host","VolumeOptions":{"DriverConfig":{"Options":{"o":"ro,rbind","device":"/etc/ssl"}}}}]}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"volume","Source":"home","Target":"/home","VolumeOptions":{"DriverConfig":{"Name":"local","Options":{"o":"bind","device":"/home/alice"}}}}]}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"volume","Source":"etc","Target":"/host","VolumeOptions":{"DriverConfig":{"Name":"nfs","Options":{"o":"bind","device":"/etc"}}}}]}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/volumes/create", body: `{"Name":"etc","Driver":"local","DriverOpts":{"type":"none","o":"bind","device":"/etc"}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/volumes/create", body: `{"Name":"etc","DriverOpts":{"o":"bind","device":"etc"}}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/volumes/create", body: `{"Name":"etc","DriverOpts":{"type":"tmpfs","device":"/etc"}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/volumes/create", body: `{"Name":"home","DriverOpts":{"o":"bind","device":"/home/alice"}}`, allow: true},
+		{user: "alice", method: "POST", uri: "/v1.24/volumes/data/clone", body: `{"Name":"etc","DriverOpts":{"o":"bind","device":"/etc"}}`, allow: false},
+		// Deprecated top-level host configuration fields of create requests
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","Binds":["/etc:/etc"]}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","CapAdd":["SYS_ADMIN"]}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","NetworkMode":"host"}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","Privileged":true}`, allow: false},
+		// The daemon ignores them when the request has a HostConfig
+		{user: "alice", method: "POST", uri: "/v1.24/containers/create", body: `{"Image":"busybox","Binds":["/etc:/etc"],"HostConfig":{}}`, allow: true},
+		// Host configuration of legacy start requests
+		{user: "alice", method: "POST", uri: "/v1.23/containers/abc/start", body: `{"Binds":["/etc:/host"]}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.23/containers/abc/start", body: `{"CapAdd":["SYS_ADMIN"]}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.23/containers/abc/start", body: `{"Privileged":true}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.23/containers/abc/start", body: `{"NetworkMode":"host"}`, allow: false},
+		{user: "alice", method: "POST", uri: "/v1.23/containers/abc/start", body: `{"Binds":["/home/alice:/home"]}`, allow: true},
+	}
+
+	for _, c := range cases {
+		res, err := p.AuthZRequest(&Request{
+			User:           c.user,
+			UserGroups:     c.groups,
+			RequestMethod:  c.method,
+			RequestURI:     c.uri,
+			RequestBody:    []byte(c.body),
+			RequestHeaders: c.headers,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allow != c.allow {
+			t.Fatalf("expected allow=%v for %s %s %s by %s, got %v (%s)", c.allow, c.method, c.uri, c.body, c.user, res.Allow, res.Msg)
+		}
+	}
+}
+
+func TestPolicyReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authz-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := writePolicy(t, dir, `{"rules": [{"action": "deny", "methods": ["DELETE"]}]}`)
+	p, err := NewPolicy(file, runconfig.ContainerDecoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &Request{User: "alice", RequestMethod: "DELETE", RequestURI: "/v1.24/images/busybox"}
+	if res, _ := p.AuthZRequest(req); res.Allow {
+		t.Fatal("expected DELETE to be denied")
+	}
+
+	writePolicy(t, dir, `{"rules": [{"action": "maybe"}]}`)
+	if err := p.Reload(file); err == nil {
+		t.Fatal("expected an error for an invalid action")
+	}
+	if res, _ := p.AuthZRequest(req); res.Allow {
+		t.Fatal("expected an invalid policy to leave the rules unchanged")
+	}
+
+	writePolicy(t, dir, `{"rules": [{"action": "deny", "methods": ["POST"]}]}`)
+	if err := p.Reload(file); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := p.AuthZRequest(req); !res.Allow {
+		t.Fatalf("expected DELETE to be allowed after reload, got %s", res.Msg)
+	}
+}
+
+func TestPolicyBindMountTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authz-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p, err := NewPolicy(writePolicy(t, dir, `{"rules": [{"action": "deny", "bindMounts": ["/"]}]}`), runconfig.ContainerDecoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for body, allow := range map[string]bool{
+		`{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"bind","Source":"/","Target":"/host"}]}}`:      false,
+		`{"Image":"busybox","HostConfig":{"Binds":["/:/host"]}}`:                                           false,
+		`{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"tmpfs","Target":"/tmp"}]}}`:                   true,
+		`{"Image":"busybox","HostConfig":{"Mounts":[{"Type":"volume","Source":"data","Target":"/data"}]}}`: true,
+	} {
+		res, err := p.AuthZRequest(&Request{User: "alice", RequestMethod: "POST", RequestURI: "/v1.24/containers/create", RequestBody: []byte(body)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allow != allow {
+			t.Fatalf("expected allow=%v for %s, got %v (%s)", allow, body, res.Allow, res.Msg)
+		}
+	}
+}
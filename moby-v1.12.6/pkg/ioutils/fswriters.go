@@ -80,3 +80,49 @@ func (w *atomicFileWriter) Close() (retErr error) {
 	}
 	return nil
 }
+
+// AtomicWriteFileWithBackup atomically writes data to a file named by
+// filename, like AtomicWriteFile, and keeps the previous content of the file
+// at backupname. The new content is synced, as well as the directory once the
+// file is replaced, so that either the new or the previous content survives
+// a power loss.
+func AtomicWriteFileWithBackup(filename, backupname string, data []byte, perm os.FileMode) (retErr error) {
+	f, err := ioutil.TempFile(filepath.Dir(filename), ".tmp-"+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	n, err := f.Write(data)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), perm); err != nil {
+		return err
+	}
+
+	// link rather than move the current file, so that it is never missing
+	if err := os.Remove(backupname); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Link(filename, backupname); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(f.Name(), filename); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(filename))
+}
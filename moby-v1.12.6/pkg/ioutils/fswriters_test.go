@@ -37,3 +37,51 @@ func TestAtomicWriteToFile(t *testing.T) {
 		t.Fatalf("Mode mismatched, expected %o, got %o", expected, st.Mode())
 	}
 }
+
+func TestAtomicWriteFileWithBackup(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "atomic-writers-test")
+	if err != nil {
+		t.Fatalf("Error when creating temporary directory: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	fn := filepath.Join(tmpDir, "foo")
+	backup := filepath.Join(tmpDir, "foo.bak")
+
+	// there is nothing to back up on the first write
+	if err := AtomicWriteFileWithBackup(fn, backup, []byte("first"), 0600); err != nil {
+		t.Fatalf("Error writing to file: %v", err)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Fatalf("Expected no backup after the first write, got %v", err)
+	}
+
+	if err := AtomicWriteFileWithBackup(fn, backup, []byte("second"), 0600); err != nil {
+		t.Fatalf("Error writing to file: %v", err)
+	}
+	for path, expected := range map[string]string{fn: "second", backup: "first"} {
+		actual, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Error reading from file: %v", err)
+		}
+		if string(actual) != expected {
+			t.Fatalf("Data mismatch in %s, expected %q, got %q", path, expected, actual)
+		}
+	}
+
+	st, err := os.Stat(fn)
+	if err != nil {
+		t.Fatalf("Error statting file: %v", err)
+	}
+	if expected := os.FileMode(0600); st.Mode() != expected {
+		t.Fatalf("Mode mismatched, expected %o, got %o", expected, st.Mode())
+	}
+
+	files, err := ioutil.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected only the file and its backup, got %d files", len(files))
+	}
+}
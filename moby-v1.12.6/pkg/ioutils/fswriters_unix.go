@@ -0,0 +1,16 @@
+// +build !windows
+
+package ioutils
+
+import "os"
+
+// syncDir flushes the entries of a directory, such as a renamed file, to
+// disk.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
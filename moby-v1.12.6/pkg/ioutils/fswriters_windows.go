@@ -0,0 +1,7 @@
+package ioutils
+
+// syncDir is a no-op on Windows, where directories cannot be opened for
+// syncing and renames are flushed with the file system metadata.
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,472 @@
+// +build linux
+
+package seccomp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/engine-api/types"
+	"github.com/opencontainers/specs/specs-go"
+)
+
+// auditSeccompType is the type of the audit records of the kernel for
+// syscalls matching a seccomp action which is logged.
+const auditSeccompType = "1326"
+
+// kernelLogPath is the path of the kernel log, which holds the audit records
+// of the kernel when no audit daemon collects them.
+const kernelLogPath = "/dev/kmsg"
+
+// flushTimeout is how long Remove waits for the records logged before it
+// was called to be read.
+const flushTimeout = 2 * time.Second
+
+// auditArches maps the AUDIT_ARCH values of audit records to seccomp
+// architectures.
+var auditArches = map[uint32]types.Arch{
+	0x40000003: types.ArchX86,
+	0xc000003e: types.ArchX86_64,
+	0x40000028: types.ArchARM,
+	0xc00000b7: types.ArchAARCH64,
+	0x00000008: types.ArchMIPS,
+	0x80000008: types.ArchMIPS64,
+	0xa0000008: types.ArchMIPS64N32,
+	0x40000008: types.ArchMIPSEL,
+	0xc0000008: types.ArchMIPSEL64,
+	0xe0000008: types.ArchMIPSEL64N32,
+	0x00000014: types.ArchPPC,
+	0x80000015: types.ArchPPC64,
+	0xc0000015: types.ArchPPC64LE,
+	0x00000016: types.ArchS390,
+	0x80000016: types.ArchS390X,
+}
+
+// x32SyscallBit is set in the numbers of the syscalls of the x32 ABI, which
+// share the AUDIT_ARCH value of x86_64.
+const x32SyscallBit = 0x40000000
+
+var auditField = regexp.MustCompile(`(\w+)=("[^"]*"|\S+)`)
+
+// kernelLogSeq matches the sequence number in the prefix of a record of the
+// kernel log, "<priority>,<sequence>,<timestamp>,<flags>;".
+var kernelLogSeq = regexp.MustCompile(`^\d+,(\d+),`)
+
+// seccompActionsPath lists the seccomp actions supported by the kernel.
+const seccompActionsPath = "/proc/sys/kernel/seccomp/actions_avail"
+
+// logLibseccompVersion is the first version of libseccomp supporting the
+// SCMP_ACT_LOG action.
+var logLibseccompVersion = [3]int{2, 4, 0}
+
+var libseccompVersion = regexp.MustCompile(`(?m)^libseccomp: (\d+)\.(\d+)\.(\d+)`)
+
+// CheckRecordSupport returns an error if the kernel or the runtime at
+// runtimePath cannot run containers with the RecordProfile, whose
+// SCMP_ACT_LOG action requires Linux 4.14 and a runtime built with
+// libseccomp 2.4.0 or later.
+func CheckRecordSupport(runtimePath string) error {
+	actions, err := ioutil.ReadFile(seccompActionsPath)
+	if err != nil || !hasField(string(actions), "log") {
+		return fmt.Errorf("Cannot record the seccomp profile of the container: the kernel does not support the SCMP_ACT_LOG action, Linux 4.14 or later is required")
+	}
+	out, err := exec.Command(runtimePath, "--version").Output()
+	if err != nil {
+		return fmt.Errorf("Cannot record the seccomp profile of the container: failed to get the version of runtime %s: %v", runtimePath, err)
+	}
+	if !runtimeSupportsLog(out) {
+		return fmt.Errorf("Cannot record the seccomp profile of the container: runtime %s does not support the SCMP_ACT_LOG action, runc 1.0.0-rc91 or later built with libseccomp 2.4.0 or later is required", runtimePath)
+	}
+	return nil
+}
+
+// runtimeSupportsLog returns true if the version of a runtime reports a
+// libseccomp version supporting the SCMP_ACT_LOG action. Runtimes which do
+// not report the version of libseccomp they are built with predate it.
+func runtimeSupportsLog(version []byte) bool {
+	m := libseccompVersion.FindSubmatch(version)
+	if m == nil {
+		return false
+	}
+	for i, min := range logLibseccompVersion {
+		n, _ := strconv.Atoi(string(m[i+1]))
+		if n != min {
+			return n > min
+		}
+	}
+	return true
+}
+
+func hasField(s, field string) bool {
+	for _, f := range strings.Fields(s) {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordProfile returns the profile of containers whose syscalls are
+// recorded, which allows and logs all syscalls.
+func RecordProfile() *specs.Seccomp {
+	return &specs.Seccomp{DefaultAction: specs.Action(types.ActLog)}
+}
+
+// Recorder collects the syscalls the kernel logs for the processes of the
+// containers run with the RecordProfile.
+type Recorder struct {
+	mu         sync.Mutex
+	containers map[string]*recording
+	pids       map[int]string
+	log        io.ReadCloser
+	// seq is the sequence number of the last record read from log.
+	seq uint64
+
+	// openLog opens the source of the audit records, lastSeq returns the
+	// sequence number of the last record logged, and resolve returns the
+	// name of a syscall from its number.
+	openLog func() (io.ReadCloser, error)
+	lastSeq func() (uint64, error)
+	resolve func(arch types.Arch, nr int) (string, error)
+}
+
+type recording struct {
+	syscalls map[types.Arch]map[int]struct{}
+}
+
+// NewRecorder creates a new Recorder reading the audit records of the
+// kernel log.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		containers: make(map[string]*recording),
+		pids:       make(map[int]string),
+		openLog:    openKernelLog,
+		lastSeq:    lastKernelLogSeq,
+		resolve:    syscallName,
+	}
+}
+
+func openKernelLog() (io.ReadCloser, error) {
+	f, err := os.Open(kernelLogPath)
+	if err != nil {
+		return nil, err
+	}
+	// Skip the records logged before the recording started.
+	if _, err := f.Seek(0, os.SEEK_END); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// lastKernelLogSeq returns the sequence number of the last record of the
+// kernel log, reading the log without blocking until no record is left.
+func lastKernelLogSeq() (uint64, error) {
+	fd, err := syscall.Open(kernelLogPath, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.Close(fd)
+
+	var last uint64
+	buf := make([]byte, 8192)
+	for {
+		n, err := syscall.Read(fd, buf)
+		switch err {
+		case nil:
+		case syscall.EAGAIN:
+			return last, nil
+		case syscall.EPIPE, syscall.EINTR:
+			// EPIPE reports records overwritten before they were read.
+			continue
+		default:
+			return 0, err
+		}
+		if n == 0 {
+			return last, nil
+		}
+		if seq, ok := recordSeq(string(buf[:n])); ok {
+			last = seq
+		}
+	}
+}
+
+// recordSeq returns the sequence number of a record of the kernel log.
+func recordSeq(line string) (uint64, bool) {
+	m := kernelLogSeq.FindStringSubmatch(line)
+	if m == nil {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(m[1], 10, 64)
+	return seq, err == nil
+}
+
+// Add starts recording the syscalls of the container id.
+func (r *Recorder) Add(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.containers[id]; ok {
+		return nil
+	}
+	if r.log == nil {
+		log, err := r.openLog()
+		if err != nil {
+			return fmt.Errorf("cannot read the audit records of the kernel: %v", err)
+		}
+		// openLog skips the records logged so far, which Remove need not
+		// wait for.
+		seq, err := r.lastSeq()
+		if err != nil {
+			log.Close()
+			return fmt.Errorf("cannot read the audit records of the kernel: %v", err)
+		}
+		r.log = log
+		r.seq = seq
+		go r.read(log)
+	}
+	r.containers[id] = &recording{syscalls: make(map[types.Arch]map[int]struct{})}
+	return nil
+}
+
+// Remove stops recording the syscalls of the container id, and returns
+// the profile allowing the syscalls it used, once the records logged before
+// it was called are read.
+func (r *Recorder) Remove(id string) *types.Seccomp {
+	r.flush()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.containers[id]
+	if !ok {
+		return nil
+	}
+	delete(r.containers, id)
+	for pid, c := range r.pids {
+		if c == id {
+			delete(r.pids, pid)
+		}
+	}
+	if len(r.containers) == 0 && r.log != nil {
+		r.log.Close()
+		r.log = nil
+	}
+	return r.profile(rec)
+}
+
+// flush waits for the records logged before it was called to be read, for
+// at most flushTimeout.
+func (r *Recorder) flush() {
+	r.mu.Lock()
+	reading := r.log != nil
+	r.mu.Unlock()
+	if !reading {
+		return
+	}
+
+	last, err := r.lastSeq()
+	if err != nil {
+		logrus.Warnf("Cannot read the last audit record of the kernel: %v", err)
+		return
+	}
+	deadline := time.Now().Add(flushTimeout)
+	for {
+		r.mu.Lock()
+		done := r.log == nil || r.seq >= last
+		r.mu.Unlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			logrus.Warnf("Timed out reading the audit records of the kernel, the recorded seccomp profile may be missing syscalls")
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (r *Recorder) read(log io.Reader) {
+	s := bufio.NewScanner(log)
+	for s.Scan() {
+		line := s.Text()
+		r.handleRecord(line)
+		if seq, ok := recordSeq(line); ok {
+			r.mu.Lock()
+			r.seq = seq
+			r.mu.Unlock()
+		}
+	}
+}
+
+// handleRecord adds the syscall of an audit record of the kernel log to the
+// recording of the container of the process which made it.
+func (r *Recorder) handleRecord(line string) {
+	if !strings.Contains(line, "type="+auditSeccompType) {
+		return
+	}
+	fields := make(map[string]string)
+	for _, m := range auditField.FindAllStringSubmatch(line, -1) {
+		fields[m[1]] = m[2]
+	}
+	pid, err := strconv.Atoi(fields["pid"])
+	if err != nil {
+		return
+	}
+	auditArch, err := strconv.ParseUint(fields["arch"], 16, 32)
+	if err != nil {
+		return
+	}
+	nr, err := strconv.Atoi(fields["syscall"])
+	if err != nil {
+		return
+	}
+	arch, ok := auditArches[uint32(auditArch)]
+	if !ok {
+		return
+	}
+	if arch == types.ArchX86_64 && nr&x32SyscallBit != 0 {
+		arch = types.ArchX32
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := r.containerOf(pid)
+	if rec == nil {
+		return
+	}
+	if rec.syscalls[arch] == nil {
+		rec.syscalls[arch] = make(map[int]struct{})
+	}
+	rec.syscalls[arch][nr] = struct{}{}
+}
+
+// containerOf returns the recording of the container of the process pid,
+// from the cgroups of the process. It returns nil if the process has exited
+// already, as its syscalls cannot be attributed to a container. It must be
+// called with mu held.
+func (r *Recorder) containerOf(pid int) *recording {
+	if id, ok := r.pids[pid]; ok {
+		return r.containers[id]
+	}
+	b, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil
+	}
+	for id, rec := range r.containers {
+		if strings.Contains(string(b), id) {
+			r.pids[pid] = id
+			return rec
+		}
+	}
+	return nil
+}
+
+// profile returns the profile allowing the syscalls of a recording, and
+// denying the others.
+func (r *Recorder) profile(rec *recording) *types.Seccomp {
+	profile := &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		Architectures: []types.Arch{},
+		Syscalls:      []*types.Syscall{},
+	}
+	names := make(map[string]struct{})
+	for arch, syscalls := range rec.syscalls {
+		profile.Architectures = append(profile.Architectures, arch)
+		for nr := range syscalls {
+			name, err := r.resolve(arch, nr)
+			if err != nil {
+				logrus.Warnf("Cannot resolve the name of syscall %d of architecture %s: %v", nr, arch, err)
+				continue
+			}
+			names[name] = struct{}{}
+		}
+	}
+	sort.Sort(archs(profile.Architectures))
+	profile.Syscalls = allowSyscalls(names)
+	return profile
+}
+
+// WriteProfile writes a recorded profile to path, adding the syscalls
+// allowed by the profile already at path, if any, so that the syscalls of
+// successive runs of a container are recorded.
+func WriteProfile(path string, profile *types.Seccomp) error {
+	if b, err := ioutil.ReadFile(path); err == nil {
+		var previous types.Seccomp
+		if err := json.Unmarshal(b, &previous); err != nil {
+			return fmt.Errorf("Decoding seccomp profile %s failed: %v", path, err)
+		}
+		profile = mergeProfiles(&previous, profile)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	b, err := json.MarshalIndent(profile, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(path, append(b, '\n'), 0644)
+}
+
+func mergeProfiles(a, b *types.Seccomp) *types.Seccomp {
+	arches := make(map[types.Arch]struct{})
+	names := make(map[string]struct{})
+	for _, p := range []*types.Seccomp{a, b} {
+		for _, arch := range p.Architectures {
+			arches[arch] = struct{}{}
+		}
+		for _, s := range p.Syscalls {
+			if s.Action == types.ActAllow && len(s.Args) == 0 {
+				names[s.Name] = struct{}{}
+			}
+		}
+	}
+	profile := &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		Architectures: []types.Arch{},
+		Syscalls:      allowSyscalls(names),
+	}
+	for arch := range arches {
+		profile.Architectures = append(profile.Architectures, arch)
+	}
+	sort.Sort(archs(profile.Architectures))
+	return profile
+}
+
+func allowSyscalls(names map[string]struct{}) []*types.Syscall {
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	syscalls := []*types.Syscall{}
+	for _, name := range sorted {
+		syscalls = append(syscalls, &types.Syscall{
+			Name:   name,
+			Action: types.ActAllow,
+			Args:   []*types.Arg{},
+		})
+	}
+	return syscalls
+}
+
+type archs []types.Arch
+
+func (a archs) Len() int           { return len(a) }
+func (a archs) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a archs) Less(i, j int) bool { return a[i] < a[j] }
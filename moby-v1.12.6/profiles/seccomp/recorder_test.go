@@ -0,0 +1,200 @@
+// +build linux
+
+package seccomp
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types"
+)
+
+func newTestRecorder() *Recorder {
+	r := NewRecorder()
+	r.openLog = func() (io.ReadCloser, error) {
+		pr, _ := io.Pipe()
+		return pr, nil
+	}
+	r.lastSeq = func() (uint64, error) {
+		return 0, nil
+	}
+	r.resolve = func(arch types.Arch, nr int) (string, error) {
+		names := map[int]string{0: "read", 1: "write", 59: "execve", 0x40000000: "read"}
+		if name, ok := names[nr]; ok {
+			return name, nil
+		}
+		return "", fmt.Errorf("unknown syscall %d", nr)
+	}
+	return r
+}
+
+func auditRecord(pid int, arch string, nr int) string {
+	return fmt.Sprintf(`6,1234,5678,-;audit: type=1326 audit(1476000000.123:42): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=%d comm="sh" exe="/bin/busybox" sig=0 arch=%s syscall=%d compat=0 ip=0x7f code=0x7ffc0000`, pid, arch, nr)
+}
+
+func syscallNames(p *types.Seccomp) []string {
+	var names []string
+	for _, s := range p.Syscalls {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestRecorder(t *testing.T) {
+	r := newTestRecorder()
+	if err := r.Add("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The process was attributed to the container from its cgroups.
+	r.pids[999999] = "abc"
+	r.handleRecord(auditRecord(999999, "c000003e", 59))
+	r.handleRecord(auditRecord(999999, "c000003e", 1))
+	r.handleRecord(auditRecord(999999, "c000003e", 1))
+	r.handleRecord(auditRecord(999999, "c000003e", 0x40000000))
+	r.handleRecord(auditRecord(999999, "c000003e", 400))
+	r.handleRecord(auditRecord(999999, "deadbeef", 0))
+	r.handleRecord(`6,1235,5679,-;audit: type=1400 audit(1476000000.123:43): apparmor="DENIED" pid=999999`)
+	// The process has exited, so its syscalls cannot be attributed.
+	r.handleRecord(auditRecord(999998, "c000003e", 2))
+
+	if p := r.Remove("unknown"); p != nil {
+		t.Fatalf("expected no profile for an unknown container, got %v", p)
+	}
+	p := r.Remove("abc")
+	if p == nil {
+		t.Fatal("expected a profile")
+	}
+	if p.DefaultAction != types.ActErrno {
+		t.Fatalf("expected the default action %s, got %s", types.ActErrno, p.DefaultAction)
+	}
+	if expected := []types.Arch{types.ArchX32, types.ArchX86_64}; !reflect.DeepEqual(p.Architectures, expected) {
+		t.Fatalf("expected architectures %v, got %v", expected, p.Architectures)
+	}
+	if expected := []string{"execve", "read", "write"}; !reflect.DeepEqual(syscallNames(p), expected) {
+		t.Fatalf("expected syscalls %v, got %v", expected, syscallNames(p))
+	}
+	if r.log != nil {
+		t.Fatal("expected the kernel log to be closed once no container is recorded")
+	}
+}
+
+func TestRecorderIgnoresOtherProcesses(t *testing.T) {
+	r := newTestRecorder()
+	r.Add("abc")
+	r.Add("def")
+
+	// The process is not in the cgroups of any recorded container.
+	r.handleRecord(auditRecord(os.Getpid(), "c000003e", 0))
+	// The syscalls of exited processes are dropped.
+	r.handleRecord(auditRecord(999999, "c000003e", 1))
+
+	for _, id := range []string{"abc", "def"} {
+		if p := r.Remove(id); len(p.Syscalls) != 0 {
+			t.Fatalf("expected no syscalls for %s, got %v", id, syscallNames(p))
+		}
+	}
+}
+
+func TestRecorderFlush(t *testing.T) {
+	r := newTestRecorder()
+	pr, pw := io.Pipe()
+	r.openLog = func() (io.ReadCloser, error) {
+		return pr, nil
+	}
+	r.lastSeq = func() (uint64, error) {
+		return 1234, nil
+	}
+	if err := r.Add("abc"); err != nil {
+		t.Fatal(err)
+	}
+	r.pids[999999] = "abc"
+
+	// The record is logged before the recording is removed, but read later.
+	r.lastSeq = func() (uint64, error) {
+		return 1235, nil
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintln(pw, strings.Replace(auditRecord(999999, "c000003e", 1), "6,1234,", "6,1235,", 1))
+	}()
+
+	p := r.Remove("abc")
+	if expected := []string{"write"}; !reflect.DeepEqual(syscallNames(p), expected) {
+		t.Fatalf("expected syscalls %v, got %v", expected, syscallNames(p))
+	}
+}
+
+func TestRecordSeq(t *testing.T) {
+	if seq, ok := recordSeq(auditRecord(1, "c000003e", 0)); !ok || seq != 1234 {
+		t.Fatalf("expected the sequence number 1234, got %d", seq)
+	}
+	if _, ok := recordSeq(" SUBSYSTEM=cpu"); ok {
+		t.Fatal("expected no sequence number in a continuation line")
+	}
+}
+
+func TestWriteProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seccomp-record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "profile.json")
+
+	first := &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		Architectures: []types.Arch{types.ArchX86_64},
+		Syscalls:      allowSyscalls(map[string]struct{}{"read": {}, "write": {}}),
+	}
+	second := &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		Architectures: []types.Arch{types.ArchX86},
+		Syscalls:      allowSyscalls(map[string]struct{}{"execve": {}, "read": {}}),
+	}
+	if err := WriteProfile(path, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteProfile(path, second); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfile(string(b)); err != nil {
+		t.Fatal(err)
+	}
+	merged := mergeProfiles(first, second)
+	if expected := []string{"execve", "read", "write"}; !reflect.DeepEqual(syscallNames(merged), expected) {
+		t.Fatalf("expected syscalls %v, got %v", expected, syscallNames(merged))
+	}
+	if expected := []types.Arch{types.ArchX86, types.ArchX86_64}; !reflect.DeepEqual(merged.Architectures, expected) {
+		t.Fatalf("expected architectures %v, got %v", expected, merged.Architectures)
+	}
+}
+
+func TestRuntimeSupportsLog(t *testing.T) {
+	cases := map[string]bool{
+		"runc version 1.0.0-rc2\ncommit: 50a19c6ff828c58e5dab13830bd3dacde268afe5\nspec: 1.0.0-rc2-dev\n":                                false,
+		"runc version 1.0.0-rc92\ncommit: ff819c7e\nspec: 1.0.2-dev\n":                                                                   false,
+		"runc version 1.0.0-rc92\ncommit: ff819c7e\nspec: 1.0.2-dev\ngo: go1.13.15\nlibseccomp: 2.3.3\n":                                 false,
+		"runc version 1.0.0-rc92\ncommit: ff819c7e\nspec: 1.0.2-dev\ngo: go1.13.15\nlibseccomp: 2.4.0\n":                                 true,
+		"runc version 1.1.4\ncommit: v1.1.4-0-g5fd4c4d1\nspec: 1.0.2-dev\ngo: go1.17.10\nlibseccomp: 2.5.4\n":                            true,
+		"runc version 1.1.4\ncommit: v1.1.4-0-g5fd4c4d1\nspec: 1.0.2-dev\ngo: go1.17.10\nlibseccomp: 3.0.0\n":                            true,
+		"crun version 1.4.5\ncommit: c381048530aa750495cf502ddb7181f2ded5b400\nspec: 1.0.0\n+SYSTEMD +SELINUX +APPARMOR +CAP +SECCOMP\n": false,
+	}
+	for version, want := range cases {
+		if got := runtimeSupportsLog([]byte(version)); got != want {
+			t.Errorf("runtimeSupportsLog(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
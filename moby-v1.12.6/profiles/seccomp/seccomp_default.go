@@ -3,6 +3,7 @@
 package seccomp
 
 import (
+	"strings"
 	"syscall"
 
 	"github.com/docker/engine-api/types"
@@ -36,6 +37,15 @@ func arches() []types.Arch {
 	}
 }
 
+// syscallName returns the name of the syscall nr of architecture arch.
+func syscallName(arch types.Arch, nr int) (string, error) {
+	a, err := libseccomp.GetArchFromString(strings.ToLower(strings.TrimPrefix(string(arch), "SCMP_ARCH_")))
+	if err != nil {
+		return "", err
+	}
+	return libseccomp.ScmpSyscall(nr).GetNameByArch(a)
+}
+
 // DefaultProfile defines the whitelist for the default seccomp profile.
 func DefaultProfile(rs *specs.Spec) *types.Seccomp {
 
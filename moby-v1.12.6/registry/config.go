@@ -27,6 +27,10 @@ type ServiceOptions struct {
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+
+	// registryMirrors holds the mirrors of registries other than the
+	// official index, keyed by registry hostname.
+	registryMirrors map[string][]string
 }
 
 var (
@@ -72,7 +76,7 @@ var lookupIP = net.LookupIP
 // the current process.
 func (options *ServiceOptions) InstallCliFlags(cmd *flag.FlagSet, usageFn func(string) string) {
 	mirrors := opts.NewNamedListOptsRef("registry-mirrors", &options.Mirrors, ValidateMirror)
-	cmd.Var(mirrors, []string{"-registry-mirror"}, usageFn("Preferred Docker registry mirror"))
+	cmd.Var(mirrors, []string{"-registry-mirror"}, usageFn("Preferred Docker registry mirror, prefix with REGISTRY= to mirror a registry other than Docker Hub"))
 
 	insecureRegistries := opts.NewNamedListOptsRef("insecure-registries", &options.InsecureRegistries, ValidateIndexName)
 	cmd.Var(insecureRegistries, []string{"-insecure-registry"}, usageFn("Enable insecure registry communication"))
@@ -93,11 +97,21 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 		ServiceConfig: registrytypes.ServiceConfig{
 			InsecureRegistryCIDRs: make([]*registrytypes.NetIPNet, 0),
 			IndexConfigs:          make(map[string]*registrytypes.IndexInfo, 0),
-			// Hack: Bypass setting the mirrors to IndexConfigs since they are going away
-			// and Mirrors are only for the official registry anyways.
-			Mirrors: options.Mirrors,
+			// Mirrors holds the mirrors of the official registry only; the
+			// mirrors of other registries are kept in registryMirrors.
+			Mirrors: make([]string, 0),
 		},
-		V2Only: options.V2Only,
+		V2Only:          options.V2Only,
+		registryMirrors: make(map[string][]string),
+	}
+	// Split --registry-mirror into mirrors of the official index and
+	// mirrors of other registries, keeping the order they were given in.
+	for _, m := range options.Mirrors {
+		if registryName, mirror, ok := splitRegistryMirror(m); ok {
+			config.registryMirrors[registryName] = append(config.registryMirrors[registryName], mirror)
+		} else {
+			config.Mirrors = append(config.Mirrors, m)
+		}
 	}
 	// Split --insecure-registry into CIDR and registry-specific settings.
 	for _, r := range options.InsecureRegistries {
@@ -110,7 +124,7 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// Assume `host:port` if not CIDR.
 			config.IndexConfigs[r] = &registrytypes.IndexInfo{
 				Name:     r,
-				Mirrors:  make([]string, 0),
+				Mirrors:  config.mirrorsFor(r),
 				Secure:   false,
 				Official: false,
 			}
@@ -177,8 +191,43 @@ func isSecureIndex(config *serviceConfig, indexName string) bool {
 	return true
 }
 
-// ValidateMirror validates an HTTP(S) registry mirror
+// mirrorsFor returns the mirrors configured for a registry other than the
+// official index.
+func (config *serviceConfig) mirrorsFor(indexName string) []string {
+	mirrors := make([]string, 0, len(config.registryMirrors[indexName]))
+	return append(mirrors, config.registryMirrors[indexName]...)
+}
+
+// splitRegistryMirror splits a mirror of the form `registry=URL` into the
+// registry it mirrors and the mirror URL. It returns false for a mirror of
+// the official index, which is given as a bare URL.
+func splitRegistryMirror(val string) (string, string, bool) {
+	i := strings.Index(val, "=")
+	if i <= 0 || strings.Contains(val[:i], "://") {
+		return "", "", false
+	}
+	return val[:i], val[i+1:], true
+}
+
+// ValidateMirror validates an HTTP(S) registry mirror. The mirror may be
+// prefixed with `registry=` to mirror a registry other than the official
+// index.
 func ValidateMirror(val string) (string, error) {
+	if registryName, mirror, ok := splitRegistryMirror(val); ok {
+		registryName, err := ValidateIndexName(registryName)
+		if err != nil {
+			return "", err
+		}
+		if registryName == IndexName {
+			return "", fmt.Errorf("Mirrors of %s must be given without a registry prefix", IndexName)
+		}
+		mirror, err = ValidateMirror(mirror)
+		if err != nil {
+			return "", err
+		}
+		return registryName + "=" + mirror, nil
+	}
+
 	uri, err := url.Parse(val)
 	if err != nil {
 		return "", fmt.Errorf("%s is not a valid URI", val)
@@ -230,7 +279,7 @@ func newIndexInfo(config *serviceConfig, indexName string) (*registrytypes.Index
 	// Construct a non-configured index info.
 	index := &registrytypes.IndexInfo{
 		Name:     indexName,
-		Mirrors:  make([]string, 0),
+		Mirrors:  config.mirrorsFor(indexName),
 		Official: false,
 	}
 	index.Secure = isSecureIndex(config, indexName)
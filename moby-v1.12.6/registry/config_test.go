@@ -16,6 +16,8 @@ func TestValidateMirror(t *testing.T) {
 		"https://127.0.0.1",
 		"http://127.0.0.1:5000",
 		"https://127.0.0.1:5000",
+		"quay.io=https://quay-mirror.local",
+		"registry.local:5000=http://127.0.0.1:5001",
 	}
 
 	invalid := []string{
@@ -33,6 +35,10 @@ func TestValidateMirror(t *testing.T) {
 		"https://mirror-1.com/v1/",
 		"https://mirror-1.com/v1/#",
 		"https://mirror-1.com?q",
+		"quay.io=ftp://quay-mirror.local",
+		"quay.io=https://quay-mirror.local/v2/",
+		"-quay.io=https://quay-mirror.local",
+		"docker.io=https://mirror-1.com",
 	}
 
 	for _, address := range valid {
@@ -616,6 +616,41 @@ func TestNewIndexInfo(t *testing.T) {
 	}
 	testIndexInfo(config, expectedIndexInfos)
 
+	registryMirrors := []string{"http://mirror1.local", "http://mirror2.local"}
+	config = makeServiceConfig([]string{
+		"http://hub-mirror.local",
+		"quay.io=http://mirror1.local",
+		"example.com=http://mirror3.local",
+		"quay.io=http://mirror2.local",
+	}, []string{"example.com"})
+	expectedIndexInfos = map[string]*registrytypes.IndexInfo{
+		IndexName: {
+			Name:     IndexName,
+			Official: true,
+			Secure:   true,
+			Mirrors:  []string{"http://hub-mirror.local"},
+		},
+		"quay.io": {
+			Name:     "quay.io",
+			Official: false,
+			Secure:   true,
+			Mirrors:  registryMirrors,
+		},
+		"example.com": {
+			Name:     "example.com",
+			Official: false,
+			Secure:   false,
+			Mirrors:  []string{"http://mirror3.local"},
+		},
+		"other.com": {
+			Name:     "other.com",
+			Official: false,
+			Secure:   true,
+			Mirrors:  noMirrors,
+		},
+	}
+	testIndexInfo(config, expectedIndexInfos)
+
 	config = makeServiceConfig(nil, []string{"42.42.0.0/16"})
 	expectedIndexInfos = map[string]*registrytypes.IndexInfo{
 		"example.com": {
@@ -684,6 +719,49 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestRegistryMirrorEndpointLookup(t *testing.T) {
+	s := DefaultService{config: makeServiceConfig([]string{"https://hub.mirror", "quay.io=https://my.mirror"}, nil)}
+
+	pullAPIEndpoints, err := s.LookupPullEndpoints("quay.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pullAPIEndpoints) < 2 {
+		t.Fatalf("Expected mirror and registry pull endpoints, got %d endpoints", len(pullAPIEndpoints))
+	}
+	if pullAPIEndpoints[0].URL.Host != "my.mirror" || !pullAPIEndpoints[0].Mirror {
+		t.Fatalf("Expected mirror to be the first pull endpoint, got %s", pullAPIEndpoints[0].URL)
+	}
+	if pullAPIEndpoints[1].URL.Host != "quay.io" || pullAPIEndpoints[1].Mirror {
+		t.Fatalf("Expected registry to be tried after its mirror, got %s", pullAPIEndpoints[1].URL)
+	}
+	for _, pe := range pullAPIEndpoints {
+		if pe.URL.Host == "hub.mirror" {
+			t.Fatal("Pull endpoints of quay.io should not contain the Docker Hub mirror")
+		}
+	}
+
+	pushAPIEndpoints, err := s.LookupPushEndpoints("quay.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pushAPIEndpoints {
+		if pe.Mirror {
+			t.Fatal("Push endpoint should not contain mirror")
+		}
+	}
+
+	pullAPIEndpoints, err = s.LookupPullEndpoints("other.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pullAPIEndpoints {
+		if pe.Mirror {
+			t.Fatalf("Unexpected mirror endpoint %s for other.com", pe.URL)
+		}
+	}
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNamed(REPO)
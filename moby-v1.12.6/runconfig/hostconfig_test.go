@@ -128,6 +128,10 @@ func TestUsernsModeTest(t *testing.T) {
 		"something:weird": {true, false, false},
 		"host":            {false, true, true},
 		"host:name":       {true, false, true},
+		"auto":            {true, false, true},
+		"auto:tenant":     {true, false, true},
+		"auto:":           {true, false, false},
+		"auto:a:b":        {true, false, false},
 	}
 	for usernsMode, state := range usrensMode {
 		if usernsMode.IsPrivate() != state[0] {
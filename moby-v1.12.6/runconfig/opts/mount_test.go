@@ -0,0 +1,73 @@
+package opts
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/docker/pkg/testutil/assert"
+	mounttypes "github.com/docker/engine-api/types/mount"
+)
+
+func TestMountOptSetNoError(t *testing.T) {
+	for _, testcase := range []string{
+		// tests several aliases that should have same result.
+		"type=bind,target=/target,source=/source",
+		"type=bind,src=/source,dst=/target",
+		"type=bind,source=/source,dst=/target",
+		"type=bind,src=/source,target=/target",
+	} {
+		var mount MountOpt
+
+		assert.NilError(t, mount.Set(testcase))
+
+		mounts := mount.Value()
+		assert.Equal(t, len(mounts), 1)
+		assert.Equal(t, mounts[0], mounttypes.Mount{
+			Type:   mounttypes.TypeBind,
+			Source: "/source",
+			Target: "/target",
+		})
+	}
+}
+
+func TestMountOptDefaultType(t *testing.T) {
+	var mount MountOpt
+	assert.NilError(t, mount.Set("target=/target,source=foo"))
+	assert.Equal(t, mount.values[0].Type, mounttypes.TypeVolume)
+}
+
+func TestMountOptVolumeOptions(t *testing.T) {
+	var mount MountOpt
+	assert.NilError(t, mount.Set("type=volume,src=foo,dst=/target,readonly,volume-driver=local,volume-opt=type=tmpfs,volume-opt=device=tmpfs"))
+	m := mount.Value()[0]
+	assert.Equal(t, m.ReadOnly, true)
+	assert.Equal(t, m.VolumeOptions.DriverConfig.Name, "local")
+	assert.Equal(t, m.VolumeOptions.DriverConfig.Options["type"], "tmpfs")
+	assert.Equal(t, m.VolumeOptions.DriverConfig.Options["device"], "tmpfs")
+}
+
+func TestMountOptTmpfsOptions(t *testing.T) {
+	var mount MountOpt
+	assert.NilError(t, mount.Set("type=tmpfs,dst=/target,tmpfs-size=64m,tmpfs-mode=1770"))
+	m := mount.Value()[0]
+	assert.Equal(t, m.TmpfsOptions.SizeBytes, int64(64*1024*1024))
+	assert.Equal(t, m.TmpfsOptions.Mode, os.ModeSticky|0770)
+}
+
+func TestMountOptSetErrors(t *testing.T) {
+	for value, expected := range map[string]string{
+		"type=volume,source=foo":                           "target is required",
+		"type=volume,target=/foo,bogus=foo":                "unexpected key 'bogus'",
+		"type=volume,target=/foo,bogus":                    "invalid field 'bogus'",
+		"type=volume,target=/foo,readonly=no":              "invalid value for readonly: no",
+		"type=volume,target=/foo,volume-opt=o=bar":         "source is required",
+		"type=bind,src=/foo,target=/foo,volume-driver=foo": "cannot mix 'volume-*' options with mount type 'bind'",
+		"type=volume,target=/foo,bind-propagation=shared":  "cannot mix 'bind-*' options with mount type 'volume'",
+		"type=bind,src=/foo,target=/foo,tmpfs-size=1m":     "cannot mix 'tmpfs-*' options with mount type 'bind'",
+		"type=tmpfs,target=/foo,tmpfs-size=big":            "invalid value for tmpfs-size: big",
+		"type=tmpfs,target=/foo,tmpfs-mode=999":            "invalid value for tmpfs-mode: 999",
+	} {
+		var mount MountOpt
+		assert.Error(t, mount.Set(value), expected)
+	}
+}
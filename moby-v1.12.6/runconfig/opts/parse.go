@@ -27,6 +27,8 @@ type ContainerOptions struct {
 	flAttach            opts.ListOpts
 	flVolumes           opts.ListOpts
 	flTmpfs             opts.ListOpts
+	flWritablePaths     opts.ListOpts
+	flMounts            MountOpt
 	flBlkioWeightDevice WeightdeviceOpt
 	flDeviceReadBps     ThrottledeviceOpt
 	flDeviceWriteBps    ThrottledeviceOpt
@@ -71,6 +73,10 @@ type ContainerOptions struct {
 	flMemoryReservation string
 	flMemorySwap        string
 	flKernelMemory      string
+	flNetIngressRate    string
+	flNetIngressBurst   string
+	flNetEgressRate     string
+	flNetEgressBurst    string
 	flUser              string
 	flWorkingDir        string
 	flCPUShares         int64
@@ -139,6 +145,7 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 		flStorageOpt:        opts.NewListOpts(nil),
 		flSysctls:           opts.NewMapOpts(nil, opts.ValidateSysctl),
 		flTmpfs:             opts.NewListOpts(nil),
+		flWritablePaths:     opts.NewListOpts(nil),
 		flUlimits:           NewUlimitOpt(nil),
 		flVolumes:           opts.NewListOpts(nil),
 		flVolumesFrom:       opts.NewListOpts(nil),
@@ -192,6 +199,10 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.Var(&copts.flAliases, "net-alias", "Add network-scoped alias for the container")
 	flags.Var(&copts.flAliases, "network-alias", "Add network-scoped alias for the container")
 	flags.MarkHidden("net-alias")
+	flags.StringVar(&copts.flNetEgressBurst, "network-egress-burst", "", "Burst size of the network egress rate limit")
+	flags.StringVar(&copts.flNetEgressRate, "network-egress-rate", "", "Limit the rate (bytes per second) of the traffic sent by the container")
+	flags.StringVar(&copts.flNetIngressBurst, "network-ingress-burst", "", "Burst size of the network ingress rate limit")
+	flags.StringVar(&copts.flNetIngressRate, "network-ingress-rate", "", "Limit the rate (bytes per second) of the traffic received by the container")
 
 	// Logging and storage
 	flags.StringVar(&copts.flLoggingDriver, "log-driver", "", "Logging driver for the container")
@@ -199,6 +210,8 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.Var(&copts.flLoggingOpts, "log-opt", "Log driver options")
 	flags.Var(&copts.flStorageOpt, "storage-opt", "Storage driver options for the container")
 	flags.Var(&copts.flTmpfs, "tmpfs", "Mount a tmpfs directory")
+	flags.Var(&copts.flWritablePaths, "read-only-writable-paths", "Mount a tmpfs on comma-separated paths to keep them writable with a read-only root filesystem")
+	flags.Var(&copts.flMounts, "mount", "Attach a filesystem mount to the container")
 	flags.Var(&copts.flVolumesFrom, "volumes-from", "Mount volumes from the specified container(s)")
 	flags.VarP(&copts.flVolumes, "volume", "v", "Bind mount a volume")
 
@@ -308,6 +321,15 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		}
 	}
 
+	netIngressRate, netIngressBurst, err := ParseNetworkLimit(copts.flNetIngressRate, copts.flNetIngressBurst)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	netEgressRate, netEgressBurst, err := ParseNetworkLimit(copts.flNetEgressRate, copts.flNetEgressBurst)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	swappiness := copts.flSwappiness
 	if swappiness != -1 && (swappiness < 0 || swappiness > 100) {
 		return nil, nil, nil, fmt.Errorf("invalid value: %d. Valid memory swappiness range is 0-100", swappiness)
@@ -357,6 +379,18 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		}
 	}
 
+	var writablePaths []string
+	for _, p := range copts.flWritablePaths.GetAll() {
+		for _, path := range strings.Split(p, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				writablePaths = append(writablePaths, path)
+			}
+		}
+	}
+	if len(writablePaths) > 0 && !copts.flReadonlyRootfs {
+		return nil, nil, nil, fmt.Errorf("--read-only-writable-paths requires --read-only")
+	}
+
 	var (
 		runCmd     strslice.StrSlice
 		entrypoint strslice.StrSlice
@@ -408,13 +442,13 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 	}
 
 	// collect all the environment variables for the container
-	envVariables, err := readKVStrings(copts.flEnvFile.GetAll(), copts.flEnv.GetAll())
+	envVariables, err := ReadKVStrings(copts.flEnvFile.GetAll(), copts.flEnv.GetAll())
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
 	// collect all the labels for the container
-	labels, err := readKVStrings(copts.flLabelsFile.GetAll(), copts.flLabels.GetAll())
+	labels, err := ReadKVStrings(copts.flLabelsFile.GetAll(), copts.flLabels.GetAll())
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -499,6 +533,10 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		MemorySwap:           memorySwap,
 		MemorySwappiness:     &copts.flSwappiness,
 		KernelMemory:         KernelMemory,
+		NetworkIngressRate:   netIngressRate,
+		NetworkIngressBurst:  netIngressBurst,
+		NetworkEgressRate:    netEgressRate,
+		NetworkEgressBurst:   netEgressBurst,
 		OomKillDisable:       &copts.flOomKillDisable,
 		CPUPercent:           copts.flCPUPercent,
 		CPUShares:            copts.flCPUShares,
@@ -582,10 +620,13 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		ShmSize:        shmSize,
 		Resources:      resources,
 		Tmpfs:          tmpfs,
+		Mounts:         copts.flMounts.Value(),
 		Sysctls:        copts.flSysctls.GetAll(),
 		Runtime:        copts.flRuntime,
 	}
 
+	hostConfig.ReadonlyWritablePaths = writablePaths
+
 	// When allocating stdin in attached mode, close stdin at client disconnect
 	if config.OpenStdin && config.AttachStdin {
 		config.StdinOnce = true
@@ -633,9 +674,9 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 	return config, hostConfig, networkingConfig, nil
 }
 
-// reads a file of line terminated key=value pairs, and overrides any keys
+// ReadKVStrings reads a file of line terminated key=value pairs, and overrides any keys
 // present in the file with additional pairs specified in the override parameter
-func readKVStrings(files []string, override []string) ([]string, error) {
+func ReadKVStrings(files []string, override []string) ([]string, error) {
 	envVariables := []string{}
 	for _, ef := range files {
 		parsedVars, err := ParseEnvFile(ef)
@@ -684,7 +725,7 @@ func parseSecurityOpts(securityOpts []string) ([]string, error) {
 				return securityOpts, fmt.Errorf("Invalid --security-opt: %q", opt)
 			}
 		}
-		if con[0] == "seccomp" && con[1] != "unconfined" {
+		if con[0] == "seccomp" && con[1] != "unconfined" && con[1] != "record" {
 			f, err := ioutil.ReadFile(con[1])
 			if err != nil {
 				return securityOpts, fmt.Errorf("opening seccomp profile (%s) failed: %v", con[1], err)
@@ -714,6 +755,34 @@ func parseStorageOpts(storageOpts []string) (map[string]string, error) {
 	return m, nil
 }
 
+// ParseNetworkLimit parses the rate, in bytes per second, and the burst size
+// of a network rate limit. A rate of '-1' removes the limit.
+func ParseNetworkLimit(rate, burst string) (int64, int64, error) {
+	var (
+		r, b int64
+		err  error
+	)
+	if rate == "-1" {
+		r = -1
+	} else if rate != "" {
+		if r, err = units.RAMInBytes(rate); err != nil {
+			return 0, 0, err
+		}
+		if r <= 0 {
+			return 0, 0, fmt.Errorf("invalid network rate %s: must be positive, or -1 for unlimited", rate)
+		}
+	}
+	if burst != "" {
+		if b, err = units.RAMInBytes(burst); err != nil {
+			return 0, 0, err
+		}
+		if b <= 0 {
+			return 0, 0, fmt.Errorf("invalid network burst %s: must be positive", burst)
+		}
+	}
+	return r, b, nil
+}
+
 // ParseRestartPolicy returns the parsed policy or an error indicating what is incorrect
 func ParseRestartPolicy(policy string) (container.RestartPolicy, error) {
 	p := container.RestartPolicy{}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -389,6 +390,40 @@ func TestParseWithMemorySwap(t *testing.T) {
 	}
 }
 
+func TestParseWithNetworkLimits(t *testing.T) {
+	invalids := map[string]string{
+		"--network-ingress-rate=invalid": "invalid size: 'invalid'",
+		"--network-egress-rate=0":        "invalid network rate 0: must be positive, or -1 for unlimited",
+		"--network-egress-burst=0":       "invalid network burst 0: must be positive",
+	}
+	for flag, expectedError := range invalids {
+		if _, _, _, err := parseRun([]string{flag, "img", "cmd"}); err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected an error with message '%v' for %v, got %v", expectedError, flag, err)
+		}
+	}
+
+	_, hostconfig := mustParse(t, "--network-ingress-rate=1m --network-ingress-burst=64k --network-egress-rate=-1")
+	if hostconfig.NetworkIngressRate != 1048576 || hostconfig.NetworkIngressBurst != 65536 {
+		t.Fatalf("Expected an ingress rate of 1048576 and a burst of 65536, got %d and %d", hostconfig.NetworkIngressRate, hostconfig.NetworkIngressBurst)
+	}
+	if hostconfig.NetworkEgressRate != -1 || hostconfig.NetworkEgressBurst != 0 {
+		t.Fatalf("Expected an egress rate of -1 and no burst, got %d and %d", hostconfig.NetworkEgressRate, hostconfig.NetworkEgressBurst)
+	}
+}
+
+func TestParseWithReadonlyWritablePaths(t *testing.T) {
+	expectedError := "--read-only-writable-paths requires --read-only"
+	if _, _, _, err := parseRun([]string{"--read-only-writable-paths=/tmp", "img", "cmd"}); err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected an error with message '%v', got %v", expectedError, err)
+	}
+
+	_, hostconfig := mustParse(t, "--read-only --read-only-writable-paths=/tmp,/var/run --read-only-writable-paths=/var/cache")
+	expected := []string{"/tmp", "/var/run", "/var/cache"}
+	if !reflect.DeepEqual(hostconfig.ReadonlyWritablePaths, expected) {
+		t.Fatalf("Expected writable paths %v, got %v", expected, hostconfig.ReadonlyWritablePaths)
+	}
+}
+
 func TestParseHostname(t *testing.T) {
 	validHostnames := map[string]string{
 		"hostname":    "hostname",
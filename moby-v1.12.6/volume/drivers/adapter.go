@@ -51,6 +51,7 @@ func (a *volumeDriverAdapter) List() ([]volume.Volume, error) {
 			name:       vp.Name,
 			driverName: a.name,
 			eMount:     vp.Mountpoint,
+			status:     vp.Status,
 		})
 	}
 	return out, nil
@@ -76,6 +77,22 @@ func (a *volumeDriverAdapter) Get(name string) (volume.Volume, error) {
 	}, nil
 }
 
+func (a *volumeDriverAdapter) Snapshot(v volume.Volume, name string, opts map[string]string) (volume.Volume, error) {
+	// `Snapshot` is not a required endpoint, only call the plugins which
+	// advertise it in their capabilities.
+	if !a.getCapabilities().Snapshot {
+		return nil, volume.ErrSnapshotNotSupported
+	}
+	if err := a.proxy.Snapshot(v.Name(), name, opts); err != nil {
+		return nil, err
+	}
+	return &volumeAdapter{
+		proxy:      a.proxy,
+		name:       name,
+		driverName: a.name,
+	}, nil
+}
+
 func (a *volumeDriverAdapter) Scope() string {
 	cap := a.getCapabilities()
 	return cap.Scope
@@ -44,6 +44,8 @@ type volumeDriver interface {
 	Get(name string) (volume *proxyVolume, err error)
 	// Capabilities gets the list of capabilities of the driver
 	Capabilities() (capabilities volume.Capability, err error)
+	// Snapshot creates the volume named target as a copy of the given volume
+	Snapshot(name, target string, opts map[string]string) (err error)
 }
 
 type driverExtpoint struct {
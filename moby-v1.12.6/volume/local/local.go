@@ -11,8 +11,10 @@ import (
 	"path/filepath"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/utils"
@@ -342,3 +344,25 @@ func validateOpts(opts map[string]string) error {
 func (v *localVolume) Status() map[string]interface{} {
 	return nil
 }
+
+// Size returns the size of the data of the volume. The data of a volume
+// mounted from another filesystem is only known while it is mounted.
+func (v *localVolume) Size() (int64, error) {
+	v.m.Lock()
+	mounted := v.opts == nil || v.active.mounted
+	v.m.Unlock()
+	if !mounted {
+		return -1, nil
+	}
+	return directory.Size(v.path)
+}
+
+// CreatedAt returns the time the volume was created, which is the
+// modification time of its directory since nothing is added to it later.
+func (v *localVolume) CreatedAt() (time.Time, error) {
+	fi, err := os.Stat(filepath.Dir(v.path))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fi.ModTime(), nil
+}
@@ -8,8 +8,10 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/volume"
 )
 
 func TestRemove(t *testing.T) {
@@ -326,3 +328,57 @@ func TestRealodNoOpts(t *testing.T) {
 		}
 	}
 }
+
+func TestUsage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := r.Create("testing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(v.Path(), "data"), make([]byte, 1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(filepath.Join(v.Path(), "data"), filepath.Join(v.Path(), "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	uv, ok := v.(volume.UsageVolume)
+	if !ok {
+		t.Fatalf("Expected local volumes to report their usage")
+	}
+	size, err := uv.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 1024 {
+		t.Fatalf("Expected a size of 1024 bytes, got %d", size)
+	}
+	createdAt, err := uv.CreatedAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if createdAt.IsZero() || createdAt.After(time.Now()) {
+		t.Fatalf("Unexpected creation time %v", createdAt)
+	}
+
+	v, err = r.Create("testing-opts", map[string]string{"type": "tmpfs", "device": "tmpfs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size, err := v.(volume.UsageVolume).Size(); err != nil || size != -1 {
+		t.Fatalf("Expected an unknown size for an unmounted volume, got %d: %v", size, err)
+	}
+}
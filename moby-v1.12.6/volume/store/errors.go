@@ -3,6 +3,8 @@ package store
 import (
 	"errors"
 	"strings"
+
+	"github.com/docker/docker/volume"
 )
 
 var (
@@ -63,6 +65,12 @@ func IsNameConflict(err error) bool {
 	return isErr(err, errNameConflict)
 }
 
+// IsNotSupported returns a boolean indicating whether the error indicates that
+// the driver of a volume cannot copy it on its own
+func IsNotSupported(err error) bool {
+	return isErr(err, volume.ErrSnapshotNotSupported)
+}
+
 func isErr(err error, expected error) bool {
 	switch pe := err.(type) {
 	case nil:
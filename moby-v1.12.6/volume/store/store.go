@@ -1,7 +1,6 @@
 package store
 
 import (
-	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -21,14 +20,35 @@ const (
 )
 
 type volumeMetadata struct {
-	Name   string
-	Labels map[string]string
+	Name       string
+	Labels     map[string]string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
 }
 
 type volumeWrapper struct {
 	volume.Volume
 	labels map[string]string
 	scope  string
+	store  *VolumeStore
+}
+
+// Mount mounts the volume, and records the time it was used.
+func (v volumeWrapper) Mount(id string) (string, error) {
+	path, err := v.Volume.Mount(id)
+	if err == nil {
+		v.store.touch(v.Name())
+	}
+	return path, err
+}
+
+// Unmount unmounts the volume, and records the time it was used.
+func (v volumeWrapper) Unmount(id string) error {
+	err := v.Volume.Unmount(id)
+	if err == nil {
+		v.store.touch(v.Name())
+	}
+	return err
 }
 
 func (v volumeWrapper) Labels() map[string]string {
@@ -202,7 +222,7 @@ func (s *VolumeStore) list() ([]volume.Volume, []string, error) {
 			}
 			for i, v := range vs {
 				s.globalLock.RLock()
-				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope()}
+				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope(), s}
 				s.globalLock.RUnlock()
 			}
 
@@ -251,6 +271,41 @@ func (s *VolumeStore) CreateWithRef(name, driverName, ref string, opts, labels m
 	return v, nil
 }
 
+// CreateNewWithRef is like CreateWithRef, except that it fails with an error
+// satisfying IsNameConflict if a volume with the name already exists, instead
+// of returning the existing volume.
+func (s *VolumeStore) CreateNewWithRef(name, driverName, ref string, opts, labels map[string]string) (volume.Volume, error) {
+	name = normaliseVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	vd, err := volumedrivers.GetDriver(driverName)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "create"}
+	}
+	if s.nameInUse(name, vd) {
+		return nil, &OpErr{Err: errNameConflict, Name: name, Op: "create"}
+	}
+
+	v, err := s.create(name, driverName, opts, labels)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "create"}
+	}
+
+	s.setNamed(v, ref)
+	return v, nil
+}
+
+// nameInUse returns whether a volume with the name is known to the store or
+// to the driver. It is expected that callers hold the lock of the name.
+func (s *VolumeStore) nameInUse(name string, vd volume.Driver) bool {
+	if _, exists := s.getNamed(name); exists {
+		return true
+	}
+	existing, _ := vd.Get(name)
+	return existing != nil
+}
+
 // Create creates a volume with the given name and driver.
 func (s *VolumeStore) Create(name, driverName string, opts, labels map[string]string) (volume.Volume, error) {
 	name = normaliseVolumeName(name)
@@ -309,31 +364,128 @@ func (s *VolumeStore) create(name, driverName string, opts, labels map[string]st
 	if err != nil {
 		return nil, err
 	}
+	if err := s.setMetadata(name, labels); err != nil {
+		return nil, err
+	}
+
+	return volumeWrapper{v, labels, vd.Scope(), s}, nil
+}
+
+// setMetadata stores the labels of a new volume with the given name, both in
+// memory and in the metadata of the volume, along with its creation time.
+func (s *VolumeStore) setMetadata(name string, labels map[string]string) error {
 	s.globalLock.Lock()
 	s.labels[name] = labels
 	s.globalLock.Unlock()
 
-	if s.db != nil {
-		metadata := &volumeMetadata{
-			Name:   name,
-			Labels: labels,
+	if s.db == nil {
+		return nil
+	}
+
+	metadata := &volumeMetadata{
+		Name:      name,
+		Labels:    labels,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	volData, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(volumeBucketName))
+		err := b.Put([]byte(name), volData)
+		return err
+	})
+}
+
+// getMetadata returns the metadata stored for the volume with the given
+// name, which is empty for volumes the store did not create.
+func (s *VolumeStore) getMetadata(name string) (volumeMetadata, error) {
+	meta := volumeMetadata{Name: name}
+	if s.db == nil {
+		return meta, nil
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(volumeBucketName))
+		data := b.Get([]byte(name))
+		if len(data) == 0 {
+			return nil
 		}
+		return json.Unmarshal(data, &meta)
+	})
+	return meta, err
+}
 
-		volData, err := json.Marshal(metadata)
-		if err != nil {
-			return nil, err
+// touch records that the volume with the given name was just mounted or
+// unmounted.
+func (s *VolumeStore) touch(name string) {
+	if s.db == nil {
+		return
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(volumeBucketName))
+		meta := volumeMetadata{Name: name}
+		if data := b.Get([]byte(name)); len(data) > 0 {
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return err
+			}
 		}
+		meta.LastUsedAt = time.Now().UTC()
 
-		if err := s.db.Update(func(tx *bolt.Tx) error {
-			b := tx.Bucket([]byte(volumeBucketName))
-			err := b.Put([]byte(name), volData)
+		volData, err := json.Marshal(meta)
+		if err != nil {
 			return err
-		}); err != nil {
-			return nil, err
 		}
+		return b.Put([]byte(name), volData)
+	})
+	if err != nil {
+		logrus.Errorf("Error updating the last use of volume %s: %v", name, err)
 	}
+}
 
-	return volumeWrapper{v, labels, vd.Scope()}, nil
+// Snapshot asks the driver of the given volume to create a volume with the
+// name as a copy of it. An error satisfying IsNotSupported is returned if
+// the driver cannot copy volumes on its own, and IsNameConflict if a volume
+// with the name already exists.
+func (s *VolumeStore) Snapshot(v volume.Volume, name string, opts, labels map[string]string) (volume.Volume, error) {
+	name = normaliseVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	valid, err := volume.IsVolumeNameValid(name)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "snapshot"}
+	}
+	if !valid {
+		return nil, &OpErr{Err: errInvalidName, Name: name, Op: "snapshot"}
+	}
+
+	vd, err := volumedrivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "snapshot"}
+	}
+	if s.nameInUse(name, vd) {
+		return nil, &OpErr{Err: errNameConflict, Name: name, Op: "snapshot"}
+	}
+	sd, ok := vd.(volume.SnapshotDriver)
+	if !ok {
+		return nil, &OpErr{Err: volume.ErrSnapshotNotSupported, Name: name, Op: "snapshot"}
+	}
+
+	snapshot, err := sd.Snapshot(unwrapVolume(v), name, opts)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "snapshot"}
+	}
+	if err := s.setMetadata(name, labels); err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "snapshot"}
+	}
+
+	s.setNamed(snapshot, "")
+	return volumeWrapper{snapshot, labels, vd.Scope(), s}, nil
 }
 
 // GetWithRef gets a volume with the given name from the passed in driver and stores the ref
@@ -358,7 +510,7 @@ func (s *VolumeStore) GetWithRef(name, driverName, ref string) (volume.Volume, e
 
 	s.globalLock.RLock()
 	defer s.globalLock.RUnlock()
-	return volumeWrapper{v, s.labels[name], vd.Scope()}, nil
+	return volumeWrapper{v, s.labels[name], vd.Scope(), s}, nil
 }
 
 // Get looks if a volume with the given name exists and returns it if so
@@ -379,30 +531,13 @@ func (s *VolumeStore) Get(name string) (volume.Volume, error) {
 // if the driver is unknown it probes all drivers until it finds the first volume with that name.
 // it is expected that callers of this function hold any necessary locks
 func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
-	labels := map[string]string{}
-
-	if s.db != nil {
-		// get meta
-		if err := s.db.Update(func(tx *bolt.Tx) error {
-			b := tx.Bucket([]byte(volumeBucketName))
-			data := b.Get([]byte(name))
-
-			if string(data) == "" {
-				return nil
-			}
-
-			var meta volumeMetadata
-			buf := bytes.NewBuffer(data)
-
-			if err := json.NewDecoder(buf).Decode(&meta); err != nil {
-				return err
-			}
-			labels = meta.Labels
-
-			return nil
-		}); err != nil {
-			return nil, err
-		}
+	meta, err := s.getMetadata(name)
+	if err != nil {
+		return nil, err
+	}
+	labels := meta.Labels
+	if labels == nil {
+		labels = map[string]string{}
 	}
 
 	logrus.Debugf("Getting volume reference for name: %s", name)
@@ -418,7 +553,7 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 		if err != nil {
 			return nil, err
 		}
-		return volumeWrapper{vol, labels, vd.Scope()}, nil
+		return volumeWrapper{vol, labels, vd.Scope(), s}, nil
 	}
 
 	logrus.Debugf("Probing all drivers for volume with name: %s", name)
@@ -433,7 +568,7 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 			continue
 		}
 
-		return volumeWrapper{v, labels, d.Scope()}, nil
+		return volumeWrapper{v, labels, d.Scope(), s}, nil
 	}
 	return nil, errNoSuchVolume
 }
@@ -470,7 +605,6 @@ func (s *VolumeStore) Dereference(v volume.Volume, ref string) {
 	defer s.locks.Unlock(v.Name())
 
 	s.globalLock.Lock()
-	defer s.globalLock.Unlock()
 	var refs []string
 
 	for _, r := range s.refs[v.Name()] {
@@ -479,6 +613,7 @@ func (s *VolumeStore) Dereference(v volume.Volume, ref string) {
 		}
 	}
 	s.refs[v.Name()] = refs
+	s.globalLock.Unlock()
 }
 
 // Refs gets the current list of refs for the given volume
@@ -492,6 +627,54 @@ func (s *VolumeStore) Refs(v volume.Volume) []string {
 	return refsOut
 }
 
+// Usage describes the disk space used by a volume and when it was used.
+type Usage struct {
+	// Size is the size of the volume in bytes, or -1 if it is not known.
+	Size int64
+	// CreatedAt is the time the volume was created, zero if not known.
+	CreatedAt time.Time
+	// LastUsedAt is the last time the volume was mounted or unmounted, zero
+	// if not known.
+	LastUsedAt time.Time
+	// Refs is the list of things referencing the volume.
+	Refs []string
+}
+
+// Usage returns the usage of the given volume. The size of the volume is
+// only computed if size is set since it walks through the volume data, and
+// only for the drivers which can do it without mounting the volume.
+func (s *VolumeStore) Usage(v volume.Volume, size bool) (Usage, error) {
+	name := normaliseVolumeName(v.Name())
+	meta, err := s.getMetadata(name)
+	if err != nil {
+		return Usage{}, &OpErr{Err: err, Name: name, Op: "usage"}
+	}
+
+	u := Usage{
+		Size:       -1,
+		CreatedAt:  meta.CreatedAt,
+		LastUsedAt: meta.LastUsedAt,
+		Refs:       s.Refs(v),
+	}
+
+	uv, ok := unwrapVolume(v).(volume.UsageVolume)
+	if !ok {
+		return u, nil
+	}
+	// Volumes created before the store recorded creation times
+	if u.CreatedAt.IsZero() {
+		if u.CreatedAt, err = uv.CreatedAt(); err != nil {
+			return Usage{}, &OpErr{Err: err, Name: name, Op: "usage"}
+		}
+	}
+	if size {
+		if u.Size, err = uv.Size(); err != nil {
+			return Usage{}, &OpErr{Err: err, Name: name, Op: "usage"}
+		}
+	}
+	return u, nil
+}
+
 // FilterByDriver returns the available volumes filtered by driver name
 func (s *VolumeStore) FilterByDriver(name string) ([]volume.Volume, error) {
 	vd, err := volumedrivers.GetDriver(name)
@@ -504,7 +687,7 @@ func (s *VolumeStore) FilterByDriver(name string) ([]volume.Volume, error) {
 	}
 	s.globalLock.RLock()
 	for i, v := range ls {
-		ls[i] = volumeWrapper{v, s.labels[v.Name()], vd.Scope()}
+		ls[i] = volumeWrapper{v, s.labels[v.Name()], vd.Scope(), s}
 	}
 	s.globalLock.RUnlock()
 	return ls, nil
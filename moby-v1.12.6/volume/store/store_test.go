@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/volume"
 	"github.com/docker/docker/volume/drivers"
 	vt "github.com/docker/docker/volume/testutils"
 )
@@ -213,3 +214,146 @@ func TestDerefMultipleOfSameRef(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSnapshot(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fakecopy"), "fakecopy")
+	volumedrivers.Register(vt.NewFakeSnapshotDriver("fakesnapshot"), "fakesnapshot")
+	defer volumedrivers.Unregister("fakecopy")
+	defer volumedrivers.Unregister("fakesnapshot")
+	s, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := s.Create("fakecopy1", "fakecopy", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Snapshot(v, "fakecopy2", nil, nil); !IsNotSupported(err) {
+		t.Fatalf("Expected not supported error, got %v", err)
+	}
+
+	v, err = s.Create("fake3", "fakesnapshot", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Snapshot(v, "fakecopy1", nil, nil); !IsNameConflict(err) {
+		t.Fatalf("Expected name conflict error, got %v", err)
+	}
+
+	snapshot, err := s.Snapshot(v, "fake4", nil, map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Name() != "fake4" || snapshot.DriverName() != "fakesnapshot" {
+		t.Fatalf("Expected fake4 volume of the fakesnapshot driver, got %v", snapshot)
+	}
+	if labels := snapshot.(volume.LabeledVolume).Labels(); labels["a"] != "b" {
+		t.Fatalf("Expected the labels of the snapshot to be set, got %v", labels)
+	}
+	if _, err := s.Get("fake4"); err != nil {
+		t.Fatalf("Expected the snapshot to be found in the store: %v", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fakeusage"), "fakeusage")
+	defer volumedrivers.Unregister("fakeusage")
+
+	dir, err := ioutil.TempDir("", "test-usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := s.CreateWithRef("fakeusage1", "fakeusage", "ref1", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.Usage(v, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Size != -1 {
+		t.Fatalf("Expected an unknown size, got %d", u.Size)
+	}
+	if u.CreatedAt.IsZero() || !u.LastUsedAt.IsZero() {
+		t.Fatalf("Expected the creation time to be recorded, and the volume not to be used yet, got %v and %v", u.CreatedAt, u.LastUsedAt)
+	}
+	if len(u.Refs) != 1 || u.Refs[0] != "ref1" {
+		t.Fatalf("Expected the volume to be referenced by ref1, got %v", u.Refs)
+	}
+
+	if _, err := v.Mount("ref1"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err = s.Usage(v, false); err != nil {
+		t.Fatal(err)
+	}
+	if u.LastUsedAt.IsZero() {
+		t.Fatal("Expected the last use time to be recorded on mount")
+	}
+
+	lastUsed := u.LastUsedAt
+	if err := v.Unmount("ref1"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err = s.Usage(v, false); err != nil {
+		t.Fatal(err)
+	}
+	if !u.LastUsedAt.After(lastUsed) {
+		t.Fatalf("Expected the last use time to be updated on unmount, got %v", u.LastUsedAt)
+	}
+
+	// references taken and released, like the ones restored when the
+	// daemon starts, are not uses of the volume
+	lastUsed = u.LastUsedAt
+	s.Dereference(v, "ref1")
+	if _, err := s.GetWithRef("fakeusage1", "fakeusage", "ref2"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err = s.Usage(v, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(u.Refs) != 1 || u.Refs[0] != "ref2" {
+		t.Fatalf("Expected the volume to be referenced by ref2, got %v", u.Refs)
+	}
+	if !u.LastUsedAt.Equal(lastUsed) {
+		t.Fatalf("Expected the last use time to be left as is, got %v", u.LastUsedAt)
+	}
+}
+
+func TestCreateNewWithRef(t *testing.T) {
+	fd := vt.NewFakeDriver("fake")
+	volumedrivers.Register(fd, "fake")
+	defer volumedrivers.Unregister("fake")
+	s, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := s.CreateNewWithRef("fake1", "fake", "ref1", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateNewWithRef("fake1", "fake", "ref2", nil, nil); !IsNameConflict(err) {
+		t.Fatalf("Expected name conflict error, got %v", err)
+	}
+	// the failed creation does not hold a reference to the existing volume
+	if refs := s.Refs(v); len(refs) != 1 || refs[0] != "ref1" {
+		t.Fatalf("Expected the volume to be referenced by ref1 only, got %v", refs)
+	}
+
+	// a volume of the driver unknown to the store is not taken over
+	if _, err := fd.Create("fake2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateNewWithRef("fake2", "fake", "ref1", nil, nil); !IsNameConflict(err) {
+		t.Fatalf("Expected name conflict error, got %v", err)
+	}
+}
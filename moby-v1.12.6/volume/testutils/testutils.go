@@ -114,3 +114,21 @@ func (d *FakeDriver) Get(name string) (volume.Volume, error) {
 func (*FakeDriver) Scope() string {
 	return "local"
 }
+
+// FakeSnapshotDriver is a FakeDriver supporting snapshots
+type FakeSnapshotDriver struct {
+	*FakeDriver
+}
+
+// NewFakeSnapshotDriver creates a new FakeSnapshotDriver with the specified name
+func NewFakeSnapshotDriver(name string) volume.Driver {
+	return FakeSnapshotDriver{NewFakeDriver(name).(*FakeDriver)}
+}
+
+// Snapshot creates a fake volume with the given name, as a copy of the volume.
+func (d FakeSnapshotDriver) Snapshot(v volume.Volume, name string, opts map[string]string) (volume.Volume, error) {
+	if _, exists := d.vols[v.Name()]; !exists {
+		return nil, fmt.Errorf("no such volume")
+	}
+	return d.Create(name, opts)
+}
@@ -0,0 +1,142 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/docker/engine-api/types/mount"
+)
+
+var errBindNotExist = errors.New("bind source path does not exist")
+
+type errMountConfig struct {
+	mount *mount.Mount
+	err   error
+}
+
+func (e *errMountConfig) Error() string {
+	return fmt.Sprintf("invalid mount config for type %q: %v", e.mount.Type, e.err.Error())
+}
+
+func errExtraField(name string) error {
+	return fmt.Errorf("field %s must not be specified", name)
+}
+
+func errMissingField(name string) error {
+	return fmt.Errorf("field %s must not be empty", name)
+}
+
+// ValidateMountConfig validates the configuration of a mount set in the
+// Mounts field of the host config of a container.
+func ValidateMountConfig(mnt *mount.Mount) error {
+	if len(mnt.Target) == 0 {
+		return &errMountConfig{mnt, errMissingField("Target")}
+	}
+	if err := validateMountTarget(mnt.Target); err != nil {
+		return &errMountConfig{mnt, err}
+	}
+
+	switch mnt.Type {
+	case mount.TypeBind:
+		if len(mnt.Source) == 0 {
+			return &errMountConfig{mnt, errMissingField("Source")}
+		}
+		if mnt.VolumeOptions != nil {
+			return &errMountConfig{mnt, errExtraField("VolumeOptions")}
+		}
+		if mnt.TmpfsOptions != nil {
+			return &errMountConfig{mnt, errExtraField("TmpfsOptions")}
+		}
+		if mnt.BindOptions != nil && len(mnt.BindOptions.Propagation) > 0 {
+			if !propagationModes[string(mnt.BindOptions.Propagation)] {
+				return &errMountConfig{mnt, fmt.Errorf("invalid propagation mode: %s", mnt.BindOptions.Propagation)}
+			}
+		}
+		if !filepath.IsAbs(mnt.Source) {
+			return &errMountConfig{mnt, errors.New("invalid bind source, source must be an absolute path")}
+		}
+		if _, err := os.Stat(mnt.Source); err != nil {
+			if os.IsNotExist(err) {
+				return &errMountConfig{mnt, errBindNotExist}
+			}
+			return &errMountConfig{mnt, err}
+		}
+	case mount.TypeVolume:
+		if mnt.BindOptions != nil {
+			return &errMountConfig{mnt, errExtraField("BindOptions")}
+		}
+		if mnt.TmpfsOptions != nil {
+			return &errMountConfig{mnt, errExtraField("TmpfsOptions")}
+		}
+		if len(mnt.Source) > 0 {
+			if filepath.IsAbs(mnt.Source) {
+				return &errMountConfig{mnt, errors.New("invalid volume name, use a bind mount to mount a host path")}
+			}
+			if valid, err := IsVolumeNameValid(mnt.Source); !valid {
+				if err == nil {
+					err = errors.New("invalid volume name")
+				}
+				return &errMountConfig{mnt, err}
+			}
+		}
+		if len(mnt.Source) == 0 && mnt.VolumeOptions != nil && mnt.VolumeOptions.DriverConfig != nil && len(mnt.VolumeOptions.DriverConfig.Options) > 0 {
+			return &errMountConfig{mnt, errors.New("volume options require a volume name")}
+		}
+	case mount.TypeTmpfs:
+		if runtime.GOOS == "windows" {
+			return &errMountConfig{mnt, errors.New("tmpfs mounts are not supported on this platform")}
+		}
+		if len(mnt.Source) != 0 {
+			return &errMountConfig{mnt, errExtraField("Source")}
+		}
+		if mnt.BindOptions != nil {
+			return &errMountConfig{mnt, errExtraField("BindOptions")}
+		}
+		if mnt.VolumeOptions != nil {
+			return &errMountConfig{mnt, errExtraField("VolumeOptions")}
+		}
+		if mnt.TmpfsOptions != nil && mnt.TmpfsOptions.SizeBytes < 0 {
+			return &errMountConfig{mnt, fmt.Errorf("invalid tmpfs size %d", mnt.TmpfsOptions.SizeBytes)}
+		}
+	default:
+		return &errMountConfig{mnt, errors.New("mount type unknown")}
+	}
+	return nil
+}
+
+// ParseMountConfig returns the mount point of a bind or volume mount set in
+// the Mounts field of the host config of a container.
+func ParseMountConfig(mnt mount.Mount) (*MountPoint, error) {
+	if err := ValidateMountConfig(&mnt); err != nil {
+		return nil, err
+	}
+
+	mp := &MountPoint{
+		RW:          !mnt.ReadOnly,
+		Destination: filepath.Clean(mnt.Target),
+		Propagation: DefaultPropagationMode,
+	}
+
+	switch mnt.Type {
+	case mount.TypeBind:
+		mp.Source = filepath.Clean(mnt.Source)
+		if mnt.BindOptions != nil && len(mnt.BindOptions.Propagation) > 0 {
+			mp.Propagation = string(mnt.BindOptions.Propagation)
+		}
+	case mount.TypeVolume:
+		mp.Name = mnt.Source
+		mp.CopyData = DefaultCopyMode
+		if mnt.VolumeOptions != nil {
+			mp.CopyData = !mnt.VolumeOptions.NoCopy
+			if mnt.VolumeOptions.DriverConfig != nil {
+				mp.Driver = mnt.VolumeOptions.DriverConfig.Name
+			}
+		}
+	default:
+		return nil, &errMountConfig{&mnt, fmt.Errorf("mount type %s has no mount point", mnt.Type)}
+	}
+	return mp, nil
+}
@@ -0,0 +1,75 @@
+package volume
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/engine-api/types/mount"
+)
+
+func TestValidateMountConfig(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "test-validate-mount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	target := "/foo"
+	if runtime.GOOS == "windows" {
+		target = `c:\foo`
+	}
+
+	valid := []mount.Mount{
+		{Type: mount.TypeBind, Source: tmpdir, Target: target},
+		{Type: mount.TypeVolume, Target: target},
+		{Type: mount.TypeVolume, Source: "hello", Target: target},
+		{Type: mount.TypeVolume, Source: "hello", Target: target, VolumeOptions: &mount.VolumeOptions{DriverConfig: &mount.Driver{Name: "local", Options: map[string]string{"o": "bar"}}}},
+	}
+	for _, m := range valid {
+		if err := ValidateMountConfig(&m); err != nil {
+			t.Fatalf("expected %v to be valid, got %v", m, err)
+		}
+	}
+
+	invalid := map[string]mount.Mount{
+		"field Target must not be empty":   {Type: mount.TypeVolume},
+		"mount type unknown":               {Type: "foo", Target: target},
+		"field Source must not be empty":   {Type: mount.TypeBind, Target: target},
+		"bind source path does not exist":  {Type: mount.TypeBind, Source: tmpdir + "-missing", Target: target},
+		"field VolumeOptions must not be":  {Type: mount.TypeBind, Source: tmpdir, Target: target, VolumeOptions: &mount.VolumeOptions{}},
+		"field BindOptions must not be":    {Type: mount.TypeVolume, Target: target, BindOptions: &mount.BindOptions{}},
+		"use a bind mount":                 {Type: mount.TypeVolume, Source: tmpdir, Target: target},
+		"volume options require a volume":  {Type: mount.TypeVolume, Target: target, VolumeOptions: &mount.VolumeOptions{DriverConfig: &mount.Driver{Options: map[string]string{"o": "bar"}}}},
+		"field TmpfsOptions must not be":   {Type: mount.TypeVolume, Target: target, TmpfsOptions: &mount.TmpfsOptions{}},
+		"invalid mount config for type \"": {Type: mount.TypeTmpfs, Source: "foo", Target: target},
+	}
+	for expected, m := range invalid {
+		err := ValidateMountConfig(&m)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected an error containing %q for %v, got %v", expected, m, err)
+		}
+	}
+}
+
+func TestParseMountConfig(t *testing.T) {
+	target := "/foo/"
+	if runtime.GOOS == "windows" {
+		target = `c:\foo\`
+	}
+
+	mp, err := ParseMountConfig(mount.Mount{Type: mount.TypeVolume, Source: "hello", Target: target, ReadOnly: true, VolumeOptions: &mount.VolumeOptions{NoCopy: true, DriverConfig: &mount.Driver{Name: "local"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mp.Name != "hello" || mp.Driver != "local" || mp.RW || mp.CopyData || mp.Destination == target {
+		t.Fatalf("unexpected mount point %+v", mp)
+	}
+
+	if runtime.GOOS != "windows" {
+		if _, err := ParseMountConfig(mount.Mount{Type: mount.TypeTmpfs, Target: target}); err == nil {
+			t.Fatal("expected an error for a tmpfs mount")
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package volume
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/pkg/system"
@@ -46,6 +48,23 @@ type Capability struct {
 	// A `local` scope indicates that the driver only manages volumes resources local to the host
 	// Scope is declared by the driver
 	Scope string
+	// Snapshot indicates that the driver can copy a volume into a new one
+	// on its own, see SnapshotDriver
+	Snapshot bool
+}
+
+// ErrSnapshotNotSupported is returned by the drivers which cannot copy a
+// volume on their own.
+var ErrSnapshotNotSupported = errors.New("volume driver does not support snapshots")
+
+// SnapshotDriver is a driver able to create a volume as a copy of another
+// one of its volumes, typically faster than copying the files of the volume.
+type SnapshotDriver interface {
+	Driver
+	// Snapshot creates the volume with the given name as a copy of the
+	// content of the volume. It returns ErrSnapshotNotSupported if the
+	// driver turns out not to support snapshots.
+	Snapshot(vol Volume, name string, opts map[string]string) (Volume, error)
 }
 
 // Volume is a place to store data. It is backed by a specific driver, and can be mounted.
@@ -77,6 +96,17 @@ type ScopedVolume interface {
 	Volume
 }
 
+// UsageVolume wraps a volume able to report its size on disk and its
+// creation time without being mounted
+type UsageVolume interface {
+	// Size returns the size of the data of the volume in bytes, or -1 if
+	// it cannot be known without mounting the volume.
+	Size() (int64, error)
+	// CreatedAt returns the time the volume was created.
+	CreatedAt() (time.Time, error)
+	Volume
+}
+
 // MountPoint is the intersection point between a volume and a container. It
 // specifies which volume is to be used and where inside a container it should
 // be mounted.
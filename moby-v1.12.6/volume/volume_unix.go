@@ -4,8 +4,11 @@ package volume
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/engine-api/types/mount"
 )
 
 // read-write modes
@@ -122,6 +125,38 @@ func ParseMountSpec(spec, volumeDriver string) (*MountPoint, error) {
 	return mp, nil
 }
 
+// validateMountTarget checks the destination of a mount in the container.
+func validateMountTarget(target string) error {
+	if !filepath.IsAbs(target) {
+		return fmt.Errorf("invalid mount target, must be an absolute path: %s", target)
+	}
+	if filepath.Clean(target) == "/" {
+		return fmt.Errorf("invalid mount target, destination can't be '/'")
+	}
+	return nil
+}
+
+// ConvertTmpfsOptions returns the mount data of a tmpfs mount with the
+// given options, in the format of the --tmpfs flag.
+func ConvertTmpfsOptions(opt *mount.TmpfsOptions, readOnly bool) string {
+	var rawOpts []string
+	if readOnly {
+		rawOpts = append(rawOpts, "ro")
+	}
+	if opt != nil && opt.Mode != 0 {
+		mode := opt.Mode.Perm()
+		if opt.Mode&os.ModeSticky != 0 {
+			mode |= 01000
+		}
+		rawOpts = append(rawOpts, fmt.Sprintf("mode=%o", mode))
+	}
+	if opt != nil && opt.SizeBytes != 0 {
+		// The size is given in kilobytes, rounded up.
+		rawOpts = append(rawOpts, fmt.Sprintf("size=%dk", (opt.SizeBytes+1023)/1024))
+	}
+	return strings.Join(rawOpts, ",")
+}
+
 // ParseVolumeSource parses the origin sources that's mounted into the container.
 // It returns a name and a source. It looks to see if the spec passed in
 // is an absolute file. If it is, it assumes the spec is a source. If not,
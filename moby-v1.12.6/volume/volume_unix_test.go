@@ -0,0 +1,29 @@
+// +build linux freebsd darwin solaris
+
+package volume
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/engine-api/types/mount"
+)
+
+func TestConvertTmpfsOptions(t *testing.T) {
+	cases := []struct {
+		opt      *mount.TmpfsOptions
+		readOnly bool
+		expected string
+	}{
+		{nil, false, ""},
+		{nil, true, "ro"},
+		{&mount.TmpfsOptions{SizeBytes: 64 * 1024 * 1024}, false, "size=65536k"},
+		{&mount.TmpfsOptions{SizeBytes: 1000}, false, "size=1k"},
+		{&mount.TmpfsOptions{Mode: 0700 | os.ModeSticky}, true, "ro,mode=1700"},
+	}
+	for _, c := range cases {
+		if data := ConvertTmpfsOptions(c.opt, c.readOnly); data != c.expected {
+			t.Fatalf("expected %q for %+v, got %q", c.expected, c.opt, data)
+		}
+	}
+}
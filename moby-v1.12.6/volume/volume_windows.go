@@ -181,6 +181,14 @@ func ParseMountSpec(spec string, volumeDriver string) (*MountPoint, error) {
 	return mp, nil
 }
 
+// validateMountTarget checks the destination of a mount in the container.
+func validateMountTarget(target string) error {
+	if !filepath.IsAbs(target) {
+		return fmt.Errorf("invalid mount target, must be an absolute path: %s", target)
+	}
+	return nil
+}
+
 // IsVolumeNameValid checks a volume name in a platform specific manner.
 func IsVolumeNameValid(name string) (bool, error) {
 	nameExp := regexp.MustCompile(`^` + RXName + `$`)